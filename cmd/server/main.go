@@ -2,6 +2,8 @@ package main
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"log"
 	"net/http"
 	"os"
@@ -14,6 +16,7 @@ import (
 	"github.com/vivekanand/labour-thekedar-backend/internal/database"
 	"github.com/vivekanand/labour-thekedar-backend/internal/handler"
 	"github.com/vivekanand/labour-thekedar-backend/internal/middleware"
+	"github.com/vivekanand/labour-thekedar-backend/internal/models"
 	"github.com/vivekanand/labour-thekedar-backend/internal/repository"
 	"github.com/vivekanand/labour-thekedar-backend/internal/service"
 	"github.com/vivekanand/labour-thekedar-backend/pkg/otp"
@@ -40,33 +43,87 @@ func main() {
 	}
 	defer db.Close()
 
-	// Initialize OTP provider (mock for development)
-	otpProvider := otp.NewMockProvider(true)
+	// Initialize OTP provider, with codes shared across instances through Postgres
+	otpStore := repository.NewOTPRepository(db.Pool)
+	otpProvider, err := newOTPProvider(cfg, otpStore)
+	if err != nil {
+		log.Fatalf("Failed to initialize OTP provider: %v", err)
+	}
+
+	// Background jobs stop when the server shuts down
+	jobsCtx, stopJobs := context.WithCancel(context.Background())
+	defer stopJobs()
+	go otp.RunCleanup(jobsCtx, otpStore, time.Minute)
 
 	// Initialize repositories
 	userRepo := repository.NewUserRepository(db.Pool)
+	authEventRepo := repository.NewAuthEventRepository(db.Pool)
+	refreshTokenRepo := repository.NewRefreshTokenRepository(db.Pool)
+	sessionRepo := repository.NewSessionRepository(db.Pool)
+	orgRepo := repository.NewOrganisationRepository(db.Pool)
 	projectRepo := repository.NewProjectRepository(db.Pool)
 	labourRepo := repository.NewLabourRepository(db.Pool)
 	workDayRepo := repository.NewWorkDayRepository(db.Pool)
 	paymentRepo := repository.NewPaymentRepository(db.Pool)
+	payrollRepo := repository.NewPayrollRepository(db.Pool)
+	auditRepo := repository.NewAuditRepository(db.Pool)
+	budgetRepo := repository.NewBudgetRepository(db.Pool)
+	billingRepo := repository.NewBillingRepository(db.Pool)
+	transactor := repository.NewTransactor(db.Pool)
 
 	// Initialize services
-	authService := service.NewAuthService(userRepo, otpProvider, cfg.JWTSecret)
-	projectService := service.NewProjectService(projectRepo, labourRepo)
-	labourService := service.NewLabourService(labourRepo)
-	workDayService := service.NewWorkDayService(workDayRepo, labourRepo)
-	paymentService := service.NewPaymentService(paymentRepo, labourRepo)
+	authService := service.NewAuthService(userRepo, authEventRepo, refreshTokenRepo, sessionRepo, labourRepo, otpProvider, cfg.JWTSecret, service.OTPLimits{
+		ResendCooldown:   cfg.OTPResendCooldown,
+		Window:           cfg.OTPRateLimitWindow,
+		SendsPerPhone:    cfg.OTPSendsPerPhone,
+		SendsPerIP:       cfg.OTPSendsPerIP,
+		VerifiesPerPhone: cfg.OTPVerifiesPerPhone,
+		VerifiesPerIP:    cfg.OTPVerifiesPerIP,
+	})
+	auditService := service.NewAuditService(auditRepo, transactor)
+	budgetService := service.NewBudgetService(budgetRepo, projectRepo, cfg.BudgetAlertThresholds)
+	orgService := service.NewOrganisationService(orgRepo)
+	projectService := service.NewProjectService(projectRepo, labourRepo, orgRepo, userRepo, auditService)
+	labourService := service.NewLabourService(labourRepo, projectRepo, orgRepo, paymentRepo, auditService)
+	workDayService := service.NewWorkDayService(workDayRepo, labourRepo, projectRepo, budgetService, auditService)
+	paymentService := service.NewPaymentService(paymentRepo, labourRepo, projectRepo, auditService)
+	payrollService := service.NewPayrollService(payrollRepo, projectRepo, orgRepo, auditService)
+	billingService := service.NewBillingService(billingRepo, projectRepo, labourRepo, auditService)
+	selfService := service.NewSelfService(labourRepo, workDayRepo, paymentRepo)
+
+	go runEvery(jobsCtx, 10*time.Minute, func(ctx context.Context) {
+		if _, err := authService.PruneAuthEvents(ctx); err != nil {
+			log.Printf("Failed to prune auth events: %v", err)
+		}
+	})
+	go runEvery(jobsCtx, time.Hour, func(ctx context.Context) {
+		if _, err := authService.PruneSessions(ctx); err != nil {
+			log.Printf("Failed to prune sessions: %v", err)
+		}
+	})
 
 	// Initialize handlers
 	authHandler := handler.NewAuthHandler(authService)
+	orgHandler := handler.NewOrganisationHandler(orgService)
 	projectHandler := handler.NewProjectHandler(projectService)
 	labourHandler := handler.NewLabourHandler(labourService, projectService)
 	workDayHandler := handler.NewWorkDayHandler(workDayService, projectService)
-	paymentHandler := handler.NewPaymentHandler(paymentService, projectService)
+	paymentHandler := handler.NewPaymentHandler(paymentService, projectService, labourService)
+	payrollHandler := handler.NewPayrollHandler(payrollService, projectService, orgService)
+	selfHandler := handler.NewSelfHandler(selfService)
+	auditHandler := handler.NewAuditHandler(auditService, projectService)
+	budgetHandler := handler.NewBudgetHandler(budgetService)
+	billingHandler := handler.NewBillingHandler(billingService, projectService)
 
 	// Setup router
 	r := gin.Default()
 
+	// Only trust X-Forwarded-For from configured proxies, or clients could pick
+	// their own IP and escape the per-IP OTP limits
+	if err := r.SetTrustedProxies(cfg.TrustedProxies); err != nil {
+		log.Fatalf("Invalid trusted proxies: %v", err)
+	}
+
 	// Add CORS middleware
 	r.Use(corsMiddleware())
 
@@ -81,47 +138,143 @@ func main() {
 		auth.POST("/send-otp", authHandler.SendOTP)
 		auth.POST("/verify-otp", authHandler.VerifyOTP)
 		auth.POST("/refresh", authHandler.RefreshToken)
+		auth.POST("/logout", authHandler.Logout)
+	}
+
+	// Labour auth routes (public). Labours get a read-only token for /me.
+	labourAuth := r.Group("/labour-auth")
+	{
+		labourAuth.POST("/send-otp", authHandler.SendLabourOTP)
+		labourAuth.POST("/verify-otp", authHandler.VerifyLabourOTP)
+	}
+
+	// Labour self-service routes
+	me := r.Group("/me")
+	me.Use(middleware.LabourAuthMiddleware(authService))
+	{
+		me.GET("", selfHandler.Get)
+		me.GET("/work-days", selfHandler.ListWorkDays)
+		me.GET("/payments", selfHandler.ListPayments)
+		me.GET("/balance", selfHandler.GetBalance)
 	}
 
 	// Protected routes
 	protected := r.Group("")
 	protected.Use(middleware.AuthMiddleware(authService))
 	{
-		// Projects
+		protected.POST("/auth/logout-all", authHandler.LogoutAll)
+		protected.GET("/auth/sessions", authHandler.ListSessions)
+		protected.DELETE("/auth/sessions/:id", authHandler.RevokeSession)
+
+		// Organisations
+		organisations := protected.Group("/organisations")
+		{
+			organisations.GET("", orgHandler.List)
+			organisations.POST("", orgHandler.Create)
+			organisations.GET("/:id/members", orgHandler.ListMembers)
+			organisations.PUT("/:id/members/:user_id", orgHandler.UpdateMember)
+			organisations.DELETE("/:id/members/:user_id", orgHandler.RemoveMember)
+			organisations.GET("/:id/invitations", orgHandler.ListInvitations)
+			organisations.POST("/:id/invitations", orgHandler.Invite)
+			organisations.DELETE("/:id/invitations/:invitation_id", orgHandler.CancelInvitation)
+			organisations.GET("/:id/payroll-runs", payrollHandler.ListByOrganisation)
+		}
+
+		// Invitations addressed to the caller's phone
+		invitations := protected.Group("/invitations")
+		{
+			invitations.GET("", orgHandler.ListMyInvitations)
+			invitations.POST("/:id/accept", orgHandler.AcceptInvitation)
+		}
+
+		// Projects. Routes under /:id load the project after checking the permission.
+		projectAccess := func(perm models.Permission) gin.HandlerFunc {
+			return middleware.RequireProjectPermission(projectService, perm)
+		}
+		trashedProjectAccess := func(perm models.Permission) gin.HandlerFunc {
+			return middleware.RequireTrashedProjectPermission(projectService, perm)
+		}
 		projects := protected.Group("/projects")
 		{
 			projects.GET("", projectHandler.List)
 			projects.POST("", projectHandler.Create)
-			projects.GET("/:id", projectHandler.Get)
-			projects.PUT("/:id", projectHandler.Update)
-			projects.DELETE("/:id", projectHandler.Delete)
+			projects.GET("/:id", projectAccess(models.PermProjectsRead), projectHandler.Get)
+			projects.PUT("/:id", projectAccess(models.PermProjectsWrite), projectHandler.Update)
+			projects.DELETE("/:id", projectAccess(models.PermProjectsWrite), projectHandler.Delete)
+
+			// Project trash. Only owners may purge.
+			projects.GET("/trash", projectHandler.ListTrash)
+			projects.POST("/:id/restore", trashedProjectAccess(models.PermProjectsWrite), projectHandler.Restore)
+			projects.POST("/:id/purge", trashedProjectAccess(models.PermTrashPurge), projectHandler.Purge)
+
+			// Project members
+			projects.GET("/:id/members", projectAccess(models.PermMembersManage), projectHandler.ListMembers)
+			projects.POST("/:id/members", projectAccess(models.PermMembersManage), projectHandler.AddMember)
+			projects.DELETE("/:id/members/:user_id", projectAccess(models.PermMembersManage), projectHandler.RemoveMember)
 
 			// Project labours
-			projects.GET("/:id/labours", labourHandler.ListByProject)
-			projects.POST("/:id/labours", labourHandler.AssignToProject)
-			projects.DELETE("/:id/labours/:labour_id", labourHandler.RemoveFromProject)
+			projects.GET("/:id/labours", projectAccess(models.PermLaboursRead), labourHandler.ListByProject)
+			projects.POST("/:id/labours", projectAccess(models.PermLaboursWrite), labourHandler.AssignToProject)
+			projects.DELETE("/:id/labours/:labour_id", projectAccess(models.PermLaboursWrite), labourHandler.RemoveFromProject)
 
 			// Project attendance
-			projects.GET("/:id/attendance", workDayHandler.List)
-			projects.POST("/:id/attendance", workDayHandler.Create)
+			projects.GET("/:id/attendance", projectAccess(models.PermAttendanceRead), workDayHandler.List)
+			projects.POST("/:id/attendance", projectAccess(models.PermAttendanceWrite), workDayHandler.Create)
+			projects.POST("/:id/attendance/bulk", projectAccess(models.PermAttendanceWrite), workDayHandler.BulkUpsert)
 
 			// Project payments
-			projects.GET("/:id/payments", paymentHandler.ListByProject)
-			projects.POST("/:id/payments", paymentHandler.Create)
+			projects.GET("/:id/payments", projectAccess(models.PermPaymentsRead), paymentHandler.ListByProject)
+			projects.POST("/:id/payments", projectAccess(models.PermPaymentsWrite), paymentHandler.Create)
 
 			// Labour balance in project
-			projects.GET("/:id/labours/:labour_id/balance", paymentHandler.GetBalance)
+			projects.GET("/:id/labours/:labour_id/balance", projectAccess(models.PermPaymentsRead), paymentHandler.GetBalance)
+			projects.GET("/:id/balances", projectAccess(models.PermPaymentsRead), paymentHandler.ListBalances)
+
+			// Project period closing. Only owners may reopen.
+			projects.POST("/:id/close", projectAccess(models.PermPeriodsClose), projectHandler.ClosePeriod)
+			projects.POST("/:id/reopen", projectAccess(models.PermPeriodsReopen), projectHandler.ReopenPeriod)
+			projects.GET("/:id/period-events", projectAccess(models.PermPaymentsRead), projectHandler.ListPeriodEvents)
+
+			// Project labour budget
+			projects.GET("/:id/budget", projectAccess(models.PermBillingRead), budgetHandler.Get)
+
+			// Project audit log
+			projects.GET("/:id/audit", projectAccess(models.PermAuditRead), auditHandler.ListByProject)
+
+			// Project payroll runs
+			projects.GET("/:id/payroll-runs", projectAccess(models.PermPaymentsRead), payrollHandler.ListByProject)
+
+			// Client billing
+			projects.GET("/:id/billing-rates", projectAccess(models.PermBillingRead), billingHandler.ListRates)
+			projects.POST("/:id/billing-rates", projectAccess(models.PermBillingWrite), billingHandler.SetRate)
+			projects.DELETE("/:id/billing-rates/:rate_id", projectAccess(models.PermBillingWrite), billingHandler.DeleteRate)
+			projects.GET("/:id/invoices", projectAccess(models.PermBillingRead), billingHandler.ListInvoices)
+			projects.POST("/:id/invoices", projectAccess(models.PermBillingWrite), billingHandler.CreateInvoice)
 		}
 
-		// Labours
+		// Labours. Routes under /:id load the labour after checking the permission.
+		labourAccess := func(perm models.Permission) gin.HandlerFunc {
+			return middleware.RequireLabourPermission(labourService, perm)
+		}
+		trashedLabourAccess := func(perm models.Permission) gin.HandlerFunc {
+			return middleware.RequireTrashedLabourPermission(labourService, perm)
+		}
 		labours := protected.Group("/labours")
 		{
 			labours.GET("", labourHandler.List)
 			labours.POST("", labourHandler.Create)
-			labours.GET("/:id", labourHandler.Get)
-			labours.PUT("/:id", labourHandler.Update)
-			labours.DELETE("/:id", labourHandler.Delete)
-			labours.GET("/:id/payments", paymentHandler.ListByLabour)
+			labours.GET("/:id", labourAccess(models.PermLaboursRead), labourHandler.Get)
+			labours.PUT("/:id", labourAccess(models.PermLaboursWrite), labourHandler.Update)
+			labours.DELETE("/:id", labourAccess(models.PermLaboursWrite), labourHandler.Delete)
+			labours.GET("/trash", labourHandler.ListTrash)
+			labours.POST("/:id/restore", trashedLabourAccess(models.PermLaboursWrite), labourHandler.Restore)
+			labours.POST("/:id/purge", trashedLabourAccess(models.PermTrashPurge), labourHandler.Purge)
+			labours.GET("/:id/payments", labourAccess(models.PermPaymentsRead), paymentHandler.ListByLabour)
+			labours.GET("/:id/ledger", labourAccess(models.PermPaymentsRead), paymentHandler.Ledger)
+			labours.GET("/:id/advances", labourAccess(models.PermPaymentsRead), paymentHandler.ListAdvances)
+			labours.GET("/:id/audit", labourAccess(models.PermAuditRead), auditHandler.ListByLabour)
+			labours.GET("/:id/wage-rates", labourAccess(models.PermLaboursRead), labourHandler.ListWageRates)
+			labours.POST("/:id/wage-rates", labourAccess(models.PermLaboursWrite), labourHandler.CreateWageRate)
 		}
 
 		// Attendance (for update/delete by ID)
@@ -134,7 +287,29 @@ func main() {
 		// Payments (for delete by ID)
 		payments := protected.Group("/payments")
 		{
-			payments.DELETE("/:id", paymentHandler.Delete)
+			payments.PUT("/:id", paymentHandler.Update)
+			payments.POST("/:id/void", paymentHandler.Void)
+			payments.PUT("/:id/recovery", paymentHandler.SetRecovery)
+		}
+
+		// Payroll runs
+		payrollRuns := protected.Group("/payroll-runs")
+		{
+			payrollRuns.POST("", payrollHandler.Create)
+			payrollRuns.GET("/:id", payrollHandler.Get)
+			payrollRuns.DELETE("/:id", payrollHandler.Delete)
+			payrollRuns.POST("/:id/approve", payrollHandler.Approve)
+			payrollRuns.PUT("/:id/items/:item_id", payrollHandler.UpdateItem)
+			payrollRuns.DELETE("/:id/items/:item_id", payrollHandler.DeleteItem)
+		}
+
+		// Invoices
+		invoices := protected.Group("/invoices")
+		{
+			invoices.GET("/:id", billingHandler.GetInvoice)
+			invoices.DELETE("/:id", billingHandler.DeleteInvoice)
+			invoices.POST("/:id/send", billingHandler.SendInvoice)
+			invoices.POST("/:id/receipts", billingHandler.AddReceipt)
 		}
 	}
 
@@ -161,6 +336,7 @@ func main() {
 	<-quit
 
 	log.Println("Shutting down server...")
+	stopJobs()
 
 	// Graceful shutdown with timeout
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
@@ -173,6 +349,53 @@ func main() {
 	log.Println("Server exited properly")
 }
 
+// runEvery calls job every interval until ctx is done
+func runEvery(ctx context.Context, interval time.Duration, job func(ctx context.Context)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			job(ctx)
+		}
+	}
+}
+
+// newOTPProvider builds the OTP provider selected by OTP_PROVIDER
+func newOTPProvider(cfg *config.Config, store otp.Store) (otp.Provider, error) {
+	switch cfg.OTPProvider {
+	case "mock":
+		if cfg.GinMode == gin.ReleaseMode {
+			return nil, errors.New("mock OTP provider accepts a fixed code and cannot be used with GIN_MODE=release")
+		}
+		log.Println("WARNING: using mock OTP provider, every login accepts 123456")
+		return otp.NewMockProviderWithStore(store, true), nil
+	case "twilio":
+		if cfg.TwilioAccountSID == "" || cfg.TwilioAuthToken == "" || cfg.TwilioFrom == "" {
+			return nil, errors.New("twilio requires TWILIO_ACCOUNT_SID, TWILIO_AUTH_TOKEN and TWILIO_FROM")
+		}
+		sender := otp.NewTwilioSender(cfg.TwilioAccountSID, cfg.TwilioAuthToken, cfg.TwilioFrom)
+		return otp.NewSMSProvider(sender, store, cfg.JWTSecret), nil
+	case "msg91":
+		if cfg.MSG91AuthKey == "" || cfg.MSG91TemplateID == "" {
+			return nil, errors.New("msg91 requires MSG91_AUTH_KEY and MSG91_TEMPLATE_ID")
+		}
+		sender := otp.NewMSG91Sender(cfg.MSG91AuthKey, cfg.MSG91TemplateID)
+		return otp.NewSMSProvider(sender, store, cfg.JWTSecret), nil
+	case "webhook":
+		if cfg.OTPWebhookURL == "" {
+			return nil, errors.New("webhook requires OTP_WEBHOOK_URL")
+		}
+		sender := otp.NewWebhookSender(cfg.OTPWebhookURL, cfg.OTPWebhookSecret)
+		return otp.NewSMSProvider(sender, store, cfg.JWTSecret), nil
+	default:
+		return nil, fmt.Errorf("unknown OTP_PROVIDER %q, use mock, twilio, msg91 or webhook", cfg.OTPProvider)
+	}
+}
+
 func corsMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		c.Header("Access-Control-Allow-Origin", "*")
@@ -3,6 +3,8 @@ package config
 import (
 	"os"
 	"strconv"
+	"strings"
+	"time"
 )
 
 // Config holds all configuration for the application
@@ -12,6 +14,31 @@ type Config struct {
 	ServerPort  string
 	AdminPort   string
 	GinMode     string
+
+	// Addresses or CIDRs of reverse proxies whose X-Forwarded-For is trusted
+	// for the client IP; empty trusts none
+	TrustedProxies []string
+
+	// OTP delivery: mock, twilio, msg91 or webhook
+	OTPProvider      string
+	TwilioAccountSID string
+	TwilioAuthToken  string
+	TwilioFrom       string // Phone number or messaging service SID
+	MSG91AuthKey     string
+	MSG91TemplateID  string
+	OTPWebhookURL    string
+	OTPWebhookSecret string
+
+	// OTP rate limits; counts apply per rate-limit window
+	OTPResendCooldown   time.Duration
+	OTPRateLimitWindow  time.Duration
+	OTPSendsPerPhone    int
+	OTPSendsPerIP       int
+	OTPVerifiesPerPhone int
+	OTPVerifiesPerIP    int
+
+	// Percentages of a project's labour budget at which alerts are raised
+	BudgetAlertThresholds []int
 }
 
 // Load loads configuration from environment variables
@@ -22,6 +49,26 @@ func Load() *Config {
 		ServerPort:  getEnv("SERVER_PORT", "8080"),
 		AdminPort:   getEnv("ADMIN_PORT", "9033"),
 		GinMode:     getEnv("GIN_MODE", "debug"),
+
+		TrustedProxies: getEnvList("TRUSTED_PROXIES"),
+
+		OTPProvider:      getEnv("OTP_PROVIDER", "mock"),
+		TwilioAccountSID: getEnv("TWILIO_ACCOUNT_SID", ""),
+		TwilioAuthToken:  getEnv("TWILIO_AUTH_TOKEN", ""),
+		TwilioFrom:       getEnv("TWILIO_FROM", ""),
+		MSG91AuthKey:     getEnv("MSG91_AUTH_KEY", ""),
+		MSG91TemplateID:  getEnv("MSG91_TEMPLATE_ID", ""),
+		OTPWebhookURL:    getEnv("OTP_WEBHOOK_URL", ""),
+		OTPWebhookSecret: getEnv("OTP_WEBHOOK_SECRET", ""),
+
+		OTPResendCooldown:   time.Duration(getEnvInt("OTP_RESEND_COOLDOWN_SECONDS", 60)) * time.Second,
+		OTPRateLimitWindow:  time.Duration(getEnvInt("OTP_RATE_LIMIT_WINDOW_MINUTES", 60)) * time.Minute,
+		OTPSendsPerPhone:    getEnvInt("OTP_SENDS_PER_PHONE", 5),
+		OTPSendsPerIP:       getEnvInt("OTP_SENDS_PER_IP", 20),
+		OTPVerifiesPerPhone: getEnvInt("OTP_VERIFIES_PER_PHONE", 15),
+		OTPVerifiesPerIP:    getEnvInt("OTP_VERIFIES_PER_IP", 50),
+
+		BudgetAlertThresholds: getEnvInts("BUDGET_ALERT_THRESHOLDS", []int{80, 100}),
 	}
 }
 
@@ -42,3 +89,33 @@ func getEnvInt(key string, defaultValue int) int {
 	}
 	return defaultValue
 }
+
+// getEnvList gets a comma-separated environment variable as a list, nil if unset
+func getEnvList(key string) []string {
+	var list []string
+	for _, part := range strings.Split(os.Getenv(key), ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			list = append(list, part)
+		}
+	}
+	return list
+}
+
+// getEnvInts gets a comma-separated environment variable as positive ints or
+// returns a default value
+func getEnvInts(key string, defaultValue []int) []int {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+
+	var ints []int
+	for _, part := range strings.Split(value, ",") {
+		intVal, err := strconv.Atoi(strings.TrimSpace(part))
+		if err != nil || intVal <= 0 {
+			return defaultValue
+		}
+		ints = append(ints, intVal)
+	}
+	return ints
+}
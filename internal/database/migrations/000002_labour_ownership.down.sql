@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_labours_user_id;
+
+ALTER TABLE labours DROP COLUMN IF EXISTS user_id;
@@ -0,0 +1,16 @@
+-- Labours belong to the thekedar who manages them
+ALTER TABLE labours ADD COLUMN user_id UUID REFERENCES users(id) ON DELETE CASCADE;
+
+-- Backfill ownership from the earliest project each labour was assigned to.
+-- Labours that were never assigned to a project have no owner and stay hidden.
+UPDATE labours l
+SET user_id = owner.user_id
+FROM (
+    SELECT DISTINCT ON (pl.labour_id) pl.labour_id, p.user_id
+    FROM project_labours pl
+    INNER JOIN projects p ON p.id = pl.project_id
+    ORDER BY pl.labour_id, pl.assigned_at ASC
+) owner
+WHERE l.id = owner.labour_id;
+
+CREATE INDEX idx_labours_user_id ON labours(user_id);
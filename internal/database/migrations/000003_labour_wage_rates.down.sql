@@ -0,0 +1,3 @@
+DROP VIEW IF EXISTS work_day_earnings;
+
+DROP TABLE IF EXISTS labour_wage_rates;
@@ -0,0 +1,51 @@
+-- Effective-dated wage history (project_id set = project-specific override)
+CREATE TABLE labour_wage_rates (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    labour_id UUID NOT NULL REFERENCES labours(id) ON DELETE CASCADE,
+    project_id UUID REFERENCES projects(id) ON DELETE CASCADE,
+    daily_wage DECIMAL(10, 2) NOT NULL,
+    effective_from DATE NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_labour_wage_rates_labour_id ON labour_wage_rates(labour_id, effective_from);
+
+-- Seed the history with each labour's current wage, effective from their first work day
+INSERT INTO labour_wage_rates (labour_id, daily_wage, effective_from)
+SELECT l.id, l.daily_wage, LEAST(l.created_at::date, COALESCE(MIN(wd.work_date), l.created_at::date))
+FROM labours l
+LEFT JOIN work_days wd ON wd.labour_id = l.id
+GROUP BY l.id;
+
+-- Earnings per work day at the rate in force on work_date.
+-- A project-specific rate wins over the labour's general rate.
+-- full_day = 1.0 * daily_wage, half_day = 0.5 * daily_wage, absent = 0
+CREATE VIEW work_day_earnings AS
+SELECT
+    wd.id,
+    wd.project_id,
+    wd.labour_id,
+    wd.work_date,
+    wd.status,
+    CASE wd.status
+        WHEN 'full_day' THEN 1.0
+        WHEN 'half_day' THEN 0.5
+        ELSE 0
+    END AS day_fraction,
+    COALESCE(rate.daily_wage, l.daily_wage) AS daily_wage,
+    CASE wd.status
+        WHEN 'full_day' THEN 1.0
+        WHEN 'half_day' THEN 0.5
+        ELSE 0
+    END * COALESCE(rate.daily_wage, l.daily_wage) AS amount
+FROM work_days wd
+INNER JOIN labours l ON l.id = wd.labour_id
+LEFT JOIN LATERAL (
+    SELECT r.daily_wage
+    FROM labour_wage_rates r
+    WHERE r.labour_id = wd.labour_id
+      AND (r.project_id IS NULL OR r.project_id = wd.project_id)
+      AND r.effective_from <= wd.work_date
+    ORDER BY r.project_id IS NULL, r.effective_from DESC, r.created_at DESC
+    LIMIT 1
+) rate ON TRUE;
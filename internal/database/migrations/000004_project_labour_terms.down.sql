@@ -0,0 +1,34 @@
+DROP VIEW IF EXISTS work_day_earnings;
+
+CREATE VIEW work_day_earnings AS
+SELECT
+    wd.id,
+    wd.project_id,
+    wd.labour_id,
+    wd.work_date,
+    wd.status,
+    CASE wd.status
+        WHEN 'full_day' THEN 1.0
+        WHEN 'half_day' THEN 0.5
+        ELSE 0
+    END AS day_fraction,
+    COALESCE(rate.daily_wage, l.daily_wage) AS daily_wage,
+    CASE wd.status
+        WHEN 'full_day' THEN 1.0
+        WHEN 'half_day' THEN 0.5
+        ELSE 0
+    END * COALESCE(rate.daily_wage, l.daily_wage) AS amount
+FROM work_days wd
+INNER JOIN labours l ON l.id = wd.labour_id
+LEFT JOIN LATERAL (
+    SELECT r.daily_wage
+    FROM labour_wage_rates r
+    WHERE r.labour_id = wd.labour_id
+      AND (r.project_id IS NULL OR r.project_id = wd.project_id)
+      AND r.effective_from <= wd.work_date
+    ORDER BY r.project_id IS NULL, r.effective_from DESC, r.created_at DESC
+    LIMIT 1
+) rate ON TRUE;
+
+ALTER TABLE project_labours DROP COLUMN IF EXISTS role;
+ALTER TABLE project_labours DROP COLUMN IF EXISTS daily_wage;
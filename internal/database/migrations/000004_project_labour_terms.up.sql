@@ -0,0 +1,50 @@
+-- Project-specific terms for an assigned labour
+ALTER TABLE project_labours ADD COLUMN daily_wage DECIMAL(10, 2);
+ALTER TABLE project_labours ADD COLUMN role VARCHAR(100) NOT NULL DEFAULT '';
+
+-- Rate precedence on a work date:
+--   1. project-specific rate history
+--   2. the assignment's project daily wage
+--   3. the labour's general rate history
+--   4. the labour's current daily wage
+DROP VIEW IF EXISTS work_day_earnings;
+
+CREATE VIEW work_day_earnings AS
+SELECT
+    wd.id,
+    wd.project_id,
+    wd.labour_id,
+    wd.work_date,
+    wd.status,
+    CASE wd.status
+        WHEN 'full_day' THEN 1.0
+        WHEN 'half_day' THEN 0.5
+        ELSE 0
+    END AS day_fraction,
+    COALESCE(project_rate.daily_wage, pl.daily_wage, general_rate.daily_wage, l.daily_wage) AS daily_wage,
+    CASE wd.status
+        WHEN 'full_day' THEN 1.0
+        WHEN 'half_day' THEN 0.5
+        ELSE 0
+    END * COALESCE(project_rate.daily_wage, pl.daily_wage, general_rate.daily_wage, l.daily_wage) AS amount
+FROM work_days wd
+INNER JOIN labours l ON l.id = wd.labour_id
+LEFT JOIN project_labours pl ON pl.project_id = wd.project_id AND pl.labour_id = wd.labour_id
+LEFT JOIN LATERAL (
+    SELECT r.daily_wage
+    FROM labour_wage_rates r
+    WHERE r.labour_id = wd.labour_id
+      AND r.project_id = wd.project_id
+      AND r.effective_from <= wd.work_date
+    ORDER BY r.effective_from DESC, r.created_at DESC
+    LIMIT 1
+) project_rate ON TRUE
+LEFT JOIN LATERAL (
+    SELECT r.daily_wage
+    FROM labour_wage_rates r
+    WHERE r.labour_id = wd.labour_id
+      AND r.project_id IS NULL
+      AND r.effective_from <= wd.work_date
+    ORDER BY r.effective_from DESC, r.created_at DESC
+    LIMIT 1
+) general_rate ON TRUE;
@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS idx_otp_codes_phone_created_at;
+
+DELETE FROM otp_codes;
+
+ALTER TABLE otp_codes DROP COLUMN IF EXISTS attempts;
+ALTER TABLE otp_codes ALTER COLUMN code_hash TYPE VARCHAR(10);
+ALTER TABLE otp_codes RENAME COLUMN code_hash TO code;
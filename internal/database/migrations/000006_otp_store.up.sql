@@ -0,0 +1,9 @@
+-- OTP codes are stored as HMAC hashes, never in plain text.
+-- Existing rows hold plain codes and are short-lived, so drop them.
+DELETE FROM otp_codes;
+
+ALTER TABLE otp_codes RENAME COLUMN code TO code_hash;
+ALTER TABLE otp_codes ALTER COLUMN code_hash TYPE VARCHAR(64);
+ALTER TABLE otp_codes ADD COLUMN attempts INT NOT NULL DEFAULT 0;
+
+CREATE INDEX idx_otp_codes_phone_created_at ON otp_codes(phone, created_at DESC);
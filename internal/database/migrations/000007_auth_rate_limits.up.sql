@@ -0,0 +1,12 @@
+-- OTP send and verify events, used for per-phone and per-IP rate limits
+CREATE TABLE auth_events (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    action VARCHAR(20) NOT NULL,
+    phone VARCHAR(20) NOT NULL,
+    ip VARCHAR(45) NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_auth_events_phone ON auth_events(action, phone, created_at);
+CREATE INDEX idx_auth_events_ip ON auth_events(action, ip, created_at);
+CREATE INDEX idx_auth_events_created_at ON auth_events(created_at);
@@ -0,0 +1,6 @@
+ALTER TABLE labours DROP COLUMN IF EXISTS organisation_id;
+ALTER TABLE projects DROP COLUMN IF EXISTS organisation_id;
+DROP TABLE IF EXISTS organisation_invitations;
+DROP TABLE IF EXISTS organisation_members;
+DROP TABLE IF EXISTS organisations;
+DROP TYPE IF EXISTS organisation_role;
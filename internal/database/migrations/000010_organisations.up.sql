@@ -0,0 +1,58 @@
+-- Organisations own projects and labours. Users join them as members with
+-- a role; projects.user_id and labours.user_id remain as the creator.
+CREATE TYPE organisation_role AS ENUM ('owner', 'supervisor', 'accountant', 'viewer');
+
+CREATE TABLE organisations (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    name VARCHAR(255) NOT NULL,
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE organisation_members (
+    organisation_id UUID NOT NULL REFERENCES organisations(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    role organisation_role NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (organisation_id, user_id)
+);
+
+CREATE INDEX idx_organisation_members_user_id ON organisation_members(user_id);
+
+CREATE TABLE organisation_invitations (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    organisation_id UUID NOT NULL REFERENCES organisations(id) ON DELETE CASCADE,
+    phone VARCHAR(20) NOT NULL,
+    role organisation_role NOT NULL,
+    invited_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    expires_at TIMESTAMPTZ NOT NULL,
+    accepted_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- One open invitation per phone per organisation
+CREATE UNIQUE INDEX idx_organisation_invitations_pending
+    ON organisation_invitations(organisation_id, phone) WHERE accepted_at IS NULL;
+CREATE INDEX idx_organisation_invitations_phone ON organisation_invitations(phone);
+
+ALTER TABLE projects ADD COLUMN organisation_id UUID REFERENCES organisations(id) ON DELETE CASCADE;
+ALTER TABLE labours ADD COLUMN organisation_id UUID REFERENCES organisations(id) ON DELETE CASCADE;
+
+-- Every existing user gets a personal organisation owning their data
+INSERT INTO organisations (name, created_by, created_at)
+SELECT COALESCE(NULLIF(name, ''), phone), id, created_at FROM users;
+
+INSERT INTO organisation_members (organisation_id, user_id, role, created_at)
+SELECT id, created_by, 'owner', created_at FROM organisations;
+
+UPDATE projects p SET organisation_id = o.id
+FROM organisations o WHERE o.created_by = p.user_id;
+
+UPDATE labours l SET organisation_id = o.id
+FROM organisations o WHERE o.created_by = l.user_id;
+
+ALTER TABLE projects ALTER COLUMN organisation_id SET NOT NULL;
+
+CREATE INDEX idx_projects_organisation_id ON projects(organisation_id);
+CREATE INDEX idx_labours_organisation_id ON labours(organisation_id);
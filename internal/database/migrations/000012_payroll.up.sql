@@ -0,0 +1,48 @@
+-- Payroll runs settle a period's earnings for one project, or every project
+-- in an organisation. Approving a run creates its daily_wage payments and
+-- locks attendance and payments in the period.
+CREATE TYPE payroll_status AS ENUM ('draft', 'approved');
+
+CREATE TABLE payroll_runs (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    organisation_id UUID NOT NULL REFERENCES organisations(id) ON DELETE CASCADE,
+    project_id UUID REFERENCES projects(id) ON DELETE CASCADE, -- NULL = every project in the organisation
+    period_start DATE NOT NULL,
+    period_end DATE NOT NULL,
+    status payroll_status NOT NULL DEFAULT 'draft',
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    approved_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    approved_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CHECK (period_end >= period_start)
+);
+
+CREATE INDEX idx_payroll_runs_organisation_id ON payroll_runs(organisation_id);
+CREATE INDEX idx_payroll_runs_project_id ON payroll_runs(project_id);
+
+-- One line per labour per project. net_amount = earned - advance_deduction + adjustment.
+CREATE TABLE payroll_items (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    payroll_run_id UUID NOT NULL REFERENCES payroll_runs(id) ON DELETE CASCADE,
+    project_id UUID NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+    labour_id UUID NOT NULL REFERENCES labours(id) ON DELETE CASCADE,
+    days_worked DECIMAL(6, 1) NOT NULL DEFAULT 0,
+    earned DECIMAL(12, 2) NOT NULL DEFAULT 0,
+    advance_deduction DECIMAL(12, 2) NOT NULL DEFAULT 0 CHECK (advance_deduction >= 0),
+    adjustment DECIMAL(12, 2) NOT NULL DEFAULT 0,
+    net_amount DECIMAL(12, 2) NOT NULL DEFAULT 0 CHECK (net_amount >= 0),
+    notes TEXT NOT NULL DEFAULT '',
+    payment_id UUID REFERENCES payments(id) ON DELETE SET NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (payroll_run_id, project_id, labour_id)
+);
+
+CREATE INDEX idx_payroll_items_labour_id ON payroll_items(labour_id);
+
+CREATE TRIGGER update_payroll_runs_updated_at BEFORE UPDATE ON payroll_runs
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+CREATE TRIGGER update_payroll_items_updated_at BEFORE UPDATE ON payroll_items
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
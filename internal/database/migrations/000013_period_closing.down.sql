@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS project_period_events;
+ALTER TABLE projects DROP COLUMN IF EXISTS closed_through;
@@ -0,0 +1,33 @@
+-- Attendance and payments dated on or before a project's closed_through date
+-- can't be changed. Closing and reopening are recorded in project_period_events.
+ALTER TABLE projects ADD COLUMN closed_through DATE;
+
+CREATE TABLE project_period_events (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    project_id UUID NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+    action VARCHAR(10) NOT NULL CHECK (action IN ('close', 'reopen')),
+    previous_closed_through DATE,
+    closed_through DATE,
+    reason TEXT NOT NULL DEFAULT '',
+    user_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_project_period_events_project_id ON project_period_events(project_id, created_at);
+
+-- Periods settled by approved payroll runs start out closed
+UPDATE projects p SET closed_through = settled.period_end
+FROM (
+    SELECT p.id, MAX(r.period_end) AS period_end
+    FROM projects p
+    INNER JOIN payroll_runs r ON r.organisation_id = p.organisation_id
+        AND (r.project_id IS NULL OR r.project_id = p.id)
+    WHERE r.status = 'approved'
+    GROUP BY p.id
+) settled
+WHERE p.id = settled.id;
+
+INSERT INTO project_period_events (project_id, action, closed_through, reason)
+SELECT id, 'close', closed_through, 'Settled by payroll'
+FROM projects
+WHERE closed_through IS NOT NULL;
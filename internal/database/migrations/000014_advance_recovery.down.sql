@@ -0,0 +1,5 @@
+DROP VIEW IF EXISTS advance_balances;
+DROP TABLE IF EXISTS advance_recoveries;
+ALTER TABLE payments DROP CONSTRAINT IF EXISTS payments_recovery_plan_check;
+ALTER TABLE payments DROP COLUMN IF EXISTS recovery_value;
+ALTER TABLE payments DROP COLUMN IF EXISTS recovery_type;
@@ -0,0 +1,64 @@
+-- Advances are loans recovered from later payroll runs. A recovery plan sets
+-- the instalment taken by each run; without one the advance is recovered in
+-- full as soon as there are earnings to cover it.
+ALTER TABLE payments ADD COLUMN recovery_type VARCHAR(20)
+    CHECK (recovery_type IN ('fixed', 'percentage'));
+ALTER TABLE payments ADD COLUMN recovery_value DECIMAL(12, 2);
+ALTER TABLE payments ADD CONSTRAINT payments_recovery_plan_check CHECK (
+    (recovery_type IS NULL AND recovery_value IS NULL)
+    OR (payment_type = 'advance' AND recovery_type IS NOT NULL AND recovery_value > 0)
+);
+
+-- What each payroll item recovered from each advance. Advances with
+-- recoveries can't be deleted.
+CREATE TABLE advance_recoveries (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    advance_id UUID NOT NULL REFERENCES payments(id) ON DELETE RESTRICT,
+    payroll_item_id UUID REFERENCES payroll_items(id) ON DELETE SET NULL,
+    amount DECIMAL(12, 2) NOT NULL CHECK (amount > 0),
+    recovered_on DATE NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_advance_recoveries_advance_id ON advance_recoveries(advance_id);
+
+CREATE VIEW advance_balances AS
+SELECT
+    p.id,
+    p.project_id,
+    p.labour_id,
+    p.amount,
+    p.payment_date,
+    p.recovery_type,
+    p.recovery_value,
+    p.notes,
+    p.created_at,
+    COALESCE(r.recovered, 0) AS recovered,
+    p.amount - COALESCE(r.recovered, 0) AS outstanding
+FROM payments p
+LEFT JOIN (
+    SELECT advance_id, SUM(amount) AS recovered
+    FROM advance_recoveries
+    GROUP BY advance_id
+) r ON r.advance_id = p.id
+WHERE p.payment_type = 'advance';
+
+-- Deductions already taken by approved payroll runs are applied to the
+-- oldest advances first
+INSERT INTO advance_recoveries (advance_id, amount, recovered_on)
+SELECT a.id, LEAST(a.amount, d.deducted - a.before), d.recovered_on
+FROM (
+    SELECT i.project_id, i.labour_id, SUM(i.advance_deduction) AS deducted, MAX(r.period_end) AS recovered_on
+    FROM payroll_items i
+    INNER JOIN payroll_runs r ON r.id = i.payroll_run_id
+    WHERE r.status = 'approved'
+    GROUP BY i.project_id, i.labour_id
+) d
+CROSS JOIN LATERAL (
+    SELECT id, amount,
+        COALESCE(SUM(amount) OVER (ORDER BY payment_date, created_at, id
+            ROWS BETWEEN UNBOUNDED PRECEDING AND 1 PRECEDING), 0) AS before
+    FROM payments
+    WHERE project_id = d.project_id AND labour_id = d.labour_id AND payment_type = 'advance'
+) a
+WHERE d.deducted > a.before;
@@ -0,0 +1,46 @@
+-- Enum values can't be dropped, so the type is rebuilt without them. Rolling
+-- back would lose deductions and refunds and change balances, so it is
+-- refused while any exist; they must be dealt with by hand first.
+DO $$
+BEGIN
+    IF EXISTS (SELECT 1 FROM payments WHERE payment_type::text IN ('deduction', 'refund')) THEN
+        RAISE EXCEPTION 'cannot roll back: payments of type deduction or refund exist';
+    END IF;
+END
+$$;
+
+DROP VIEW IF EXISTS advance_balances;
+ALTER TABLE payments DROP CONSTRAINT IF EXISTS payments_recovery_plan_check;
+ALTER TABLE payments ALTER COLUMN payment_type DROP DEFAULT;
+
+ALTER TYPE payment_type RENAME TO payment_type_old;
+CREATE TYPE payment_type AS ENUM ('advance', 'daily_wage', 'bonus');
+ALTER TABLE payments ALTER COLUMN payment_type TYPE payment_type USING payment_type::text::payment_type;
+DROP TYPE payment_type_old;
+
+ALTER TABLE payments ALTER COLUMN payment_type SET DEFAULT 'daily_wage';
+ALTER TABLE payments ADD CONSTRAINT payments_recovery_plan_check CHECK (
+    (recovery_type IS NULL AND recovery_value IS NULL)
+    OR (payment_type = 'advance' AND recovery_type IS NOT NULL AND recovery_value > 0)
+);
+
+CREATE VIEW advance_balances AS
+SELECT
+    p.id,
+    p.project_id,
+    p.labour_id,
+    p.amount,
+    p.payment_date,
+    p.recovery_type,
+    p.recovery_value,
+    p.notes,
+    p.created_at,
+    COALESCE(r.recovered, 0) AS recovered,
+    p.amount - COALESCE(r.recovered, 0) AS outstanding
+FROM payments p
+LEFT JOIN (
+    SELECT advance_id, SUM(amount) AS recovered
+    FROM advance_recoveries
+    GROUP BY advance_id
+) r ON r.advance_id = p.id
+WHERE p.payment_type = 'advance';
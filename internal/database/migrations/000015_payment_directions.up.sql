@@ -0,0 +1,5 @@
+-- Deductions (fines, damaged tools, canteen charges) settle what a labour is
+-- owed like any payment. Refunds are money the labour returned and add back
+-- to it. Amounts stay positive; the type gives the sign.
+ALTER TYPE payment_type ADD VALUE IF NOT EXISTS 'deduction';
+ALTER TYPE payment_type ADD VALUE IF NOT EXISTS 'refund';
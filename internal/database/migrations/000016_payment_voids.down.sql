@@ -0,0 +1,29 @@
+CREATE OR REPLACE VIEW advance_balances AS
+SELECT
+    p.id,
+    p.project_id,
+    p.labour_id,
+    p.amount,
+    p.payment_date,
+    p.recovery_type,
+    p.recovery_value,
+    p.notes,
+    p.created_at,
+    COALESCE(r.recovered, 0) AS recovered,
+    p.amount - COALESCE(r.recovered, 0) AS outstanding
+FROM payments p
+LEFT JOIN (
+    SELECT advance_id, SUM(amount) AS recovered
+    FROM advance_recoveries
+    GROUP BY advance_id
+) r ON r.advance_id = p.id
+WHERE p.payment_type = 'advance';
+
+DROP TRIGGER IF EXISTS update_payments_updated_at ON payments;
+ALTER TABLE payments ALTER COLUMN notes DROP NOT NULL;
+ALTER TABLE payments ALTER COLUMN notes DROP DEFAULT;
+ALTER TABLE payments DROP CONSTRAINT IF EXISTS payments_void_reason_check;
+ALTER TABLE payments DROP COLUMN IF EXISTS updated_at;
+ALTER TABLE payments DROP COLUMN IF EXISTS void_reason;
+ALTER TABLE payments DROP COLUMN IF EXISTS voided_by;
+ALTER TABLE payments DROP COLUMN IF EXISTS voided_at;
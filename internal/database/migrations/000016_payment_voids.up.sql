@@ -0,0 +1,36 @@
+-- Payments are corrected in place or voided instead of deleted. Voided
+-- payments stay on record but no longer count towards balances.
+ALTER TABLE payments ADD COLUMN voided_at TIMESTAMPTZ;
+ALTER TABLE payments ADD COLUMN voided_by UUID REFERENCES users(id) ON DELETE SET NULL;
+ALTER TABLE payments ADD COLUMN void_reason TEXT NOT NULL DEFAULT '';
+ALTER TABLE payments ADD COLUMN updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW();
+ALTER TABLE payments ADD CONSTRAINT payments_void_reason_check
+    CHECK (voided_at IS NULL OR void_reason <> '');
+
+UPDATE payments SET notes = '' WHERE notes IS NULL;
+ALTER TABLE payments ALTER COLUMN notes SET DEFAULT '';
+ALTER TABLE payments ALTER COLUMN notes SET NOT NULL;
+
+CREATE TRIGGER update_payments_updated_at BEFORE UPDATE ON payments
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+CREATE OR REPLACE VIEW advance_balances AS
+SELECT
+    p.id,
+    p.project_id,
+    p.labour_id,
+    p.amount,
+    p.payment_date,
+    p.recovery_type,
+    p.recovery_value,
+    p.notes,
+    p.created_at,
+    COALESCE(r.recovered, 0) AS recovered,
+    p.amount - COALESCE(r.recovered, 0) AS outstanding
+FROM payments p
+LEFT JOIN (
+    SELECT advance_id, SUM(amount) AS recovered
+    FROM advance_recoveries
+    GROUP BY advance_id
+) r ON r.advance_id = p.id
+WHERE p.payment_type = 'advance' AND p.voided_at IS NULL;
@@ -0,0 +1,32 @@
+-- Append-only record of every change to projects, labours, attendance,
+-- payments and payroll. There are no foreign keys so entries outlive the
+-- records and users they mention.
+CREATE TABLE audit_logs (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    actor_id UUID,
+    entity_type VARCHAR(30) NOT NULL,
+    entity_id UUID NOT NULL,
+    project_id UUID,
+    labour_id UUID,
+    action VARCHAR(20) NOT NULL,
+    before JSONB,
+    after JSONB,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_audit_logs_project_id ON audit_logs(project_id, created_at);
+CREATE INDEX idx_audit_logs_labour_id ON audit_logs(labour_id, created_at);
+CREATE INDEX idx_audit_logs_entity ON audit_logs(entity_type, entity_id);
+
+CREATE OR REPLACE FUNCTION prevent_audit_log_changes()
+RETURNS TRIGGER AS $$
+BEGIN
+    RAISE EXCEPTION 'audit_logs is append-only';
+END;
+$$ language 'plpgsql';
+
+CREATE TRIGGER audit_logs_append_only BEFORE UPDATE OR DELETE ON audit_logs
+    FOR EACH ROW EXECUTE FUNCTION prevent_audit_log_changes();
+
+CREATE TRIGGER audit_logs_no_truncate BEFORE TRUNCATE ON audit_logs
+    FOR EACH STATEMENT EXECUTE FUNCTION prevent_audit_log_changes();
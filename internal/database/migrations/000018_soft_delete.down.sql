@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_labours_trash;
+DROP INDEX IF EXISTS idx_projects_trash;
+ALTER TABLE labours DROP COLUMN IF EXISTS deleted_by;
+ALTER TABLE labours DROP COLUMN IF EXISTS deleted_at;
+ALTER TABLE projects DROP COLUMN IF EXISTS deleted_by;
+ALTER TABLE projects DROP COLUMN IF EXISTS deleted_at;
@@ -0,0 +1,12 @@
+-- Deleting a project or labour moves it to the trash instead of cascading
+-- over its work days and payments. Purging from the trash deletes for good.
+ALTER TABLE projects
+    ADD COLUMN deleted_at TIMESTAMPTZ,
+    ADD COLUMN deleted_by UUID REFERENCES users(id) ON DELETE SET NULL;
+
+ALTER TABLE labours
+    ADD COLUMN deleted_at TIMESTAMPTZ,
+    ADD COLUMN deleted_by UUID REFERENCES users(id) ON DELETE SET NULL;
+
+CREATE INDEX idx_projects_trash ON projects(organisation_id, deleted_at) WHERE deleted_at IS NOT NULL;
+CREATE INDEX idx_labours_trash ON labours(organisation_id, deleted_at) WHERE deleted_at IS NOT NULL;
@@ -0,0 +1,8 @@
+DROP INDEX IF EXISTS idx_projects_status;
+ALTER TABLE projects DROP CONSTRAINT IF EXISTS projects_dates_check;
+ALTER TABLE projects DROP COLUMN IF EXISTS labour_budget;
+ALTER TABLE projects DROP COLUMN IF EXISTS client_name;
+ALTER TABLE projects DROP COLUMN IF EXISTS site_address;
+ALTER TABLE projects DROP COLUMN IF EXISTS end_date;
+ALTER TABLE projects DROP COLUMN IF EXISTS start_date;
+ALTER TABLE projects DROP COLUMN IF EXISTS status;
@@ -0,0 +1,13 @@
+-- Projects move through planned, active, on_hold, completed and archived.
+-- Attendance and payments are refused on completed and archived projects.
+ALTER TABLE projects
+    ADD COLUMN status VARCHAR(20) NOT NULL DEFAULT 'active'
+        CHECK (status IN ('planned', 'active', 'on_hold', 'completed', 'archived')),
+    ADD COLUMN start_date DATE,
+    ADD COLUMN end_date DATE,
+    ADD COLUMN site_address TEXT NOT NULL DEFAULT '',
+    ADD COLUMN client_name VARCHAR(255) NOT NULL DEFAULT '',
+    ADD COLUMN labour_budget DECIMAL(12, 2) CHECK (labour_budget >= 0),
+    ADD CONSTRAINT projects_dates_check CHECK (end_date >= start_date);
+
+CREATE INDEX idx_projects_status ON projects(organisation_id, status) WHERE deleted_at IS NULL;
@@ -0,0 +1,13 @@
+-- An alert is raised once per threshold when a project's committed labour cost
+-- crosses that percentage of its budget. Changing the budget re-arms them.
+CREATE TABLE budget_alerts (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    project_id UUID NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+    threshold INTEGER NOT NULL CHECK (threshold > 0),
+    budget DECIMAL(12, 2) NOT NULL,
+    committed DECIMAL(12, 2) NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (project_id, threshold, budget)
+);
+
+CREATE INDEX idx_budget_alerts_project_id ON budget_alerts(project_id, created_at);
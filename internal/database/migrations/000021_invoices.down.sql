@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS invoice_receipts;
+DROP TABLE IF EXISTS invoice_items;
+DROP TABLE IF EXISTS invoices;
+DROP TYPE IF EXISTS invoice_status;
+DROP TABLE IF EXISTS billing_rates;
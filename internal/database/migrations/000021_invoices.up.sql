@@ -0,0 +1,78 @@
+-- Billing rates set what a project's client is charged for labour. A rate
+-- applies to one labour, to every labour in a role, or with neither to the
+-- whole project; the most specific one wins. It is either a fixed day rate,
+-- with overtime passed on at cost, or a markup on the wages earned.
+CREATE TABLE billing_rates (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    project_id UUID NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+    labour_id UUID REFERENCES labours(id) ON DELETE CASCADE,
+    role VARCHAR(100) NOT NULL DEFAULT '',
+    day_rate DECIMAL(10, 2) CHECK (day_rate > 0),
+    markup_percent DECIMAL(6, 2) CHECK (markup_percent >= 0),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT billing_rates_kind_check CHECK ((day_rate IS NULL) <> (markup_percent IS NULL)),
+    CONSTRAINT billing_rates_scope_check CHECK (labour_id IS NULL OR role = ''),
+    CONSTRAINT billing_rates_scope_key UNIQUE NULLS NOT DISTINCT (project_id, labour_id, role)
+);
+
+-- Invoices bill a project's attendance over a period. Drafts have no number;
+-- sending one assigns the next number in its organisation, so issued numbers
+-- have no gaps.
+CREATE TYPE invoice_status AS ENUM ('draft', 'sent', 'paid');
+
+CREATE TABLE invoices (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    organisation_id UUID NOT NULL REFERENCES organisations(id) ON DELETE CASCADE,
+    project_id UUID NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+    number INTEGER,
+    period_start DATE NOT NULL,
+    period_end DATE NOT NULL,
+    status invoice_status NOT NULL DEFAULT 'draft',
+    client_name VARCHAR(255) NOT NULL DEFAULT '',
+    total DECIMAL(12, 2) NOT NULL DEFAULT 0,
+    notes TEXT NOT NULL DEFAULT '',
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    sent_at TIMESTAMPTZ,
+    paid_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CHECK (period_end >= period_start),
+    CHECK (status = 'draft' OR number IS NOT NULL),
+    UNIQUE (organisation_id, number)
+);
+
+CREATE INDEX idx_invoices_project_id ON invoices(project_id, period_start);
+
+-- One line per labour for their days, and one for any overtime hours
+CREATE TABLE invoice_items (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    invoice_id UUID NOT NULL REFERENCES invoices(id) ON DELETE CASCADE,
+    labour_id UUID REFERENCES labours(id) ON DELETE SET NULL,
+    description VARCHAR(255) NOT NULL,
+    unit VARCHAR(10) NOT NULL CHECK (unit IN ('day', 'hour')),
+    quantity DECIMAL(8, 2) NOT NULL,
+    unit_rate DECIMAL(10, 2) NOT NULL,
+    amount DECIMAL(12, 2) NOT NULL
+);
+
+CREATE INDEX idx_invoice_items_invoice_id ON invoice_items(invoice_id);
+
+-- Money received from the client against a sent invoice
+CREATE TABLE invoice_receipts (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    invoice_id UUID NOT NULL REFERENCES invoices(id) ON DELETE CASCADE,
+    amount DECIMAL(12, 2) NOT NULL CHECK (amount > 0),
+    received_on DATE NOT NULL,
+    reference VARCHAR(255) NOT NULL DEFAULT '',
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_invoice_receipts_invoice_id ON invoice_receipts(invoice_id);
+
+CREATE TRIGGER update_billing_rates_updated_at BEFORE UPDATE ON billing_rates
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+CREATE TRIGGER update_invoices_updated_at BEFORE UPDATE ON invoices
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
@@ -0,0 +1 @@
+ALTER TABLE labours ALTER COLUMN user_id DROP NOT NULL;
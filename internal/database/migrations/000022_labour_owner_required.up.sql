@@ -0,0 +1,23 @@
+-- Labours left without an owner by 000002 were never visible to anyone.
+-- Give each one the owner of the earliest project it has work days or
+-- payments on, else the earliest registered user, who owned everything
+-- before labours were scoped.
+UPDATE labours l
+SET user_id = owner.user_id
+FROM (
+    SELECT DISTINCT ON (h.labour_id) h.labour_id, p.user_id
+    FROM (
+        SELECT labour_id, project_id, created_at FROM work_days
+        UNION ALL
+        SELECT labour_id, project_id, created_at FROM payments
+    ) h
+    INNER JOIN projects p ON p.id = h.project_id
+    ORDER BY h.labour_id, h.created_at ASC
+) owner
+WHERE l.id = owner.labour_id AND l.user_id IS NULL;
+
+UPDATE labours
+SET user_id = (SELECT id FROM users ORDER BY created_at ASC LIMIT 1)
+WHERE user_id IS NULL;
+
+ALTER TABLE labours ALTER COLUMN user_id SET NOT NULL;
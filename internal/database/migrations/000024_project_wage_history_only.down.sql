@@ -0,0 +1,67 @@
+DROP VIEW IF EXISTS work_day_earnings;
+
+CREATE VIEW work_day_earnings AS
+SELECT
+    e.id,
+    e.project_id,
+    e.labour_id,
+    e.work_date,
+    e.status,
+    e.day_fraction,
+    e.daily_wage,
+    e.overtime_hours,
+    e.overtime_rate,
+    e.overtime_hours * e.overtime_rate AS overtime_amount,
+    e.day_fraction * e.daily_wage + e.overtime_hours * e.overtime_rate AS amount
+FROM (
+    SELECT
+        r.*,
+        COALESCE(r.labour_overtime_rate, r.project_overtime_rate, ROUND(r.daily_wage / 8, 2)) AS overtime_rate
+    FROM (
+        SELECT
+            wd.id,
+            wd.project_id,
+            wd.labour_id,
+            wd.work_date,
+            wd.status,
+            CASE wd.status
+                WHEN 'full_day' THEN 1.0
+                WHEN 'half_day' THEN 0.5
+                ELSE 0
+            END AS day_fraction,
+            COALESCE(project_rate.daily_wage, pl.daily_wage, general_rate.daily_wage, opening_rate.daily_wage, l.daily_wage) AS daily_wage,
+            wd.overtime_hours,
+            l.overtime_rate AS labour_overtime_rate,
+            p.overtime_rate AS project_overtime_rate
+        FROM work_days wd
+        INNER JOIN labours l ON l.id = wd.labour_id
+        INNER JOIN projects p ON p.id = wd.project_id
+        LEFT JOIN project_labours pl ON pl.project_id = wd.project_id AND pl.labour_id = wd.labour_id
+        LEFT JOIN LATERAL (
+            SELECT wr.daily_wage
+            FROM labour_wage_rates wr
+            WHERE wr.labour_id = wd.labour_id
+              AND wr.project_id = wd.project_id
+              AND wr.effective_from <= wd.work_date
+            ORDER BY wr.effective_from DESC, wr.created_at DESC
+            LIMIT 1
+        ) project_rate ON TRUE
+        LEFT JOIN LATERAL (
+            SELECT wr.daily_wage
+            FROM labour_wage_rates wr
+            WHERE wr.labour_id = wd.labour_id
+              AND wr.project_id IS NULL
+              AND wr.effective_from <= wd.work_date
+            ORDER BY wr.effective_from DESC, wr.created_at DESC
+            LIMIT 1
+        ) general_rate ON TRUE
+        LEFT JOIN LATERAL (
+            SELECT wr.daily_wage
+            FROM labour_wage_rates wr
+            WHERE wr.labour_id = wd.labour_id
+              AND wr.project_id IS NULL
+            ORDER BY wr.effective_from ASC, wr.created_at ASC
+            LIMIT 1
+        ) opening_rate ON TRUE
+    ) r
+) e;
@@ -0,0 +1,2 @@
+ALTER TABLE labours ALTER COLUMN organisation_id DROP NOT NULL;
+ALTER TABLE organisations DROP COLUMN IF EXISTS personal_for;
@@ -0,0 +1,45 @@
+-- The organisation created on a user's first project or labour. At most one
+-- per user, so concurrent first requests can't each create one. It is
+-- cleared if the user stops owning it.
+ALTER TABLE organisations ADD COLUMN personal_for UUID UNIQUE REFERENCES users(id) ON DELETE SET NULL;
+
+UPDATE organisations o
+SET personal_for = first_owned.user_id
+FROM (
+    SELECT DISTINCT ON (m.user_id) m.user_id, o.id
+    FROM organisation_members m
+    INNER JOIN organisations o ON o.id = m.organisation_id
+    WHERE m.role = 'owner' AND o.created_by = m.user_id
+    ORDER BY m.user_id, o.created_at ASC
+) first_owned
+WHERE o.id = first_owned.id;
+
+-- Labours left without an organisation by 000010 belong to their owner's
+-- oldest organisation, created for owners who have none
+INSERT INTO organisations (name, created_by, personal_for)
+SELECT DISTINCT ON (u.id) COALESCE(NULLIF(u.name, ''), u.phone), u.id, u.id
+FROM labours l
+INNER JOIN users u ON u.id = l.user_id
+WHERE l.organisation_id IS NULL
+  AND NOT EXISTS (
+      SELECT 1 FROM organisation_members m WHERE m.user_id = u.id AND m.role = 'owner'
+  );
+
+INSERT INTO organisation_members (organisation_id, user_id, role, created_at)
+SELECT o.id, o.created_by, 'owner', o.created_at
+FROM organisations o
+WHERE o.personal_for IS NOT NULL
+  AND NOT EXISTS (SELECT 1 FROM organisation_members m WHERE m.organisation_id = o.id);
+
+UPDATE labours l
+SET organisation_id = (
+    SELECT o.id
+    FROM organisations o
+    INNER JOIN organisation_members m ON m.organisation_id = o.id
+    WHERE m.user_id = l.user_id AND m.role = 'owner'
+    ORDER BY o.created_at ASC
+    LIMIT 1
+)
+WHERE l.organisation_id IS NULL;
+
+ALTER TABLE labours ALTER COLUMN organisation_id SET NOT NULL;
@@ -0,0 +1 @@
+ALTER TABLE organisations DROP COLUMN IF EXISTS next_invoice_number;
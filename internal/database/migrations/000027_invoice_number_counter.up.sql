@@ -0,0 +1,7 @@
+-- The next invoice number of each organisation. Numbers are drawn from this
+-- counter rather than from the invoices that still exist, so a number is
+-- never issued twice even if the invoice holding it is later removed.
+ALTER TABLE organisations ADD COLUMN next_invoice_number INTEGER NOT NULL DEFAULT 1;
+
+UPDATE organisations o
+SET next_invoice_number = COALESCE((SELECT MAX(number) FROM invoices WHERE organisation_id = o.id), 0) + 1;
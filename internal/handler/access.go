@@ -0,0 +1,47 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/vivekanand/labour-thekedar-backend/internal/models"
+	"github.com/vivekanand/labour-thekedar-backend/internal/service"
+)
+
+// The helpers below cover resources that aren't addressed by :id on the
+// route, such as a project referenced from a work day or a request body.
+// Routes keyed by a project or labour :id use the middleware instead.
+
+// authorizeProject writes an error response and returns false unless the
+// user has perm on the project
+func authorizeProject(c *gin.Context, projectService *service.ProjectService, projectID, userID uuid.UUID, perm models.Permission) bool {
+	allowed, err := projectService.Authorize(c.Request.Context(), projectID, userID, perm)
+	return checkAccess(c, allowed, err)
+}
+
+// authorizeLabour writes an error response and returns false unless the
+// user has perm on the labour
+func authorizeLabour(c *gin.Context, labourService *service.LabourService, labourID, userID uuid.UUID, perm models.Permission) bool {
+	allowed, err := labourService.Authorize(c.Request.Context(), labourID, userID, perm)
+	return checkAccess(c, allowed, err)
+}
+
+// authorizeOrganisation writes an error response and returns false unless
+// the user has perm in the organisation
+func authorizeOrganisation(c *gin.Context, orgService *service.OrganisationService, orgID, userID uuid.UUID, perm models.Permission) bool {
+	allowed, err := orgService.Authorize(c.Request.Context(), orgID, userID, perm)
+	return checkAccess(c, allowed, err)
+}
+
+func checkAccess(c *gin.Context, allowed bool, err error) bool {
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to verify access"})
+		return false
+	}
+	if !allowed {
+		c.JSON(http.StatusForbidden, gin.H{"error": "access denied"})
+		return false
+	}
+	return true
+}
@@ -0,0 +1,82 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/vivekanand/labour-thekedar-backend/internal/models"
+	"github.com/vivekanand/labour-thekedar-backend/internal/service"
+)
+
+// auditFilterError explains a rejected audit filter
+const auditFilterError = "invalid filter, use YYYY-MM-DD dates, UUIDs for entity_id and actor_id, and a limit up to 500"
+
+// AuditHandler handles audit log endpoints
+type AuditHandler struct {
+	auditService   *service.AuditService
+	projectService *service.ProjectService
+}
+
+// NewAuditHandler creates a new AuditHandler
+func NewAuditHandler(auditService *service.AuditService, projectService *service.ProjectService) *AuditHandler {
+	return &AuditHandler{
+		auditService:   auditService,
+		projectService: projectService,
+	}
+}
+
+// ListByProject handles GET /api/v1/projects/:id/audit
+func (h *AuditHandler) ListByProject(c *gin.Context) {
+	projectID := c.MustGet("project").(*models.Project).ID
+
+	var query models.AuditQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": auditFilterError})
+		return
+	}
+
+	entries, err := h.auditService.GetByProjectID(c.Request.Context(), projectID, &query)
+	if err != nil {
+		if errors.Is(err, models.ErrInvalidAuditFilter) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": auditFilterError})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to list audit log"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"entries": entries})
+}
+
+// ListByLabour handles GET /api/v1/labours/:id/audit
+func (h *AuditHandler) ListByLabour(c *gin.Context) {
+	userID := c.MustGet("user_id").(uuid.UUID)
+	labour := c.MustGet("labour").(*models.Labour)
+
+	var query models.AuditQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": auditFilterError})
+		return
+	}
+
+	// Only changes on projects the user can see payments for are listed
+	projects, err := h.projectService.GetAccessibleForLabour(c.Request.Context(), labour.ID, userID, models.PermAuditRead)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to list audit log"})
+		return
+	}
+
+	entries, err := h.auditService.GetByLabourID(c.Request.Context(), labour.ID, projects, &query)
+	if err != nil {
+		if errors.Is(err, models.ErrInvalidAuditFilter) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": auditFilterError})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to list audit log"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"entries": entries})
+}
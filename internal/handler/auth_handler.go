@@ -1,9 +1,11 @@
 package handler
 
 import (
+	"errors"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 	"github.com/vivekanand/labour-thekedar-backend/internal/models"
 	"github.com/vivekanand/labour-thekedar-backend/internal/service"
 )
@@ -28,7 +30,11 @@ func (h *AuthHandler) SendOTP(c *gin.Context) {
 		return
 	}
 
-	if err := h.authService.SendOTP(c.Request.Context(), req.Phone); err != nil {
+	if err := h.authService.SendOTP(c.Request.Context(), req.Phone, c.ClientIP()); err != nil {
+		if errors.Is(err, models.ErrOTPCooldown) || errors.Is(err, models.ErrOTPSendLimit) {
+			c.JSON(http.StatusTooManyRequests, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to send OTP"})
 		return
 	}
@@ -44,12 +50,70 @@ func (h *AuthHandler) VerifyOTP(c *gin.Context) {
 		return
 	}
 
-	tokenResponse, err := h.authService.VerifyOTP(c.Request.Context(), req.Phone, req.OTP)
+	tokenResponse, err := h.authService.VerifyOTP(c.Request.Context(), req.Phone, req.OTP, service.DeviceInfo{
+		Name:      req.DeviceName,
+		IP:        c.ClientIP(),
+		UserAgent: c.Request.UserAgent(),
+	})
 	if err != nil {
 		if err == models.ErrInvalidOTP {
 			c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid or expired OTP"})
 			return
 		}
+		if errors.Is(err, models.ErrOTPVerifyLimit) || errors.Is(err, models.ErrOTPLocked) {
+			c.JSON(http.StatusTooManyRequests, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to verify OTP"})
+		return
+	}
+
+	c.JSON(http.StatusOK, tokenResponse)
+}
+
+// SendLabourOTP handles POST /api/v1/labour-auth/send-otp
+func (h *AuthHandler) SendLabourOTP(c *gin.Context) {
+	var req service.SendOTPRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Unregistered phones get the same response, so it reveals nothing
+	if err := h.authService.SendLabourOTP(c.Request.Context(), req.Phone, c.ClientIP()); err != nil {
+		if errors.Is(err, models.ErrOTPCooldown) || errors.Is(err, models.ErrOTPSendLimit) {
+			c.JSON(http.StatusTooManyRequests, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to send OTP"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "OTP sent successfully"})
+}
+
+// VerifyLabourOTP handles POST /api/v1/labour-auth/verify-otp
+func (h *AuthHandler) VerifyLabourOTP(c *gin.Context) {
+	var req service.VerifyOTPRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	tokenResponse, err := h.authService.VerifyLabourOTP(c.Request.Context(), req.Phone, req.OTP, c.ClientIP())
+	if err != nil {
+		if errors.Is(err, models.ErrInvalidOTP) {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid or expired OTP"})
+			return
+		}
+		if errors.Is(err, models.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "no labour registered with this phone"})
+			return
+		}
+		if errors.Is(err, models.ErrOTPVerifyLimit) || errors.Is(err, models.ErrOTPLocked) {
+			c.JSON(http.StatusTooManyRequests, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to verify OTP"})
 		return
 	}
@@ -67,9 +131,81 @@ func (h *AuthHandler) RefreshToken(c *gin.Context) {
 
 	tokenResponse, err := h.authService.RefreshToken(c.Request.Context(), req.RefreshToken)
 	if err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid refresh token"})
+		if errors.Is(err, models.ErrTokenReused) {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "refresh token already used, please log in again"})
+			return
+		}
+		if errors.Is(err, models.ErrInvalidToken) {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid refresh token"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to refresh token"})
 		return
 	}
 
 	c.JSON(http.StatusOK, tokenResponse)
 }
+
+// Logout handles POST /api/v1/auth/logout
+func (h *AuthHandler) Logout(c *gin.Context) {
+	var req service.LogoutRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.authService.Logout(c.Request.Context(), req.RefreshToken); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to log out"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "logged out successfully"})
+}
+
+// LogoutAll handles POST /api/v1/auth/logout-all
+func (h *AuthHandler) LogoutAll(c *gin.Context) {
+	userID := c.MustGet("user_id").(uuid.UUID)
+
+	if err := h.authService.LogoutAll(c.Request.Context(), userID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to log out"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "logged out of all devices"})
+}
+
+// ListSessions handles GET /api/v1/auth/sessions
+func (h *AuthHandler) ListSessions(c *gin.Context) {
+	userID := c.MustGet("user_id").(uuid.UUID)
+	sessionID := c.MustGet("session_id").(uuid.UUID)
+
+	sessions, err := h.authService.ListSessions(c.Request.Context(), userID, sessionID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to list sessions"})
+		return
+	}
+
+	c.JSON(http.StatusOK, sessions)
+}
+
+// RevokeSession handles DELETE /api/v1/auth/sessions/:id
+func (h *AuthHandler) RevokeSession(c *gin.Context) {
+	userID := c.MustGet("user_id").(uuid.UUID)
+
+	sessionID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid session ID"})
+		return
+	}
+
+	if err := h.authService.RevokeSession(c.Request.Context(), userID, sessionID); err != nil {
+		if errors.Is(err, models.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "session not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to revoke session"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "session revoked successfully"})
+}
@@ -0,0 +1,247 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/vivekanand/labour-thekedar-backend/internal/models"
+	"github.com/vivekanand/labour-thekedar-backend/internal/service"
+)
+
+// billingRateError explains a rejected billing rate
+const billingRateError = "invalid billing rate, set either a positive day_rate or a markup_percent up to 1000, for a labour, a role, or neither"
+
+// BillingHandler handles billing rate and invoice endpoints
+type BillingHandler struct {
+	billingService *service.BillingService
+	projectService *service.ProjectService
+}
+
+// NewBillingHandler creates a new BillingHandler
+func NewBillingHandler(billingService *service.BillingService, projectService *service.ProjectService) *BillingHandler {
+	return &BillingHandler{
+		billingService: billingService,
+		projectService: projectService,
+	}
+}
+
+// ListRates handles GET /api/v1/projects/:id/billing-rates
+func (h *BillingHandler) ListRates(c *gin.Context) {
+	projectID := c.MustGet("project").(*models.Project).ID
+
+	rates, err := h.billingService.GetRates(c.Request.Context(), projectID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to list billing rates"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"billing_rates": rates})
+}
+
+// SetRate handles POST /api/v1/projects/:id/billing-rates
+func (h *BillingHandler) SetRate(c *gin.Context) {
+	projectID := c.MustGet("project").(*models.Project).ID
+
+	var req models.SetBillingRateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	rate, err := h.billingService.SetRate(c.Request.Context(), projectID, &req)
+	if err != nil {
+		if errors.Is(err, models.ErrInvalidBillingRate) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": billingRateError})
+			return
+		}
+		if errors.Is(err, models.ErrInvalidLabour) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "labour is not assigned to this project"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to set billing rate"})
+		return
+	}
+
+	c.JSON(http.StatusOK, rate)
+}
+
+// DeleteRate handles DELETE /api/v1/projects/:id/billing-rates/:rate_id
+func (h *BillingHandler) DeleteRate(c *gin.Context) {
+	projectID := c.MustGet("project").(*models.Project).ID
+	rateID, err := uuid.Parse(c.Param("rate_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid billing rate ID"})
+		return
+	}
+
+	if err := h.billingService.DeleteRate(c.Request.Context(), projectID, rateID); err != nil {
+		if errors.Is(err, models.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "billing rate not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to delete billing rate"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "billing rate deleted successfully"})
+}
+
+// CreateInvoice handles POST /api/v1/projects/:id/invoices
+func (h *BillingHandler) CreateInvoice(c *gin.Context) {
+	userID := c.MustGet("user_id").(uuid.UUID)
+	projectID := c.MustGet("project").(*models.Project).ID
+
+	var req models.CreateInvoiceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	invoice, err := h.billingService.CreateInvoice(c.Request.Context(), projectID, userID, &req)
+	if err != nil {
+		if errors.Is(err, models.ErrInvalidDate) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid period, use YYYY-MM-DD with period_end on or after period_start"})
+			return
+		}
+		if errors.Is(err, models.ErrInvoiceOverlap) || errors.Is(err, models.ErrInvoiceEmpty) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to create invoice"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, invoice)
+}
+
+// ListInvoices handles GET /api/v1/projects/:id/invoices
+func (h *BillingHandler) ListInvoices(c *gin.Context) {
+	projectID := c.MustGet("project").(*models.Project).ID
+
+	invoices, err := h.billingService.GetInvoicesByProjectID(c.Request.Context(), projectID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to list invoices"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"invoices": invoices})
+}
+
+// GetInvoice handles GET /api/v1/invoices/:id
+func (h *BillingHandler) GetInvoice(c *gin.Context) {
+	invoice, ok := h.loadInvoice(c, models.PermBillingRead)
+	if !ok {
+		return
+	}
+
+	details, err := h.billingService.GetInvoice(c.Request.Context(), invoice.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get invoice"})
+		return
+	}
+
+	c.JSON(http.StatusOK, details)
+}
+
+// SendInvoice handles POST /api/v1/invoices/:id/send
+func (h *BillingHandler) SendInvoice(c *gin.Context) {
+	invoice, ok := h.loadInvoice(c, models.PermBillingWrite)
+	if !ok {
+		return
+	}
+
+	sent, err := h.billingService.SendInvoice(c.Request.Context(), invoice.ID)
+	if err != nil {
+		if errors.Is(err, models.ErrInvoiceNotDraft) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to send invoice"})
+		return
+	}
+
+	c.JSON(http.StatusOK, sent)
+}
+
+// DeleteInvoice handles DELETE /api/v1/invoices/:id
+func (h *BillingHandler) DeleteInvoice(c *gin.Context) {
+	invoice, ok := h.loadInvoice(c, models.PermBillingWrite)
+	if !ok {
+		return
+	}
+
+	if err := h.billingService.DeleteInvoice(c.Request.Context(), invoice.ID); err != nil {
+		if errors.Is(err, models.ErrInvoiceNotDraft) {
+			c.JSON(http.StatusConflict, gin.H{"error": "sent invoices cannot be deleted"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to delete invoice"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "invoice deleted successfully"})
+}
+
+// AddReceipt handles POST /api/v1/invoices/:id/receipts
+func (h *BillingHandler) AddReceipt(c *gin.Context) {
+	userID := c.MustGet("user_id").(uuid.UUID)
+	invoice, ok := h.loadInvoice(c, models.PermBillingWrite)
+	if !ok {
+		return
+	}
+
+	var req models.CreateInvoiceReceiptRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	updated, err := h.billingService.AddReceipt(c.Request.Context(), invoice.ID, userID, &req)
+	if err != nil {
+		if errors.Is(err, models.ErrInvalidDate) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid date format, use YYYY-MM-DD"})
+			return
+		}
+		if errors.Is(err, models.ErrInvalidAmount) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid amount, it must be positive and not more than is outstanding"})
+			return
+		}
+		if errors.Is(err, models.ErrInvoiceNotSent) || errors.Is(err, models.ErrInvoicePaid) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to record receipt"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, updated)
+}
+
+// loadInvoice loads the invoice named by :id and checks the permission on
+// its project. It writes the error response and returns false on failure.
+func (h *BillingHandler) loadInvoice(c *gin.Context, perm models.Permission) (*models.Invoice, bool) {
+	userID := c.MustGet("user_id").(uuid.UUID)
+	invoiceID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid invoice ID"})
+		return nil, false
+	}
+
+	invoice, err := h.billingService.GetInvoiceSummary(c.Request.Context(), invoiceID)
+	if err != nil {
+		if errors.Is(err, models.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "invoice not found"})
+			return nil, false
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get invoice"})
+		return nil, false
+	}
+
+	if !authorizeProject(c, h.projectService, invoice.ProjectID, userID, perm) {
+		return nil, false
+	}
+
+	return invoice, true
+}
@@ -0,0 +1,39 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/vivekanand/labour-thekedar-backend/internal/models"
+	"github.com/vivekanand/labour-thekedar-backend/internal/service"
+)
+
+// BudgetHandler handles project budget endpoints
+type BudgetHandler struct {
+	budgetService *service.BudgetService
+}
+
+// NewBudgetHandler creates a new BudgetHandler
+func NewBudgetHandler(budgetService *service.BudgetService) *BudgetHandler {
+	return &BudgetHandler{
+		budgetService: budgetService,
+	}
+}
+
+// Get handles GET /api/v1/projects/:id/budget
+func (h *BudgetHandler) Get(c *gin.Context) {
+	projectID := c.MustGet("project").(*models.Project).ID
+
+	budget, err := h.budgetService.Get(c.Request.Context(), projectID)
+	if err != nil {
+		if errors.Is(err, models.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "project not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get budget"})
+		return
+	}
+
+	c.JSON(http.StatusOK, budget)
+}
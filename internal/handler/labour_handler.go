@@ -26,7 +26,9 @@ func NewLabourHandler(labourService *service.LabourService, projectService *serv
 
 // List handles GET /api/v1/labours
 func (h *LabourHandler) List(c *gin.Context) {
-	labours, err := h.labourService.GetAll(c.Request.Context())
+	userID := c.MustGet("user_id").(uuid.UUID)
+
+	labours, err := h.labourService.GetByUserID(c.Request.Context(), userID)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to list labours"})
 		return
@@ -37,14 +39,24 @@ func (h *LabourHandler) List(c *gin.Context) {
 
 // Create handles POST /api/v1/labours
 func (h *LabourHandler) Create(c *gin.Context) {
+	userID := c.MustGet("user_id").(uuid.UUID)
+
 	var req models.CreateLabourRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	labour, err := h.labourService.Create(c.Request.Context(), &req)
+	labour, err := h.labourService.Create(c.Request.Context(), userID, &req)
 	if err != nil {
+		if errors.Is(err, models.ErrInvalidAmount) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid daily wage or overtime rate"})
+			return
+		}
+		if errors.Is(err, models.ErrForbidden) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "access denied"})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to create labour"})
 		return
 	}
@@ -54,32 +66,14 @@ func (h *LabourHandler) Create(c *gin.Context) {
 
 // Get handles GET /api/v1/labours/:id
 func (h *LabourHandler) Get(c *gin.Context) {
-	labourID, err := uuid.Parse(c.Param("id"))
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid labour ID"})
-		return
-	}
-
-	labour, err := h.labourService.GetByID(c.Request.Context(), labourID)
-	if err != nil {
-		if errors.Is(err, models.ErrNotFound) {
-			c.JSON(http.StatusNotFound, gin.H{"error": "labour not found"})
-			return
-		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get labour"})
-		return
-	}
+	labour := c.MustGet("labour").(*models.Labour)
 
 	c.JSON(http.StatusOK, labour)
 }
 
 // Update handles PUT /api/v1/labours/:id
 func (h *LabourHandler) Update(c *gin.Context) {
-	labourID, err := uuid.Parse(c.Param("id"))
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid labour ID"})
-		return
-	}
+	labourID := c.MustGet("labour").(*models.Labour).ID
 
 	var req models.UpdateLabourRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -93,6 +87,14 @@ func (h *LabourHandler) Update(c *gin.Context) {
 			c.JSON(http.StatusNotFound, gin.H{"error": "labour not found"})
 			return
 		}
+		if errors.Is(err, models.ErrInvalidDate) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid date format, use YYYY-MM-DD"})
+			return
+		}
+		if errors.Is(err, models.ErrInvalidAmount) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid daily wage or overtime rate"})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to update labour"})
 		return
 	}
@@ -102,87 +104,130 @@ func (h *LabourHandler) Update(c *gin.Context) {
 
 // Delete handles DELETE /api/v1/labours/:id
 func (h *LabourHandler) Delete(c *gin.Context) {
-	labourID, err := uuid.Parse(c.Param("id"))
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid labour ID"})
-		return
-	}
+	labourID := c.MustGet("labour").(*models.Labour).ID
 
 	if err := h.labourService.Delete(c.Request.Context(), labourID); err != nil {
 		if errors.Is(err, models.ErrNotFound) {
 			c.JSON(http.StatusNotFound, gin.H{"error": "labour not found"})
 			return
 		}
+		if errors.Is(err, models.ErrUnsettledBalance) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to delete labour"})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "labour deleted successfully"})
+	c.JSON(http.StatusOK, gin.H{"message": "labour moved to trash"})
 }
 
-// AssignToProject handles POST /api/v1/projects/:id/labours
-func (h *LabourHandler) AssignToProject(c *gin.Context) {
+// ListTrash handles GET /api/v1/labours/trash
+func (h *LabourHandler) ListTrash(c *gin.Context) {
 	userID := c.MustGet("user_id").(uuid.UUID)
-	projectID, err := uuid.Parse(c.Param("id"))
+
+	labours, err := h.labourService.GetTrash(c.Request.Context(), userID)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid project ID"})
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to list trash"})
 		return
 	}
 
-	// Verify project ownership
-	isOwner, err := h.projectService.IsOwner(c.Request.Context(), projectID, userID)
+	c.JSON(http.StatusOK, gin.H{"labours": labours})
+}
+
+// Restore handles POST /api/v1/labours/:id/restore
+func (h *LabourHandler) Restore(c *gin.Context) {
+	labourID := c.MustGet("labour").(*models.Labour).ID
+
+	labour, err := h.labourService.Restore(c.Request.Context(), labourID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to verify ownership"})
+		if errors.Is(err, models.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "labour not found in trash"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to restore labour"})
 		return
 	}
-	if !isOwner {
-		c.JSON(http.StatusForbidden, gin.H{"error": "access denied"})
+
+	c.JSON(http.StatusOK, labour)
+}
+
+// Purge handles POST /api/v1/labours/:id/purge
+func (h *LabourHandler) Purge(c *gin.Context) {
+	labourID := c.MustGet("labour").(*models.Labour).ID
+
+	var req models.PurgeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
+	if err := h.labourService.Purge(c.Request.Context(), labourID, &req); err != nil {
+		if errors.Is(err, models.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "labour not found in trash"})
+			return
+		}
+		if errors.Is(err, models.ErrPurgeNotConfirmed) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to purge labour"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "labour permanently deleted"})
+}
+
+// AssignToProject handles POST /api/v1/projects/:id/labours
+func (h *LabourHandler) AssignToProject(c *gin.Context) {
+	userID := c.MustGet("user_id").(uuid.UUID)
+	projectID := c.MustGet("project").(*models.Project).ID
+
 	var req models.AssignLabourRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	if err := h.labourService.AssignToProject(c.Request.Context(), projectID, req.LabourID); err != nil {
+	// Verify labour access
+	if !authorizeLabour(c, h.labourService, req.LabourID, userID, models.PermLaboursWrite) {
+		return
+	}
+
+	assignment, err := h.labourService.AssignToProject(c.Request.Context(), projectID, &req)
+	if err != nil {
 		if errors.Is(err, models.ErrNotFound) {
 			c.JSON(http.StatusNotFound, gin.H{"error": "labour not found"})
 			return
 		}
+		if errors.Is(err, models.ErrInvalidDate) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid date format, use YYYY-MM-DD"})
+			return
+		}
+		if errors.Is(err, models.ErrInvalidAmount) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid daily wage"})
+			return
+		}
+		if errors.Is(err, models.ErrInvalidLabour) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "labour belongs to a different organisation"})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to assign labour"})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "labour assigned successfully"})
+	c.JSON(http.StatusOK, gin.H{"message": "labour assigned successfully", "assignment": assignment})
 }
 
 // RemoveFromProject handles DELETE /api/v1/projects/:id/labours/:labour_id
 func (h *LabourHandler) RemoveFromProject(c *gin.Context) {
-	userID := c.MustGet("user_id").(uuid.UUID)
-	projectID, err := uuid.Parse(c.Param("id"))
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid project ID"})
-		return
-	}
+	projectID := c.MustGet("project").(*models.Project).ID
 	labourID, err := uuid.Parse(c.Param("labour_id"))
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid labour ID"})
 		return
 	}
 
-	// Verify project ownership
-	isOwner, err := h.projectService.IsOwner(c.Request.Context(), projectID, userID)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to verify ownership"})
-		return
-	}
-	if !isOwner {
-		c.JSON(http.StatusForbidden, gin.H{"error": "access denied"})
-		return
-	}
-
 	if err := h.labourService.RemoveFromProject(c.Request.Context(), projectID, labourID); err != nil {
 		if errors.Is(err, models.ErrNotFound) {
 			c.JSON(http.StatusNotFound, gin.H{"error": "labour not assigned to project"})
@@ -197,29 +242,65 @@ func (h *LabourHandler) RemoveFromProject(c *gin.Context) {
 
 // ListByProject handles GET /api/v1/projects/:id/labours
 func (h *LabourHandler) ListByProject(c *gin.Context) {
-	userID := c.MustGet("user_id").(uuid.UUID)
-	projectID, err := uuid.Parse(c.Param("id"))
+	projectID := c.MustGet("project").(*models.Project).ID
+
+	labours, err := h.labourService.GetByProjectID(c.Request.Context(), projectID)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid project ID"})
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to list labours"})
 		return
 	}
 
-	// Verify project ownership
-	isOwner, err := h.projectService.IsOwner(c.Request.Context(), projectID, userID)
+	c.JSON(http.StatusOK, gin.H{"labours": labours})
+}
+
+// ListWageRates handles GET /api/v1/labours/:id/wage-rates
+func (h *LabourHandler) ListWageRates(c *gin.Context) {
+	labourID := c.MustGet("labour").(*models.Labour).ID
+
+	rates, err := h.labourService.GetWageRates(c.Request.Context(), labourID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to verify ownership"})
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to list wage rates"})
 		return
 	}
-	if !isOwner {
-		c.JSON(http.StatusForbidden, gin.H{"error": "access denied"})
+
+	c.JSON(http.StatusOK, gin.H{"wage_rates": rates})
+}
+
+// CreateWageRate handles POST /api/v1/labours/:id/wage-rates
+func (h *LabourHandler) CreateWageRate(c *gin.Context) {
+	userID := c.MustGet("user_id").(uuid.UUID)
+	labourID := c.MustGet("labour").(*models.Labour).ID
+
+	var req models.CreateWageRateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	labours, err := h.labourService.GetByProjectID(c.Request.Context(), projectID)
+	// Verify project access for project-specific rates
+	if req.ProjectID != nil {
+		if !authorizeProject(c, h.projectService, *req.ProjectID, userID, models.PermLaboursWrite) {
+			return
+		}
+	}
+
+	rate, err := h.labourService.CreateWageRate(c.Request.Context(), labourID, &req)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to list labours"})
+		if errors.Is(err, models.ErrInvalidDate) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid date format, use YYYY-MM-DD"})
+			return
+		}
+		if errors.Is(err, models.ErrInvalidAmount) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid daily wage"})
+			return
+		}
+		if errors.Is(err, models.ErrInvalidLabour) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "project belongs to a different organisation"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to create wage rate"})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"labours": labours})
+	c.JSON(http.StatusCreated, rate)
 }
@@ -0,0 +1,294 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/vivekanand/labour-thekedar-backend/internal/models"
+	"github.com/vivekanand/labour-thekedar-backend/internal/service"
+)
+
+// OrganisationHandler handles organisation, member and invitation endpoints
+type OrganisationHandler struct {
+	orgService *service.OrganisationService
+}
+
+// NewOrganisationHandler creates a new OrganisationHandler
+func NewOrganisationHandler(orgService *service.OrganisationService) *OrganisationHandler {
+	return &OrganisationHandler{
+		orgService: orgService,
+	}
+}
+
+// List handles GET /api/v1/organisations
+func (h *OrganisationHandler) List(c *gin.Context) {
+	userID := c.MustGet("user_id").(uuid.UUID)
+
+	orgs, err := h.orgService.GetByUserID(c.Request.Context(), userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to list organisations"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"organisations": orgs})
+}
+
+// Create handles POST /api/v1/organisations
+func (h *OrganisationHandler) Create(c *gin.Context) {
+	userID := c.MustGet("user_id").(uuid.UUID)
+
+	var req models.CreateOrganisationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	org, err := h.orgService.Create(c.Request.Context(), userID, &req)
+	if err != nil {
+		if errors.Is(err, models.ErrInvalidName) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid name"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to create organisation"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, org)
+}
+
+// ListMembers handles GET /api/v1/organisations/:id/members
+func (h *OrganisationHandler) ListMembers(c *gin.Context) {
+	userID := c.MustGet("user_id").(uuid.UUID)
+	orgID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid organisation ID"})
+		return
+	}
+
+	// Any member may see who else is in the organisation
+	if !authorizeOrganisation(c, h.orgService, orgID, userID, models.PermProjectsRead) {
+		return
+	}
+
+	members, err := h.orgService.GetMembers(c.Request.Context(), orgID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to list members"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"members": members})
+}
+
+// UpdateMember handles PUT /api/v1/organisations/:id/members/:user_id
+func (h *OrganisationHandler) UpdateMember(c *gin.Context) {
+	userID := c.MustGet("user_id").(uuid.UUID)
+	orgID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid organisation ID"})
+		return
+	}
+	memberID, err := uuid.Parse(c.Param("user_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user ID"})
+		return
+	}
+
+	// Verify organisation access
+	if !authorizeOrganisation(c, h.orgService, orgID, userID, models.PermMembersManage) {
+		return
+	}
+
+	var req models.UpdateMemberRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.orgService.UpdateMemberRole(c.Request.Context(), orgID, memberID, &req); err != nil {
+		if errors.Is(err, models.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "member not found"})
+			return
+		}
+		if errors.Is(err, models.ErrInvalidRole) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid role"})
+			return
+		}
+		if errors.Is(err, models.ErrLastOwner) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to update member"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "member updated successfully"})
+}
+
+// RemoveMember handles DELETE /api/v1/organisations/:id/members/:user_id.
+// Members may always remove themselves.
+func (h *OrganisationHandler) RemoveMember(c *gin.Context) {
+	userID := c.MustGet("user_id").(uuid.UUID)
+	orgID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid organisation ID"})
+		return
+	}
+	memberID, err := uuid.Parse(c.Param("user_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user ID"})
+		return
+	}
+
+	// Verify organisation access unless leaving
+	if memberID != userID && !authorizeOrganisation(c, h.orgService, orgID, userID, models.PermMembersManage) {
+		return
+	}
+
+	if err := h.orgService.RemoveMember(c.Request.Context(), orgID, memberID); err != nil {
+		if errors.Is(err, models.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "member not found"})
+			return
+		}
+		if errors.Is(err, models.ErrLastOwner) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to remove member"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "member removed successfully"})
+}
+
+// ListInvitations handles GET /api/v1/organisations/:id/invitations
+func (h *OrganisationHandler) ListInvitations(c *gin.Context) {
+	userID := c.MustGet("user_id").(uuid.UUID)
+	orgID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid organisation ID"})
+		return
+	}
+
+	// Verify organisation access
+	if !authorizeOrganisation(c, h.orgService, orgID, userID, models.PermMembersManage) {
+		return
+	}
+
+	invitations, err := h.orgService.GetPendingInvitations(c.Request.Context(), orgID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to list invitations"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"invitations": invitations})
+}
+
+// Invite handles POST /api/v1/organisations/:id/invitations
+func (h *OrganisationHandler) Invite(c *gin.Context) {
+	userID := c.MustGet("user_id").(uuid.UUID)
+	orgID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid organisation ID"})
+		return
+	}
+
+	// Verify organisation access
+	if !authorizeOrganisation(c, h.orgService, orgID, userID, models.PermMembersManage) {
+		return
+	}
+
+	var req models.CreateInvitationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	invitation, err := h.orgService.Invite(c.Request.Context(), orgID, userID, &req)
+	if err != nil {
+		if errors.Is(err, models.ErrInvalidPhone) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid phone number"})
+			return
+		}
+		if errors.Is(err, models.ErrInvalidRole) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid role"})
+			return
+		}
+		if errors.Is(err, models.ErrAlreadyExists) {
+			c.JSON(http.StatusConflict, gin.H{"error": "an invitation is already open for this phone"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to create invitation"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, invitation)
+}
+
+// CancelInvitation handles DELETE /api/v1/organisations/:id/invitations/:invitation_id
+func (h *OrganisationHandler) CancelInvitation(c *gin.Context) {
+	userID := c.MustGet("user_id").(uuid.UUID)
+	orgID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid organisation ID"})
+		return
+	}
+	invitationID, err := uuid.Parse(c.Param("invitation_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid invitation ID"})
+		return
+	}
+
+	// Verify organisation access
+	if !authorizeOrganisation(c, h.orgService, orgID, userID, models.PermMembersManage) {
+		return
+	}
+
+	if err := h.orgService.CancelInvitation(c.Request.Context(), orgID, invitationID); err != nil {
+		if errors.Is(err, models.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "invitation not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to cancel invitation"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "invitation cancelled successfully"})
+}
+
+// ListMyInvitations handles GET /api/v1/invitations
+func (h *OrganisationHandler) ListMyInvitations(c *gin.Context) {
+	phone := c.MustGet("phone").(string)
+
+	invitations, err := h.orgService.GetInvitationsForPhone(c.Request.Context(), phone)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to list invitations"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"invitations": invitations})
+}
+
+// AcceptInvitation handles POST /api/v1/invitations/:id/accept
+func (h *OrganisationHandler) AcceptInvitation(c *gin.Context) {
+	userID := c.MustGet("user_id").(uuid.UUID)
+	phone := c.MustGet("phone").(string)
+	invitationID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid invitation ID"})
+		return
+	}
+
+	orgID, err := h.orgService.AcceptInvitation(c.Request.Context(), invitationID, userID, phone)
+	if err != nil {
+		if errors.Is(err, models.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "invitation not found or expired"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to accept invitation"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "invitation accepted", "organisation_id": orgID})
+}
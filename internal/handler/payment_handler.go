@@ -10,39 +10,28 @@ import (
 	"github.com/vivekanand/labour-thekedar-backend/internal/service"
 )
 
+// recoveryPlanError explains a rejected recovery plan
+const recoveryPlanError = "invalid recovery plan, advances only, use fixed with a positive amount or percentage up to 100"
+
 // PaymentHandler handles payment endpoints
 type PaymentHandler struct {
 	paymentService *service.PaymentService
 	projectService *service.ProjectService
+	labourService  *service.LabourService
 }
 
 // NewPaymentHandler creates a new PaymentHandler
-func NewPaymentHandler(paymentService *service.PaymentService, projectService *service.ProjectService) *PaymentHandler {
+func NewPaymentHandler(paymentService *service.PaymentService, projectService *service.ProjectService, labourService *service.LabourService) *PaymentHandler {
 	return &PaymentHandler{
 		paymentService: paymentService,
 		projectService: projectService,
+		labourService:  labourService,
 	}
 }
 
 // ListByProject handles GET /api/v1/projects/:id/payments
 func (h *PaymentHandler) ListByProject(c *gin.Context) {
-	userID := c.MustGet("user_id").(uuid.UUID)
-	projectID, err := uuid.Parse(c.Param("id"))
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid project ID"})
-		return
-	}
-
-	// Verify project ownership
-	isOwner, err := h.projectService.IsOwner(c.Request.Context(), projectID, userID)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to verify ownership"})
-		return
-	}
-	if !isOwner {
-		c.JSON(http.StatusForbidden, gin.H{"error": "access denied"})
-		return
-	}
+	projectID := c.MustGet("project").(*models.Project).ID
 
 	payments, err := h.paymentService.GetByProjectID(c.Request.Context(), projectID)
 	if err != nil {
@@ -55,23 +44,7 @@ func (h *PaymentHandler) ListByProject(c *gin.Context) {
 
 // Create handles POST /api/v1/projects/:id/payments
 func (h *PaymentHandler) Create(c *gin.Context) {
-	userID := c.MustGet("user_id").(uuid.UUID)
-	projectID, err := uuid.Parse(c.Param("id"))
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid project ID"})
-		return
-	}
-
-	// Verify project ownership
-	isOwner, err := h.projectService.IsOwner(c.Request.Context(), projectID, userID)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to verify ownership"})
-		return
-	}
-	if !isOwner {
-		c.JSON(http.StatusForbidden, gin.H{"error": "access denied"})
-		return
-	}
+	projectID := c.MustGet("project").(*models.Project).ID
 
 	var req models.CreatePaymentRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -94,7 +67,15 @@ func (h *PaymentHandler) Create(c *gin.Context) {
 			return
 		}
 		if errors.Is(err, models.ErrInvalidPaymentType) {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid payment type, use advance, daily_wage, or bonus"})
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid payment type, use advance, daily_wage, bonus, deduction, or refund"})
+			return
+		}
+		if errors.Is(err, models.ErrInvalidRecovery) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": recoveryPlanError})
+			return
+		}
+		if errors.Is(err, models.ErrPeriodLocked) || errors.Is(err, models.ErrProjectInactive) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
 			return
 		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to create payment"})
@@ -106,11 +87,7 @@ func (h *PaymentHandler) Create(c *gin.Context) {
 
 // ListByLabour handles GET /api/v1/labours/:id/payments
 func (h *PaymentHandler) ListByLabour(c *gin.Context) {
-	labourID, err := uuid.Parse(c.Param("id"))
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid labour ID"})
-		return
-	}
+	labourID := c.MustGet("labour").(*models.Labour).ID
 
 	payments, err := h.paymentService.GetByLabourID(c.Request.Context(), labourID)
 	if err != nil {
@@ -121,28 +98,104 @@ func (h *PaymentHandler) ListByLabour(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"payments": payments})
 }
 
-// GetBalance handles GET /api/v1/projects/:id/labours/:labour_id/balance
-func (h *PaymentHandler) GetBalance(c *gin.Context) {
+// ListBalances handles GET /api/v1/projects/:id/balances
+func (h *PaymentHandler) ListBalances(c *gin.Context) {
+	project := c.MustGet("project").(*models.Project)
+
+	sheet, err := h.paymentService.GetProjectBalances(c.Request.Context(), project)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get balances"})
+		return
+	}
+
+	c.JSON(http.StatusOK, sheet)
+}
+
+// Ledger handles GET /api/v1/labours/:id/ledger. Only projects where the
+// caller may read payments are included.
+func (h *PaymentHandler) Ledger(c *gin.Context) {
 	userID := c.MustGet("user_id").(uuid.UUID)
-	projectID, err := uuid.Parse(c.Param("id"))
+	labour := c.MustGet("labour").(*models.Labour)
+
+	projects, err := h.projectService.GetAccessibleForLabour(c.Request.Context(), labour.ID, userID, models.PermPaymentsRead)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid project ID"})
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get ledger"})
 		return
 	}
-	labourID, err := uuid.Parse(c.Param("labour_id"))
+
+	ledger, err := h.paymentService.GetLedger(c.Request.Context(), labour, projects, c.Query("from"), c.Query("to"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid labour ID"})
+		if errors.Is(err, models.ErrInvalidDate) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid date range, use YYYY-MM-DD with from on or before to"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get ledger"})
 		return
 	}
 
-	// Verify project ownership
-	isOwner, err := h.projectService.IsOwner(c.Request.Context(), projectID, userID)
+	c.JSON(http.StatusOK, ledger)
+}
+
+// ListAdvances handles GET /api/v1/labours/:id/advances
+func (h *PaymentHandler) ListAdvances(c *gin.Context) {
+	userID := c.MustGet("user_id").(uuid.UUID)
+	labour := c.MustGet("labour").(*models.Labour)
+
+	projects, err := h.projectService.GetAccessibleForLabour(c.Request.Context(), labour.ID, userID, models.PermPaymentsRead)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to verify ownership"})
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get advances"})
 		return
 	}
-	if !isOwner {
-		c.JSON(http.StatusForbidden, gin.H{"error": "access denied"})
+
+	summary, err := h.paymentService.GetAdvances(c.Request.Context(), labour, projects)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get advances"})
+		return
+	}
+
+	c.JSON(http.StatusOK, summary)
+}
+
+// SetRecovery handles PUT /api/v1/payments/:id/recovery
+func (h *PaymentHandler) SetRecovery(c *gin.Context) {
+	payment, ok := h.loadPayment(c)
+	if !ok {
+		return
+	}
+
+	var req models.SetRecoveryPlanRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	payment, err := h.paymentService.SetRecovery(c.Request.Context(), payment, &req)
+	if err != nil {
+		if errors.Is(err, models.ErrInvalidRecovery) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": recoveryPlanError})
+			return
+		}
+		if errors.Is(err, models.ErrPaymentVoided) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
+		if errors.Is(err, models.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "payment not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to update recovery plan"})
+		return
+	}
+
+	c.JSON(http.StatusOK, payment)
+}
+
+// GetBalance handles GET /api/v1/projects/:id/labours/:labour_id/balance
+func (h *PaymentHandler) GetBalance(c *gin.Context) {
+	projectID := c.MustGet("project").(*models.Project).ID
+	labourID, err := uuid.Parse(c.Param("labour_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid labour ID"})
 		return
 	}
 
@@ -159,41 +212,107 @@ func (h *PaymentHandler) GetBalance(c *gin.Context) {
 	c.JSON(http.StatusOK, balance)
 }
 
-// Delete handles DELETE /api/v1/payments/:id
-func (h *PaymentHandler) Delete(c *gin.Context) {
-	userID := c.MustGet("user_id").(uuid.UUID)
-	paymentID, err := uuid.Parse(c.Param("id"))
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid payment ID"})
+// Update handles PUT /api/v1/payments/:id
+func (h *PaymentHandler) Update(c *gin.Context) {
+	payment, ok := h.loadPayment(c)
+	if !ok {
 		return
 	}
 
-	// Get payment to verify project ownership
-	payment, err := h.paymentService.GetByID(c.Request.Context(), paymentID)
+	var req models.UpdatePaymentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	payment, err := h.paymentService.Update(c.Request.Context(), payment, &req)
 	if err != nil {
+		if errors.Is(err, models.ErrInvalidDate) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid date format, use YYYY-MM-DD"})
+			return
+		}
+		if errors.Is(err, models.ErrInvalidAmount) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid amount"})
+			return
+		}
+		if errors.Is(err, models.ErrInvalidPaymentType) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid payment type, use advance, daily_wage, bonus, deduction, or refund"})
+			return
+		}
+		if errors.Is(err, models.ErrAdvanceRecovered) {
+			c.JSON(http.StatusConflict, gin.H{"error": "advance has already been partly recovered by payroll, it must stay an advance of at least the recovered amount"})
+			return
+		}
+		if errors.Is(err, models.ErrPeriodLocked) || errors.Is(err, models.ErrProjectInactive) || errors.Is(err, models.ErrPaymentVoided) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
 		if errors.Is(err, models.ErrNotFound) {
 			c.JSON(http.StatusNotFound, gin.H{"error": "payment not found"})
 			return
 		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get payment"})
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to update payment"})
 		return
 	}
 
-	// Verify project ownership
-	isOwner, err := h.projectService.IsOwner(c.Request.Context(), payment.ProjectID, userID)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to verify ownership"})
+	c.JSON(http.StatusOK, payment)
+}
+
+// Void handles POST /api/v1/payments/:id/void
+func (h *PaymentHandler) Void(c *gin.Context) {
+	userID := c.MustGet("user_id").(uuid.UUID)
+	payment, ok := h.loadPayment(c)
+	if !ok {
 		return
 	}
-	if !isOwner {
-		c.JSON(http.StatusForbidden, gin.H{"error": "access denied"})
+
+	var req models.VoidPaymentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	if err := h.paymentService.Delete(c.Request.Context(), paymentID); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to delete payment"})
+	payment, err := h.paymentService.Void(c.Request.Context(), payment, userID, &req)
+	if err != nil {
+		if errors.Is(err, models.ErrAdvanceRecovered) {
+			c.JSON(http.StatusConflict, gin.H{"error": "advance has already been partly recovered by payroll and cannot be voided"})
+			return
+		}
+		if errors.Is(err, models.ErrPeriodLocked) || errors.Is(err, models.ErrProjectInactive) || errors.Is(err, models.ErrPaymentVoided) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to void payment"})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "payment deleted successfully"})
+	c.JSON(http.StatusOK, payment)
+}
+
+// loadPayment loads the payment named by :id and checks that the user may
+// change payments on its project. It writes the error response and returns
+// false on failure.
+func (h *PaymentHandler) loadPayment(c *gin.Context) (*models.Payment, bool) {
+	userID := c.MustGet("user_id").(uuid.UUID)
+	paymentID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid payment ID"})
+		return nil, false
+	}
+
+	payment, err := h.paymentService.GetByID(c.Request.Context(), paymentID)
+	if err != nil {
+		if errors.Is(err, models.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "payment not found"})
+			return nil, false
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get payment"})
+		return nil, false
+	}
+
+	if !authorizeProject(c, h.projectService, payment.ProjectID, userID, models.PermPaymentsWrite) {
+		return nil, false
+	}
+
+	return payment, true
 }
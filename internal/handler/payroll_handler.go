@@ -0,0 +1,276 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/vivekanand/labour-thekedar-backend/internal/models"
+	"github.com/vivekanand/labour-thekedar-backend/internal/service"
+)
+
+// PayrollHandler handles payroll run endpoints
+type PayrollHandler struct {
+	payrollService *service.PayrollService
+	projectService *service.ProjectService
+	orgService     *service.OrganisationService
+}
+
+// NewPayrollHandler creates a new PayrollHandler
+func NewPayrollHandler(payrollService *service.PayrollService, projectService *service.ProjectService, orgService *service.OrganisationService) *PayrollHandler {
+	return &PayrollHandler{
+		payrollService: payrollService,
+		projectService: projectService,
+		orgService:     orgService,
+	}
+}
+
+// Create handles POST /api/v1/payroll-runs
+func (h *PayrollHandler) Create(c *gin.Context) {
+	userID := c.MustGet("user_id").(uuid.UUID)
+
+	var req models.CreatePayrollRunRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Verify project access; organisation-wide runs are checked by the service
+	if req.ProjectID != nil && !authorizeProject(c, h.projectService, *req.ProjectID, userID, models.PermPaymentsWrite) {
+		return
+	}
+
+	run, err := h.payrollService.Create(c.Request.Context(), userID, &req)
+	if err != nil {
+		if errors.Is(err, models.ErrInvalidDate) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid period, use YYYY-MM-DD with period_end on or after period_start"})
+			return
+		}
+		if errors.Is(err, models.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "project not found"})
+			return
+		}
+		if errors.Is(err, models.ErrForbidden) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "access denied"})
+			return
+		}
+		if errors.Is(err, models.ErrPayrollOverlap) || errors.Is(err, models.ErrPeriodLocked) ||
+			errors.Is(err, models.ErrProjectInactive) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to create payroll run"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, run)
+}
+
+// ListByOrganisation handles GET /api/v1/organisations/:id/payroll-runs
+func (h *PayrollHandler) ListByOrganisation(c *gin.Context) {
+	userID := c.MustGet("user_id").(uuid.UUID)
+	orgID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid organisation ID"})
+		return
+	}
+
+	// Verify organisation access
+	if !authorizeOrganisation(c, h.orgService, orgID, userID, models.PermPaymentsRead) {
+		return
+	}
+
+	runs, err := h.payrollService.GetByOrganisationID(c.Request.Context(), orgID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to list payroll runs"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"payroll_runs": runs})
+}
+
+// ListByProject handles GET /api/v1/projects/:id/payroll-runs
+func (h *PayrollHandler) ListByProject(c *gin.Context) {
+	projectID := c.MustGet("project").(*models.Project).ID
+
+	runs, err := h.payrollService.GetByProjectID(c.Request.Context(), projectID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to list payroll runs"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"payroll_runs": runs})
+}
+
+// Get handles GET /api/v1/payroll-runs/:id
+func (h *PayrollHandler) Get(c *gin.Context) {
+	run, ok := h.loadRun(c, models.PermPaymentsRead)
+	if !ok {
+		return
+	}
+
+	runWithItems, err := h.payrollService.GetByID(c.Request.Context(), run.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get payroll run"})
+		return
+	}
+
+	c.JSON(http.StatusOK, runWithItems)
+}
+
+// UpdateItem handles PUT /api/v1/payroll-runs/:id/items/:item_id
+func (h *PayrollHandler) UpdateItem(c *gin.Context) {
+	run, ok := h.loadRun(c, models.PermPaymentsWrite)
+	if !ok {
+		return
+	}
+	itemID, err := uuid.Parse(c.Param("item_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid item ID"})
+		return
+	}
+
+	var req models.UpdatePayrollItemRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	item, err := h.payrollService.UpdateItem(c.Request.Context(), run.ID, itemID, &req)
+	if err != nil {
+		if errors.Is(err, models.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "payroll item not found"})
+			return
+		}
+		if errors.Is(err, models.ErrInvalidAmount) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid amounts, the deduction must be between zero and the outstanding advance and the net amount must not go below zero"})
+			return
+		}
+		if errors.Is(err, models.ErrPayrollApproved) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to update payroll item"})
+		return
+	}
+
+	c.JSON(http.StatusOK, item)
+}
+
+// DeleteItem handles DELETE /api/v1/payroll-runs/:id/items/:item_id
+func (h *PayrollHandler) DeleteItem(c *gin.Context) {
+	run, ok := h.loadRun(c, models.PermPaymentsWrite)
+	if !ok {
+		return
+	}
+	itemID, err := uuid.Parse(c.Param("item_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid item ID"})
+		return
+	}
+
+	if run.Status != models.PayrollStatusDraft {
+		c.JSON(http.StatusConflict, gin.H{"error": models.ErrPayrollApproved.Error()})
+		return
+	}
+
+	if err := h.payrollService.DeleteItem(c.Request.Context(), run.ID, itemID); err != nil {
+		if errors.Is(err, models.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "payroll item not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to delete payroll item"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "payroll item deleted successfully"})
+}
+
+// Approve handles POST /api/v1/payroll-runs/:id/approve
+func (h *PayrollHandler) Approve(c *gin.Context) {
+	userID := c.MustGet("user_id").(uuid.UUID)
+	run, ok := h.loadRun(c, models.PayrollApprovePermissions...)
+	if !ok {
+		return
+	}
+
+	// The body is optional
+	var req models.ApprovePayrollRunRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	approved, err := h.payrollService.Approve(c.Request.Context(), run.ID, userID, &req)
+	if err != nil {
+		if errors.Is(err, models.ErrInvalidDate) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid date format, use YYYY-MM-DD"})
+			return
+		}
+		if errors.Is(err, models.ErrPayrollApproved) || errors.Is(err, models.ErrPayrollOverlap) ||
+			errors.Is(err, models.ErrPeriodLocked) || errors.Is(err, models.ErrProjectInactive) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to approve payroll run"})
+		return
+	}
+
+	c.JSON(http.StatusOK, approved)
+}
+
+// Delete handles DELETE /api/v1/payroll-runs/:id
+func (h *PayrollHandler) Delete(c *gin.Context) {
+	run, ok := h.loadRun(c, models.PermPaymentsWrite)
+	if !ok {
+		return
+	}
+
+	if err := h.payrollService.Delete(c.Request.Context(), run.ID); err != nil {
+		if errors.Is(err, models.ErrPayrollApproved) {
+			c.JSON(http.StatusConflict, gin.H{"error": "approved payroll runs cannot be deleted"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to delete payroll run"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "payroll run deleted successfully"})
+}
+
+// loadRun loads the payroll run named by :id and checks the permissions on
+// its project, or on its organisation for organisation-wide runs. It writes
+// the error response and returns false on failure.
+func (h *PayrollHandler) loadRun(c *gin.Context, perms ...models.Permission) (*models.PayrollRun, bool) {
+	userID := c.MustGet("user_id").(uuid.UUID)
+	runID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid payroll run ID"})
+		return nil, false
+	}
+
+	run, err := h.payrollService.GetRun(c.Request.Context(), runID)
+	if err != nil {
+		if errors.Is(err, models.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "payroll run not found"})
+			return nil, false
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get payroll run"})
+		return nil, false
+	}
+
+	for _, perm := range perms {
+		if run.ProjectID != nil {
+			if !authorizeProject(c, h.projectService, *run.ProjectID, userID, perm) {
+				return nil, false
+			}
+		} else if !authorizeOrganisation(c, h.orgService, run.OrganisationID, userID, perm) {
+			return nil, false
+		}
+	}
+
+	return run, true
+}
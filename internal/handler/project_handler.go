@@ -10,6 +10,9 @@ import (
 	"github.com/vivekanand/labour-thekedar-backend/internal/service"
 )
 
+// projectStatusError explains a rejected project status
+const projectStatusError = "invalid status, use planned, active, on_hold, completed, or archived"
+
 // ProjectHandler handles project endpoints
 type ProjectHandler struct {
 	projectService *service.ProjectService
@@ -26,8 +29,18 @@ func NewProjectHandler(projectService *service.ProjectService) *ProjectHandler {
 func (h *ProjectHandler) List(c *gin.Context) {
 	userID := c.MustGet("user_id").(uuid.UUID)
 
-	projects, err := h.projectService.GetByUserID(c.Request.Context(), userID)
+	var query models.ProjectQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	projects, err := h.projectService.GetByUserID(c.Request.Context(), userID, &query)
 	if err != nil {
+		if errors.Is(err, models.ErrInvalidStatus) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": projectStatusError})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to list projects"})
 		return
 	}
@@ -47,6 +60,22 @@ func (h *ProjectHandler) Create(c *gin.Context) {
 
 	project, err := h.projectService.Create(c.Request.Context(), userID, &req)
 	if err != nil {
+		if errors.Is(err, models.ErrInvalidAmount) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid overtime rate or labour budget"})
+			return
+		}
+		if errors.Is(err, models.ErrInvalidStatus) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": projectStatusError})
+			return
+		}
+		if errors.Is(err, models.ErrInvalidDate) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid dates, use YYYY-MM-DD with the end on or after the start"})
+			return
+		}
+		if errors.Is(err, models.ErrForbidden) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "access denied"})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to create project"})
 		return
 	}
@@ -56,104 +85,258 @@ func (h *ProjectHandler) Create(c *gin.Context) {
 
 // Get handles GET /api/v1/projects/:id
 func (h *ProjectHandler) Get(c *gin.Context) {
-	userID := c.MustGet("user_id").(uuid.UUID)
-	projectID, err := uuid.Parse(c.Param("id"))
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid project ID"})
-		return
-	}
+	projectID := c.MustGet("project").(*models.Project).ID
 
-	// Verify ownership
-	isOwner, err := h.projectService.IsOwner(c.Request.Context(), projectID, userID)
+	project, err := h.projectService.GetByIDWithLabours(c.Request.Context(), projectID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to verify ownership"})
+		if errors.Is(err, models.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "project not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get project"})
 		return
 	}
-	if !isOwner {
-		c.JSON(http.StatusForbidden, gin.H{"error": "access denied"})
+
+	c.JSON(http.StatusOK, project)
+}
+
+// Update handles PUT /api/v1/projects/:id
+func (h *ProjectHandler) Update(c *gin.Context) {
+	projectID := c.MustGet("project").(*models.Project).ID
+
+	var req models.UpdateProjectRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	project, err := h.projectService.GetByIDWithLabours(c.Request.Context(), projectID)
+	project, err := h.projectService.Update(c.Request.Context(), projectID, &req)
 	if err != nil {
 		if errors.Is(err, models.ErrNotFound) {
 			c.JSON(http.StatusNotFound, gin.H{"error": "project not found"})
 			return
 		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get project"})
+		if errors.Is(err, models.ErrInvalidAmount) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid overtime rate or labour budget"})
+			return
+		}
+		if errors.Is(err, models.ErrInvalidStatus) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": projectStatusError})
+			return
+		}
+		if errors.Is(err, models.ErrInvalidDate) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid dates, use YYYY-MM-DD with the end on or after the start"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to update project"})
 		return
 	}
 
 	c.JSON(http.StatusOK, project)
 }
 
-// Update handles PUT /api/v1/projects/:id
-func (h *ProjectHandler) Update(c *gin.Context) {
+// Delete handles DELETE /api/v1/projects/:id
+func (h *ProjectHandler) Delete(c *gin.Context) {
+	projectID := c.MustGet("project").(*models.Project).ID
+
+	if err := h.projectService.Delete(c.Request.Context(), projectID); err != nil {
+		if errors.Is(err, models.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "project not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to delete project"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "project moved to trash"})
+}
+
+// ListTrash handles GET /api/v1/projects/trash
+func (h *ProjectHandler) ListTrash(c *gin.Context) {
 	userID := c.MustGet("user_id").(uuid.UUID)
-	projectID, err := uuid.Parse(c.Param("id"))
+
+	projects, err := h.projectService.GetTrash(c.Request.Context(), userID)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid project ID"})
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to list trash"})
 		return
 	}
 
-	// Verify ownership
-	isOwner, err := h.projectService.IsOwner(c.Request.Context(), projectID, userID)
+	c.JSON(http.StatusOK, gin.H{"projects": projects})
+}
+
+// Restore handles POST /api/v1/projects/:id/restore
+func (h *ProjectHandler) Restore(c *gin.Context) {
+	projectID := c.MustGet("project").(*models.Project).ID
+
+	project, err := h.projectService.Restore(c.Request.Context(), projectID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to verify ownership"})
+		if errors.Is(err, models.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "project not found in trash"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to restore project"})
 		return
 	}
-	if !isOwner {
-		c.JSON(http.StatusForbidden, gin.H{"error": "access denied"})
+
+	c.JSON(http.StatusOK, project)
+}
+
+// Purge handles POST /api/v1/projects/:id/purge
+func (h *ProjectHandler) Purge(c *gin.Context) {
+	projectID := c.MustGet("project").(*models.Project).ID
+
+	var req models.PurgeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	var req models.UpdateProjectRequest
+	if err := h.projectService.Purge(c.Request.Context(), projectID, &req); err != nil {
+		if errors.Is(err, models.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "project not found in trash"})
+			return
+		}
+		if errors.Is(err, models.ErrPurgeNotConfirmed) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if errors.Is(err, models.ErrProjectSettled) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to purge project"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "project permanently deleted"})
+}
+
+// ListMembers handles GET /api/v1/projects/:id/members
+func (h *ProjectHandler) ListMembers(c *gin.Context) {
+	projectID := c.MustGet("project").(*models.Project).ID
+
+	members, err := h.projectService.GetMembers(c.Request.Context(), projectID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to list members"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"members": members})
+}
+
+// AddMember handles POST /api/v1/projects/:id/members
+func (h *ProjectHandler) AddMember(c *gin.Context) {
+	projectID := c.MustGet("project").(*models.Project).ID
+
+	var req models.AddProjectMemberRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	project, err := h.projectService.Update(c.Request.Context(), projectID, &req)
+	member, err := h.projectService.AddMember(c.Request.Context(), projectID, &req)
+	if err != nil {
+		if errors.Is(err, models.ErrInvalidCapability) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid capability, use labours:read, attendance:read, attendance:write, payments:read or payments:write"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to add member"})
+		return
+	}
+
+	c.JSON(http.StatusOK, member)
+}
+
+// RemoveMember handles DELETE /api/v1/projects/:id/members/:user_id
+func (h *ProjectHandler) RemoveMember(c *gin.Context) {
+	projectID := c.MustGet("project").(*models.Project).ID
+	memberID, err := uuid.Parse(c.Param("user_id"))
 	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user ID"})
+		return
+	}
+
+	if err := h.projectService.RemoveMember(c.Request.Context(), projectID, memberID); err != nil {
 		if errors.Is(err, models.ErrNotFound) {
-			c.JSON(http.StatusNotFound, gin.H{"error": "project not found"})
+			c.JSON(http.StatusNotFound, gin.H{"error": "member not found"})
 			return
 		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to update project"})
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to remove member"})
 		return
 	}
 
-	c.JSON(http.StatusOK, project)
+	c.JSON(http.StatusOK, gin.H{"message": "member removed successfully"})
 }
 
-// Delete handles DELETE /api/v1/projects/:id
-func (h *ProjectHandler) Delete(c *gin.Context) {
+// ClosePeriod handles POST /api/v1/projects/:id/close
+func (h *ProjectHandler) ClosePeriod(c *gin.Context) {
 	userID := c.MustGet("user_id").(uuid.UUID)
-	projectID, err := uuid.Parse(c.Param("id"))
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid project ID"})
+	projectID := c.MustGet("project").(*models.Project).ID
+
+	var req models.ClosePeriodRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	// Verify ownership
-	isOwner, err := h.projectService.IsOwner(c.Request.Context(), projectID, userID)
+	event, err := h.projectService.ClosePeriod(c.Request.Context(), projectID, userID, &req)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to verify ownership"})
+		if errors.Is(err, models.ErrInvalidDate) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid date, use YYYY-MM-DD after the current closed-through date"})
+			return
+		}
+		if errors.Is(err, models.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "project not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to close period"})
 		return
 	}
-	if !isOwner {
-		c.JSON(http.StatusForbidden, gin.H{"error": "access denied"})
+
+	c.JSON(http.StatusOK, event)
+}
+
+// ReopenPeriod handles POST /api/v1/projects/:id/reopen
+func (h *ProjectHandler) ReopenPeriod(c *gin.Context) {
+	userID := c.MustGet("user_id").(uuid.UUID)
+	projectID := c.MustGet("project").(*models.Project).ID
+
+	var req models.ReopenPeriodRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	if err := h.projectService.Delete(c.Request.Context(), projectID); err != nil {
+	event, err := h.projectService.ReopenPeriod(c.Request.Context(), projectID, userID, &req)
+	if err != nil {
+		if errors.Is(err, models.ErrInvalidDate) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid date, use YYYY-MM-DD before the current closed-through date"})
+			return
+		}
+		if errors.Is(err, models.ErrInvalidReason) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "a reason is required"})
+			return
+		}
 		if errors.Is(err, models.ErrNotFound) {
 			c.JSON(http.StatusNotFound, gin.H{"error": "project not found"})
 			return
 		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to delete project"})
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to reopen period"})
+		return
+	}
+
+	c.JSON(http.StatusOK, event)
+}
+
+// ListPeriodEvents handles GET /api/v1/projects/:id/period-events
+func (h *ProjectHandler) ListPeriodEvents(c *gin.Context) {
+	projectID := c.MustGet("project").(*models.Project).ID
+
+	events, err := h.projectService.GetPeriodEvents(c.Request.Context(), projectID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to list period events"})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "project deleted successfully"})
+	c.JSON(http.StatusOK, gin.H{"events": events})
 }
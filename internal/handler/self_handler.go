@@ -0,0 +1,72 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/vivekanand/labour-thekedar-backend/internal/service"
+)
+
+// SelfHandler handles a labour's read-only endpoints
+type SelfHandler struct {
+	selfService *service.SelfService
+}
+
+// NewSelfHandler creates a new SelfHandler
+func NewSelfHandler(selfService *service.SelfService) *SelfHandler {
+	return &SelfHandler{
+		selfService: selfService,
+	}
+}
+
+// Get handles GET /api/v1/me
+func (h *SelfHandler) Get(c *gin.Context) {
+	phone := c.MustGet("labour_phone").(string)
+
+	labours, err := h.selfService.GetProfiles(c.Request.Context(), phone)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get profile"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"phone": phone, "labours": labours})
+}
+
+// ListWorkDays handles GET /api/v1/me/work-days
+func (h *SelfHandler) ListWorkDays(c *gin.Context) {
+	phone := c.MustGet("labour_phone").(string)
+
+	workDays, err := h.selfService.GetWorkDays(c.Request.Context(), phone)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to list work days"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"work_days": workDays})
+}
+
+// ListPayments handles GET /api/v1/me/payments
+func (h *SelfHandler) ListPayments(c *gin.Context) {
+	phone := c.MustGet("labour_phone").(string)
+
+	payments, err := h.selfService.GetPayments(c.Request.Context(), phone)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to list payments"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"payments": payments})
+}
+
+// GetBalance handles GET /api/v1/me/balance
+func (h *SelfHandler) GetBalance(c *gin.Context) {
+	phone := c.MustGet("labour_phone").(string)
+
+	balance, err := h.selfService.GetBalance(c.Request.Context(), phone)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get balance"})
+		return
+	}
+
+	c.JSON(http.StatusOK, balance)
+}
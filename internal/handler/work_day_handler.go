@@ -26,23 +26,7 @@ func NewWorkDayHandler(workDayService *service.WorkDayService, projectService *s
 
 // List handles GET /api/v1/projects/:id/attendance
 func (h *WorkDayHandler) List(c *gin.Context) {
-	userID := c.MustGet("user_id").(uuid.UUID)
-	projectID, err := uuid.Parse(c.Param("id"))
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid project ID"})
-		return
-	}
-
-	// Verify project ownership
-	isOwner, err := h.projectService.IsOwner(c.Request.Context(), projectID, userID)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to verify ownership"})
-		return
-	}
-	if !isOwner {
-		c.JSON(http.StatusForbidden, gin.H{"error": "access denied"})
-		return
-	}
+	projectID := c.MustGet("project").(*models.Project).ID
 
 	// Check if filtering by date
 	dateStr := c.Query("date")
@@ -71,23 +55,7 @@ func (h *WorkDayHandler) List(c *gin.Context) {
 
 // Create handles POST /api/v1/projects/:id/attendance
 func (h *WorkDayHandler) Create(c *gin.Context) {
-	userID := c.MustGet("user_id").(uuid.UUID)
-	projectID, err := uuid.Parse(c.Param("id"))
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid project ID"})
-		return
-	}
-
-	// Verify project ownership
-	isOwner, err := h.projectService.IsOwner(c.Request.Context(), projectID, userID)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to verify ownership"})
-		return
-	}
-	if !isOwner {
-		c.JSON(http.StatusForbidden, gin.H{"error": "access denied"})
-		return
-	}
+	projectID := c.MustGet("project").(*models.Project).ID
 
 	var req models.CreateWorkDayRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -109,6 +77,22 @@ func (h *WorkDayHandler) Create(c *gin.Context) {
 			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid status, use full_day, half_day, or absent"})
 			return
 		}
+		if errors.Is(err, models.ErrInvalidOvertime) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid overtime hours, must be 0-16 and not on an absent day"})
+			return
+		}
+		if errors.Is(err, models.ErrInvalidTime) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid check-in/check-out, use HH:MM with check-out after check-in"})
+			return
+		}
+		if errors.Is(err, models.ErrAlreadyExists) {
+			c.JSON(http.StatusConflict, gin.H{"error": "attendance already marked for this date, use the bulk endpoint or update it"})
+			return
+		}
+		if errors.Is(err, models.ErrPeriodLocked) || errors.Is(err, models.ErrProjectInactive) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to create attendance record"})
 		return
 	}
@@ -116,6 +100,33 @@ func (h *WorkDayHandler) Create(c *gin.Context) {
 	c.JSON(http.StatusCreated, workDay)
 }
 
+// BulkUpsert handles POST /api/v1/projects/:id/attendance/bulk
+func (h *WorkDayHandler) BulkUpsert(c *gin.Context) {
+	projectID := c.MustGet("project").(*models.Project).ID
+
+	var req models.BulkAttendanceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	response, err := h.workDayService.BulkUpsert(c.Request.Context(), projectID, &req)
+	if err != nil {
+		if errors.Is(err, models.ErrInvalidDate) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid date format, use YYYY-MM-DD"})
+			return
+		}
+		if errors.Is(err, models.ErrPeriodLocked) || errors.Is(err, models.ErrProjectInactive) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to save attendance"})
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
 // Update handles PUT /api/v1/attendance/:id
 func (h *WorkDayHandler) Update(c *gin.Context) {
 	userID := c.MustGet("user_id").(uuid.UUID)
@@ -125,7 +136,7 @@ func (h *WorkDayHandler) Update(c *gin.Context) {
 		return
 	}
 
-	// Get work day to verify project ownership
+	// Get work day to verify project access
 	workDay, err := h.workDayService.GetByID(c.Request.Context(), workDayID)
 	if err != nil {
 		if errors.Is(err, models.ErrNotFound) {
@@ -136,14 +147,8 @@ func (h *WorkDayHandler) Update(c *gin.Context) {
 		return
 	}
 
-	// Verify project ownership
-	isOwner, err := h.projectService.IsOwner(c.Request.Context(), workDay.ProjectID, userID)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to verify ownership"})
-		return
-	}
-	if !isOwner {
-		c.JSON(http.StatusForbidden, gin.H{"error": "access denied"})
+	// Verify project access
+	if !authorizeProject(c, h.projectService, workDay.ProjectID, userID, models.PermAttendanceWrite) {
 		return
 	}
 
@@ -159,6 +164,18 @@ func (h *WorkDayHandler) Update(c *gin.Context) {
 			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid status"})
 			return
 		}
+		if errors.Is(err, models.ErrInvalidOvertime) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid overtime hours, must be 0-16 and not on an absent day"})
+			return
+		}
+		if errors.Is(err, models.ErrInvalidTime) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid check-in/check-out, use HH:MM with check-out after check-in"})
+			return
+		}
+		if errors.Is(err, models.ErrPeriodLocked) || errors.Is(err, models.ErrProjectInactive) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to update attendance record"})
 		return
 	}
@@ -175,7 +192,7 @@ func (h *WorkDayHandler) Delete(c *gin.Context) {
 		return
 	}
 
-	// Get work day to verify project ownership
+	// Get work day to verify project access
 	workDay, err := h.workDayService.GetByID(c.Request.Context(), workDayID)
 	if err != nil {
 		if errors.Is(err, models.ErrNotFound) {
@@ -186,18 +203,16 @@ func (h *WorkDayHandler) Delete(c *gin.Context) {
 		return
 	}
 
-	// Verify project ownership
-	isOwner, err := h.projectService.IsOwner(c.Request.Context(), workDay.ProjectID, userID)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to verify ownership"})
-		return
-	}
-	if !isOwner {
-		c.JSON(http.StatusForbidden, gin.H{"error": "access denied"})
+	// Verify project access
+	if !authorizeProject(c, h.projectService, workDay.ProjectID, userID, models.PermAttendanceWrite) {
 		return
 	}
 
 	if err := h.workDayService.Delete(c.Request.Context(), workDayID); err != nil {
+		if errors.Is(err, models.ErrPeriodLocked) || errors.Is(err, models.ErrProjectInactive) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to delete attendance record"})
 		return
 	}
@@ -0,0 +1,108 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/vivekanand/labour-thekedar-backend/internal/models"
+	"github.com/vivekanand/labour-thekedar-backend/internal/service"
+)
+
+// RequireProjectPermission creates a middleware that loads the project named
+// by the :id route parameter into the context as "project", after checking
+// the user holds perm on it. A missing project is a 404, not a 403.
+func RequireProjectPermission(projectService *service.ProjectService, perm models.Permission) gin.HandlerFunc {
+	return requireProject(projectService, perm, projectService.GetByID)
+}
+
+// RequireTrashedProjectPermission is RequireProjectPermission for a project
+// in the trash
+func RequireTrashedProjectPermission(projectService *service.ProjectService, perm models.Permission) gin.HandlerFunc {
+	return requireProject(projectService, perm, projectService.GetDeletedByID)
+}
+
+func requireProject(projectService *service.ProjectService, perm models.Permission, load func(context.Context, uuid.UUID) (*models.Project, error)) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID := c.MustGet("user_id").(uuid.UUID)
+		projectID, err := uuid.Parse(c.Param("id"))
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "invalid project ID"})
+			return
+		}
+
+		project, err := load(c.Request.Context(), projectID)
+		if err != nil {
+			if errors.Is(err, models.ErrNotFound) {
+				c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"error": "project not found"})
+				return
+			}
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "failed to get project"})
+			return
+		}
+
+		allowed, err := projectService.Authorize(c.Request.Context(), projectID, userID, perm)
+		if !checkAccess(c, allowed, err) {
+			return
+		}
+
+		c.Set("project", project)
+		c.Next()
+	}
+}
+
+// RequireLabourPermission creates a middleware that loads the labour named
+// by the :id route parameter into the context as "labour", after checking
+// the user holds perm on it. A missing labour is a 404, not a 403.
+func RequireLabourPermission(labourService *service.LabourService, perm models.Permission) gin.HandlerFunc {
+	return requireLabour(labourService, perm, labourService.GetByID)
+}
+
+// RequireTrashedLabourPermission is RequireLabourPermission for a labour in
+// the trash
+func RequireTrashedLabourPermission(labourService *service.LabourService, perm models.Permission) gin.HandlerFunc {
+	return requireLabour(labourService, perm, labourService.GetDeletedByID)
+}
+
+func requireLabour(labourService *service.LabourService, perm models.Permission, load func(context.Context, uuid.UUID) (*models.Labour, error)) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID := c.MustGet("user_id").(uuid.UUID)
+		labourID, err := uuid.Parse(c.Param("id"))
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "invalid labour ID"})
+			return
+		}
+
+		labour, err := load(c.Request.Context(), labourID)
+		if err != nil {
+			if errors.Is(err, models.ErrNotFound) {
+				c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"error": "labour not found"})
+				return
+			}
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "failed to get labour"})
+			return
+		}
+
+		allowed, err := labourService.Authorize(c.Request.Context(), labourID, userID, perm)
+		if !checkAccess(c, allowed, err) {
+			return
+		}
+
+		c.Set("labour", labour)
+		c.Next()
+	}
+}
+
+func checkAccess(c *gin.Context, allowed bool, err error) bool {
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "failed to verify access"})
+		return false
+	}
+	if !allowed {
+		c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "access denied"})
+		return false
+	}
+	return true
+}
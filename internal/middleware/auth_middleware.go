@@ -1,10 +1,12 @@
 package middleware
 
 import (
+	"errors"
 	"net/http"
 	"strings"
 
 	"github.com/gin-gonic/gin"
+	"github.com/vivekanand/labour-thekedar-backend/internal/models"
 	"github.com/vivekanand/labour-thekedar-backend/internal/service"
 )
 
@@ -40,9 +42,27 @@ func AuthMiddleware(authService *service.AuthService) gin.HandlerFunc {
 			return
 		}
 
+		// Reject tokens from revoked sessions
+		if err := authService.ValidateSession(c.Request.Context(), claims); err != nil {
+			if errors.Is(err, models.ErrSessionRevoked) {
+				c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
+					"error": "session has been revoked",
+				})
+				return
+			}
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+				"error": "failed to validate session",
+			})
+			return
+		}
+
 		// Set user info in context
 		c.Set("user_id", claims.UserID)
 		c.Set("phone", claims.Phone)
+		c.Set("session_id", claims.SessionID)
+
+		// Changes made during the request are attributed to the user
+		c.Request = c.Request.WithContext(service.WithActor(c.Request.Context(), claims.UserID))
 
 		c.Next()
 	}
@@ -66,11 +86,47 @@ func OptionalAuthMiddleware(authService *service.AuthService) gin.HandlerFunc {
 
 		tokenString := parts[1]
 		claims, err := authService.ValidateToken(tokenString)
-		if err == nil {
+		if err == nil && authService.ValidateSession(c.Request.Context(), claims) == nil {
 			c.Set("user_id", claims.UserID)
 			c.Set("phone", claims.Phone)
+			c.Set("session_id", claims.SessionID)
+			c.Request = c.Request.WithContext(service.WithActor(c.Request.Context(), claims.UserID))
+		}
+
+		c.Next()
+	}
+}
+
+// LabourAuthMiddleware creates a middleware that validates labour tokens and
+// sets the labour's phone in the context. User access tokens are rejected.
+func LabourAuthMiddleware(authService *service.AuthService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		authHeader := c.GetHeader("Authorization")
+		if authHeader == "" {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
+				"error": "authorization header required",
+			})
+			return
+		}
+
+		parts := strings.SplitN(authHeader, " ", 2)
+		if len(parts) != 2 || parts[0] != "Bearer" {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
+				"error": "invalid authorization header format",
+			})
+			return
 		}
 
+		claims, err := authService.ValidateLabourToken(parts[1])
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
+				"error": "invalid or expired token",
+			})
+			return
+		}
+
+		c.Set("labour_phone", claims.Phone)
+
 		c.Next()
 	}
 }
@@ -0,0 +1,110 @@
+package models
+
+import (
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+// RecoveryType represents how an advance is recovered from payroll
+type RecoveryType string
+
+const (
+	RecoveryTypeFixed      RecoveryType = "fixed"      // A fixed amount per payroll run
+	RecoveryTypePercentage RecoveryType = "percentage" // A percentage of the advance per payroll run
+)
+
+// IsValid checks if the recovery type is valid
+func (rt RecoveryType) IsValid() bool {
+	switch rt {
+	case RecoveryTypeFixed, RecoveryTypePercentage:
+		return true
+	}
+	return false
+}
+
+// Advance represents an advance payment with how much of it has been recovered
+type Advance struct {
+	Payment
+	ProjectName    string          `json:"project_name"`
+	Recovered      decimal.Decimal `json:"recovered"`
+	Outstanding    decimal.Decimal `json:"outstanding"`
+	NextInstalment decimal.Decimal `json:"next_instalment"` // Deducted by the next payroll run, earnings permitting
+}
+
+// AdvanceSummary represents a labour's advances across projects with totals
+type AdvanceSummary struct {
+	LabourID       uuid.UUID       `json:"labour_id"`
+	LabourName     string          `json:"labour_name"`
+	Advances       []Advance       `json:"advances"`
+	TotalAdvanced  decimal.Decimal `json:"total_advanced"`
+	TotalRecovered decimal.Decimal `json:"total_recovered"`
+	Outstanding    decimal.Decimal `json:"outstanding"`
+}
+
+// SetRecoveryPlanRequest represents the request to set or clear the recovery
+// plan of an advance. Leaving both fields empty recovers it in full.
+type SetRecoveryPlanRequest struct {
+	RecoveryType  *RecoveryType    `json:"recovery_type"`
+	RecoveryValue *decimal.Decimal `json:"recovery_value"`
+}
+
+// NewAdvanceSummary builds a labour's advance summary, filling in each
+// advance's next instalment
+func NewAdvanceSummary(labour *Labour, advances []Advance) *AdvanceSummary {
+	summary := &AdvanceSummary{
+		LabourID:       labour.ID,
+		LabourName:     labour.Name,
+		Advances:       advances,
+		TotalAdvanced:  decimal.Zero,
+		TotalRecovered: decimal.Zero,
+		Outstanding:    decimal.Zero,
+	}
+	if summary.Advances == nil {
+		summary.Advances = []Advance{}
+	}
+
+	for i := range summary.Advances {
+		a := &summary.Advances[i]
+		a.NextInstalment = a.Instalment(a.Outstanding)
+		summary.TotalAdvanced = summary.TotalAdvanced.Add(a.Amount)
+		summary.TotalRecovered = summary.TotalRecovered.Add(a.Recovered)
+		summary.Outstanding = summary.Outstanding.Add(a.Outstanding)
+	}
+
+	return summary
+}
+
+// Instalment returns what one payroll run recovers from an advance with the
+// given amount outstanding. Without a plan the whole amount is recovered.
+func (p *Payment) Instalment(outstanding decimal.Decimal) decimal.Decimal {
+	if !outstanding.IsPositive() {
+		return decimal.Zero
+	}
+	if p.RecoveryType == nil || p.RecoveryValue == nil {
+		return outstanding
+	}
+
+	instalment := *p.RecoveryValue
+	if *p.RecoveryType == RecoveryTypePercentage {
+		instalment = p.Amount.Mul(*p.RecoveryValue).Div(decimal.NewFromInt(100)).Round(2)
+	}
+
+	return decimal.Min(instalment, outstanding)
+}
+
+// validateRecovery checks the recovery plan, which only advances may have
+func (p *Payment) validateRecovery() error {
+	if p.RecoveryType == nil && p.RecoveryValue == nil {
+		return nil
+	}
+	if p.PaymentType != PaymentTypeAdvance || p.RecoveryType == nil || p.RecoveryValue == nil {
+		return ErrInvalidRecovery
+	}
+	if !p.RecoveryType.IsValid() || !p.RecoveryValue.IsPositive() {
+		return ErrInvalidRecovery
+	}
+	if *p.RecoveryType == RecoveryTypePercentage && p.RecoveryValue.GreaterThan(decimal.NewFromInt(100)) {
+		return ErrInvalidRecovery
+	}
+	return nil
+}
@@ -0,0 +1,94 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func advance(amount int64, recoveryType RecoveryType, value int64) Payment {
+	p := Payment{
+		ProjectID:   uuid.New(),
+		LabourID:    uuid.New(),
+		Amount:      decimal.NewFromInt(amount),
+		PaymentDate: time.Now(),
+		PaymentType: PaymentTypeAdvance,
+	}
+	if recoveryType != "" {
+		v := decimal.NewFromInt(value)
+		p.RecoveryType = &recoveryType
+		p.RecoveryValue = &v
+	}
+	return p
+}
+
+func TestPayment_Instalment(t *testing.T) {
+	t.Run("no plan recovers everything outstanding", func(t *testing.T) {
+		p := advance(3000, "", 0)
+		assert.Equal(t, "1200", p.Instalment(decimal.NewFromInt(1200)).String())
+	})
+
+	t.Run("fixed amount capped at outstanding", func(t *testing.T) {
+		p := advance(3000, RecoveryTypeFixed, 500)
+		assert.Equal(t, "500", p.Instalment(decimal.NewFromInt(3000)).String())
+		assert.Equal(t, "200", p.Instalment(decimal.NewFromInt(200)).String())
+	})
+
+	t.Run("percentage of the advance", func(t *testing.T) {
+		p := advance(2500, RecoveryTypePercentage, 10)
+		assert.Equal(t, "250", p.Instalment(decimal.NewFromInt(2500)).String())
+	})
+
+	t.Run("nothing outstanding", func(t *testing.T) {
+		p := advance(2500, RecoveryTypeFixed, 500)
+		assert.True(t, p.Instalment(decimal.Zero).IsZero())
+	})
+}
+
+func TestPayment_ValidateRecovery(t *testing.T) {
+	t.Run("valid plans", func(t *testing.T) {
+		for _, p := range []Payment{
+			advance(1000, "", 0),
+			advance(1000, RecoveryTypeFixed, 200),
+			advance(1000, RecoveryTypePercentage, 100),
+		} {
+			assert.NoError(t, p.Validate())
+		}
+	})
+
+	t.Run("invalid plans", func(t *testing.T) {
+		for _, p := range []Payment{
+			advance(1000, RecoveryTypePercentage, 101),
+			advance(1000, RecoveryTypeFixed, 0),
+			advance(1000, "weekly", 100),
+		} {
+			assert.ErrorIs(t, p.Validate(), ErrInvalidRecovery)
+		}
+	})
+
+	t.Run("only advances have plans", func(t *testing.T) {
+		p := advance(1000, RecoveryTypeFixed, 200)
+		p.PaymentType = PaymentTypeBonus
+		assert.ErrorIs(t, p.Validate(), ErrInvalidRecovery)
+	})
+}
+
+func TestNewAdvanceSummary(t *testing.T) {
+	labour := &Labour{ID: uuid.New(), Name: "Ramesh"}
+
+	first := Advance{Payment: advance(3000, RecoveryTypeFixed, 500), Recovered: decimal.NewFromInt(1000), Outstanding: decimal.NewFromInt(2000)}
+	second := Advance{Payment: advance(1000, "", 0), Recovered: decimal.Zero, Outstanding: decimal.NewFromInt(1000)}
+
+	summary := NewAdvanceSummary(labour, []Advance{first, second})
+
+	assert.Equal(t, "4000", summary.TotalAdvanced.String())
+	assert.Equal(t, "1000", summary.TotalRecovered.String())
+	assert.Equal(t, "3000", summary.Outstanding.String())
+	assert.Equal(t, "500", summary.Advances[0].NextInstalment.String())
+	assert.Equal(t, "1000", summary.Advances[1].NextInstalment.String())
+
+	assert.NotNil(t, NewAdvanceSummary(labour, nil).Advances)
+}
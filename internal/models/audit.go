@@ -0,0 +1,85 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AuditAction represents what was done to an entity
+type AuditAction string
+
+const (
+	AuditActionCreate   AuditAction = "create"
+	AuditActionUpdate   AuditAction = "update"
+	AuditActionDelete   AuditAction = "delete"
+	AuditActionVoid     AuditAction = "void"
+	AuditActionApprove  AuditAction = "approve"
+	AuditActionClose    AuditAction = "close"
+	AuditActionReopen   AuditAction = "reopen"
+	AuditActionAssign   AuditAction = "assign"
+	AuditActionUnassign AuditAction = "unassign"
+	AuditActionRestore  AuditAction = "restore"
+	AuditActionPurge    AuditAction = "purge"
+	AuditActionSend     AuditAction = "send"
+)
+
+// AuditEntity represents the kind of record an audit entry is about
+type AuditEntity string
+
+const (
+	AuditEntityProject       AuditEntity = "project"
+	AuditEntityProjectMember AuditEntity = "project_member"
+	AuditEntityPeriod        AuditEntity = "period"
+	AuditEntityLabour        AuditEntity = "labour"
+	AuditEntityAssignment    AuditEntity = "assignment"
+	AuditEntityWageRate      AuditEntity = "wage_rate"
+	AuditEntityWorkDay       AuditEntity = "work_day"
+	AuditEntityPayment       AuditEntity = "payment"
+	AuditEntityRecovery      AuditEntity = "advance_recovery"
+	AuditEntityPayrollRun    AuditEntity = "payroll_run"
+	AuditEntityPayrollItem   AuditEntity = "payroll_item"
+	AuditEntityBillingRate   AuditEntity = "billing_rate"
+	AuditEntityInvoice       AuditEntity = "invoice"
+	AuditEntityReceipt       AuditEntity = "invoice_receipt"
+)
+
+// AuditLog represents one change to a record. Before is empty for creations
+// and After for deletions. ProjectID and LabourID scope the entry for
+// filtering.
+type AuditLog struct {
+	ID         uuid.UUID       `json:"id" db:"id"`
+	ActorID    *uuid.UUID      `json:"actor_id,omitempty" db:"actor_id"`
+	ActorName  string          `json:"actor_name,omitempty" db:"-"`
+	EntityType AuditEntity     `json:"entity_type" db:"entity_type"`
+	EntityID   uuid.UUID       `json:"entity_id" db:"entity_id"`
+	ProjectID  *uuid.UUID      `json:"project_id,omitempty" db:"project_id"`
+	LabourID   *uuid.UUID      `json:"labour_id,omitempty" db:"labour_id"`
+	Action     AuditAction     `json:"action" db:"action"`
+	Before     json.RawMessage `json:"before,omitempty" db:"before"`
+	After      json.RawMessage `json:"after,omitempty" db:"after"`
+	CreatedAt  time.Time       `json:"created_at" db:"created_at"`
+}
+
+// AuditQuery represents the filters for listing audit entries
+type AuditQuery struct {
+	EntityType AuditEntity `form:"entity_type"`
+	EntityID   string      `form:"entity_id"`
+	Action     AuditAction `form:"action"`
+	ActorID    string      `form:"actor_id"`
+	From       string      `form:"from"` // Format: YYYY-MM-DD
+	To         string      `form:"to"`   // Format: YYYY-MM-DD, inclusive
+	Limit      int         `form:"limit" binding:"omitempty,min=1,max=500"`
+}
+
+// AuditFilter represents parsed audit filters. Nil fields don't filter.
+type AuditFilter struct {
+	EntityType *AuditEntity
+	EntityID   *uuid.UUID
+	Action     *AuditAction
+	ActorID    *uuid.UUID
+	From       *time.Time
+	Before     *time.Time // Exclusive upper bound
+	Limit      int
+}
@@ -0,0 +1,247 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+// BillingRate sets what a project's client is charged for labour. It applies
+// to one labour, to every labour in a role, or with neither to the whole
+// project. It is either a fixed day rate, with overtime passed on at cost, or
+// a markup on the wages earned.
+type BillingRate struct {
+	ID            uuid.UUID        `json:"id" db:"id"`
+	ProjectID     uuid.UUID        `json:"project_id" db:"project_id"`
+	LabourID      *uuid.UUID       `json:"labour_id,omitempty" db:"labour_id"`
+	Role          string           `json:"role,omitempty" db:"role"`
+	DayRate       *decimal.Decimal `json:"day_rate,omitempty" db:"day_rate"`
+	MarkupPercent *decimal.Decimal `json:"markup_percent,omitempty" db:"markup_percent"`
+	CreatedAt     time.Time        `json:"created_at" db:"created_at"`
+	UpdatedAt     time.Time        `json:"updated_at" db:"updated_at"`
+}
+
+// SetBillingRateRequest represents the request to set a billing rate,
+// replacing any rate with the same labour and role
+type SetBillingRateRequest struct {
+	LabourID      *uuid.UUID       `json:"labour_id"`
+	Role          string           `json:"role" binding:"max=100"`
+	DayRate       *decimal.Decimal `json:"day_rate"`
+	MarkupPercent *decimal.Decimal `json:"markup_percent"`
+}
+
+// Validate checks that the rate has one scope and exactly one kind of charge
+func (r *BillingRate) Validate() error {
+	if r.LabourID != nil && r.Role != "" {
+		return ErrInvalidBillingRate
+	}
+	if (r.DayRate == nil) == (r.MarkupPercent == nil) {
+		return ErrInvalidBillingRate
+	}
+	if r.DayRate != nil && !r.DayRate.IsPositive() {
+		return ErrInvalidBillingRate
+	}
+	if r.MarkupPercent != nil && (r.MarkupPercent.IsNegative() || r.MarkupPercent.GreaterThan(decimal.NewFromInt(1000))) {
+		return ErrInvalidBillingRate
+	}
+	return nil
+}
+
+// ResolveBillingRate returns the rate for a labour in the given role: their
+// own rate, else their role's, else the project's. It returns nil when none
+// applies, in which case the labour is billed at cost.
+func ResolveBillingRate(rates []BillingRate, labourID uuid.UUID, role string) *BillingRate {
+	var roleRate, projectRate *BillingRate
+	for i := range rates {
+		r := &rates[i]
+		switch {
+		case r.LabourID != nil:
+			if *r.LabourID == labourID {
+				return r
+			}
+		case r.Role != "":
+			if role != "" && r.Role == role {
+				roleRate = r
+			}
+		default:
+			projectRate = r
+		}
+	}
+	if roleRate != nil {
+		return roleRate
+	}
+	return projectRate
+}
+
+// markup returns the multiplier the rate applies to wages, 1 without a markup
+func (r *BillingRate) markup() decimal.Decimal {
+	if r == nil || r.MarkupPercent == nil {
+		return decimal.NewFromInt(1)
+	}
+	return decimal.NewFromInt(100).Add(*r.MarkupPercent).Div(decimal.NewFromInt(100))
+}
+
+// InvoiceStatus represents the state of an invoice
+type InvoiceStatus string
+
+const (
+	InvoiceStatusDraft InvoiceStatus = "draft"
+	InvoiceStatusSent  InvoiceStatus = "sent"
+	InvoiceStatusPaid  InvoiceStatus = "paid"
+)
+
+// InvoiceUnit represents what an invoice line's quantity counts
+type InvoiceUnit string
+
+const (
+	InvoiceUnitDay  InvoiceUnit = "day"
+	InvoiceUnitHour InvoiceUnit = "hour"
+)
+
+// Invoice represents a bill to a project's client for its attendance over a
+// period. Drafts have no number; sending assigns the next one in the
+// organisation.
+type Invoice struct {
+	ID             uuid.UUID       `json:"id" db:"id"`
+	OrganisationID uuid.UUID       `json:"organisation_id" db:"organisation_id"`
+	ProjectID      uuid.UUID       `json:"project_id" db:"project_id"`
+	Number         *int            `json:"number,omitempty" db:"number"`
+	PeriodStart    time.Time       `json:"period_start" db:"period_start"`
+	PeriodEnd      time.Time       `json:"period_end" db:"period_end"`
+	Status         InvoiceStatus   `json:"status" db:"status"`
+	ClientName     string          `json:"client_name,omitempty" db:"client_name"`
+	Total          decimal.Decimal `json:"total" db:"total"`
+	Received       decimal.Decimal `json:"received" db:"-"`
+	Notes          string          `json:"notes,omitempty" db:"notes"`
+	CreatedBy      *uuid.UUID      `json:"created_by,omitempty" db:"created_by"`
+	SentAt         *time.Time      `json:"sent_at,omitempty" db:"sent_at"`
+	PaidAt         *time.Time      `json:"paid_at,omitempty" db:"paid_at"`
+	CreatedAt      time.Time       `json:"created_at" db:"created_at"`
+	UpdatedAt      time.Time       `json:"updated_at" db:"updated_at"`
+}
+
+// InvoiceItem represents one line of an invoice
+type InvoiceItem struct {
+	ID          uuid.UUID       `json:"id" db:"id"`
+	InvoiceID   uuid.UUID       `json:"invoice_id" db:"invoice_id"`
+	LabourID    *uuid.UUID      `json:"labour_id,omitempty" db:"labour_id"`
+	Description string          `json:"description" db:"description"`
+	Unit        InvoiceUnit     `json:"unit" db:"unit"`
+	Quantity    decimal.Decimal `json:"quantity" db:"quantity"`
+	UnitRate    decimal.Decimal `json:"unit_rate" db:"unit_rate"`
+	Amount      decimal.Decimal `json:"amount" db:"amount"`
+}
+
+// InvoiceReceipt represents money received from the client against an invoice
+type InvoiceReceipt struct {
+	ID         uuid.UUID       `json:"id" db:"id"`
+	InvoiceID  uuid.UUID       `json:"invoice_id" db:"invoice_id"`
+	Amount     decimal.Decimal `json:"amount" db:"amount"`
+	ReceivedOn time.Time       `json:"received_on" db:"received_on"`
+	Reference  string          `json:"reference,omitempty" db:"reference"`
+	CreatedBy  *uuid.UUID      `json:"created_by,omitempty" db:"created_by"`
+	CreatedAt  time.Time       `json:"created_at" db:"created_at"`
+}
+
+// InvoiceWithDetails represents an invoice with its lines and receipts
+type InvoiceWithDetails struct {
+	Invoice
+	Items    []InvoiceItem    `json:"items"`
+	Receipts []InvoiceReceipt `json:"receipts"`
+}
+
+// CreateInvoiceRequest represents the request to draft an invoice
+type CreateInvoiceRequest struct {
+	PeriodStart string `json:"period_start" binding:"required"` // Format: YYYY-MM-DD
+	PeriodEnd   string `json:"period_end" binding:"required"`   // Format: YYYY-MM-DD
+	Notes       string `json:"notes" binding:"max=1000"`
+}
+
+// CreateInvoiceReceiptRequest represents the request to record a client receipt
+type CreateInvoiceReceiptRequest struct {
+	Amount     decimal.Decimal `json:"amount" binding:"required"`
+	ReceivedOn string          `json:"received_on" binding:"required"` // Format: YYYY-MM-DD
+	Reference  string          `json:"reference" binding:"max=255"`
+}
+
+// BillableLabour represents a labour's attendance on a project over an
+// invoice period
+type BillableLabour struct {
+	LabourID       uuid.UUID
+	LabourName     string
+	Role           string
+	Days           decimal.Decimal
+	Wages          decimal.Decimal // Earned for the days, without overtime
+	OvertimeHours  decimal.Decimal
+	OvertimeAmount decimal.Decimal
+}
+
+// NewInvoiceItems prices each labour's attendance at their billing rate: a
+// line for their days and, if they worked any, one for overtime hours
+func NewInvoiceItems(billable []BillableLabour, rates []BillingRate) []InvoiceItem {
+	var items []InvoiceItem
+	for _, b := range billable {
+		rate := ResolveBillingRate(rates, b.LabourID, b.Role)
+		labourID := b.LabourID
+		description := b.LabourName
+		if b.Role != "" {
+			description += " (" + b.Role + ")"
+		}
+
+		if b.Days.IsPositive() {
+			item := InvoiceItem{LabourID: &labourID, Description: description, Unit: InvoiceUnitDay, Quantity: b.Days}
+			if rate != nil && rate.DayRate != nil {
+				item.UnitRate = *rate.DayRate
+				item.Amount = b.Days.Mul(*rate.DayRate).Round(2)
+			} else {
+				item.Amount = b.Wages.Mul(rate.markup()).Round(2)
+				item.UnitRate = item.Amount.Div(b.Days).Round(2)
+			}
+			items = append(items, item)
+		}
+
+		if b.OvertimeHours.IsPositive() {
+			amount := b.OvertimeAmount.Mul(rate.markup()).Round(2)
+			items = append(items, InvoiceItem{
+				LabourID:    &labourID,
+				Description: description + " overtime",
+				Unit:        InvoiceUnitHour,
+				Quantity:    b.OvertimeHours,
+				UnitRate:    amount.Div(b.OvertimeHours).Round(2),
+				Amount:      amount,
+			})
+		}
+	}
+	return items
+}
+
+// InvoiceTotal returns the sum of the items' amounts
+func InvoiceTotal(items []InvoiceItem) decimal.Decimal {
+	total := decimal.Zero
+	for _, item := range items {
+		total = total.Add(item.Amount)
+	}
+	return total
+}
+
+// Outstanding returns what the client still owes on the invoice
+func (i *Invoice) Outstanding() decimal.Decimal {
+	return i.Total.Sub(i.Received)
+}
+
+// AcceptReceipt checks that a receipt of the given amount can be recorded
+// against the invoice: it must have been sent, and the amount must not be
+// more than is outstanding
+func (i *Invoice) AcceptReceipt(amount decimal.Decimal) error {
+	switch i.Status {
+	case InvoiceStatusDraft:
+		return ErrInvoiceNotSent
+	case InvoiceStatusPaid:
+		return ErrInvoicePaid
+	}
+	if !amount.IsPositive() || amount.GreaterThan(i.Outstanding()) {
+		return ErrInvalidAmount
+	}
+	return nil
+}
@@ -0,0 +1,111 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func dec(v string) *decimal.Decimal {
+	d := decimal.RequireFromString(v)
+	return &d
+}
+
+func TestBillingRate_Validate(t *testing.T) {
+	labourID := uuid.New()
+
+	for _, r := range []BillingRate{
+		{DayRate: dec("900")},
+		{Role: "mason", MarkupPercent: dec("15")},
+		{LabourID: &labourID, MarkupPercent: dec("0")},
+	} {
+		assert.NoError(t, r.Validate())
+	}
+
+	for _, r := range []BillingRate{
+		{},
+		{DayRate: dec("900"), MarkupPercent: dec("15")},
+		{DayRate: dec("0")},
+		{MarkupPercent: dec("-5")},
+		{LabourID: &labourID, Role: "mason", DayRate: dec("900")},
+	} {
+		assert.ErrorIs(t, r.Validate(), ErrInvalidBillingRate)
+	}
+}
+
+func TestResolveBillingRate(t *testing.T) {
+	ramesh, suresh, mahesh := uuid.New(), uuid.New(), uuid.New()
+	rates := []BillingRate{
+		{LabourID: &ramesh, DayRate: dec("1200")},
+		{Role: "mason", DayRate: dec("1000")},
+		{MarkupPercent: dec("20")},
+	}
+
+	assert.Equal(t, "1200", ResolveBillingRate(rates, ramesh, "mason").DayRate.String())
+	assert.Equal(t, "1000", ResolveBillingRate(rates, suresh, "mason").DayRate.String())
+	assert.Equal(t, "20", ResolveBillingRate(rates, mahesh, "").MarkupPercent.String())
+	assert.Nil(t, ResolveBillingRate(rates[:2], mahesh, "helper"))
+}
+
+func TestNewInvoiceItems(t *testing.T) {
+	mason, helper := uuid.New(), uuid.New()
+	billable := []BillableLabour{
+		{
+			LabourID: mason, LabourName: "Ramesh", Role: "mason",
+			Days: decimal.RequireFromString("5.5"), Wages: decimal.NewFromInt(4400),
+			OvertimeHours: decimal.NewFromInt(4), OvertimeAmount: decimal.NewFromInt(400),
+		},
+		{
+			LabourID: helper, LabourName: "Suresh",
+			Days: decimal.NewFromInt(6), Wages: decimal.NewFromInt(3000),
+			OvertimeHours: decimal.NewFromInt(2), OvertimeAmount: decimal.NewFromInt(125),
+		},
+	}
+	rates := []BillingRate{
+		{Role: "mason", DayRate: dec("1000")},
+		{MarkupPercent: dec("20")},
+	}
+
+	items := NewInvoiceItems(billable, rates)
+	require.Len(t, items, 4)
+
+	// Day rate, with overtime passed on at cost
+	assert.Equal(t, "Ramesh (mason)", items[0].Description)
+	assert.Equal(t, InvoiceUnitDay, items[0].Unit)
+	assert.Equal(t, "1000", items[0].UnitRate.String())
+	assert.Equal(t, "5500", items[0].Amount.String())
+	assert.Equal(t, "Ramesh (mason) overtime", items[1].Description)
+	assert.Equal(t, InvoiceUnitHour, items[1].Unit)
+	assert.Equal(t, "400", items[1].Amount.String())
+	assert.Equal(t, "100", items[1].UnitRate.String())
+
+	// Markup on wages and overtime
+	assert.Equal(t, "3600", items[2].Amount.String())
+	assert.Equal(t, "600", items[2].UnitRate.String())
+	assert.Equal(t, "150", items[3].Amount.String())
+	assert.Equal(t, "75", items[3].UnitRate.String())
+
+	assert.Equal(t, "9650", InvoiceTotal(items).String())
+
+	// Without a rate labour is billed at cost
+	items = NewInvoiceItems(billable[1:], nil)
+	assert.Equal(t, "3000", items[0].Amount.String())
+	assert.Equal(t, "500", items[0].UnitRate.String())
+}
+
+func TestInvoice_AcceptReceipt(t *testing.T) {
+	invoice := &Invoice{Status: InvoiceStatusSent, Total: decimal.NewFromInt(10000), Received: decimal.NewFromInt(4000)}
+
+	assert.NoError(t, invoice.AcceptReceipt(decimal.NewFromInt(6000)))
+	assert.ErrorIs(t, invoice.AcceptReceipt(decimal.NewFromInt(6001)), ErrInvalidAmount)
+	assert.ErrorIs(t, invoice.AcceptReceipt(decimal.Zero), ErrInvalidAmount)
+
+	invoice.Status = InvoiceStatusDraft
+	assert.ErrorIs(t, invoice.AcceptReceipt(decimal.NewFromInt(100)), ErrInvoiceNotSent)
+
+	invoice.Status = InvoiceStatusPaid
+	assert.ErrorIs(t, invoice.AcceptReceipt(decimal.NewFromInt(100)), ErrInvoicePaid)
+}
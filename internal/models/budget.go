@@ -0,0 +1,103 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+// BudgetAlert records a project's committed labour cost crossing a
+// percentage of its labour budget
+type BudgetAlert struct {
+	ID        uuid.UUID       `json:"id" db:"id"`
+	ProjectID uuid.UUID       `json:"project_id" db:"project_id"`
+	Threshold int             `json:"threshold" db:"threshold"` // Percent of the budget
+	Budget    decimal.Decimal `json:"budget" db:"budget"`
+	Committed decimal.Decimal `json:"committed" db:"committed"`
+	CreatedAt time.Time       `json:"created_at" db:"created_at"`
+}
+
+// ProjectBudget represents a project's labour spend against its budget, with
+// a projection to the planned end date at the average daily spend so far.
+// Fields that depend on a budget, start or end date are nil without one.
+type ProjectBudget struct {
+	ProjectID        uuid.UUID        `json:"project_id"`
+	LabourBudget     *decimal.Decimal `json:"labour_budget,omitempty"`
+	Committed        decimal.Decimal  `json:"committed"` // Earned on work days to date, including overtime
+	Remaining        *decimal.Decimal `json:"remaining,omitempty"`
+	UsedPercent      *decimal.Decimal `json:"used_percent,omitempty"`
+	StartDate        *time.Time       `json:"start_date,omitempty"` // Project start, or the first work day
+	EndDate          *time.Time       `json:"end_date,omitempty"`
+	AsOf             time.Time        `json:"as_of"`
+	DailyBurnRate    decimal.Decimal  `json:"daily_burn_rate"`
+	ProjectedCost    *decimal.Decimal `json:"projected_cost,omitempty"`
+	ProjectedOverrun *decimal.Decimal `json:"projected_overrun,omitempty"` // Zero when the projection is within budget
+	Alerts           []BudgetAlert    `json:"alerts"`
+}
+
+// NewProjectBudget builds a project's budget summary as of the given date
+// from its committed cost and the date of its first work day, if any
+func NewProjectBudget(project *Project, committed decimal.Decimal, firstWorkDate *time.Time, asOf time.Time) *ProjectBudget {
+	b := &ProjectBudget{
+		ProjectID:     project.ID,
+		LabourBudget:  project.LabourBudget,
+		Committed:     committed,
+		StartDate:     project.StartDate,
+		EndDate:       project.EndDate,
+		AsOf:          asOf,
+		DailyBurnRate: decimal.Zero,
+		Alerts:        []BudgetAlert{},
+	}
+	if b.StartDate == nil {
+		b.StartDate = firstWorkDate
+	}
+
+	if b.StartDate != nil && !b.StartDate.After(asOf) {
+		elapsed := decimal.NewFromInt(daysBetween(*b.StartDate, asOf) + 1)
+		b.DailyBurnRate = committed.Div(elapsed).Round(2)
+
+		if b.EndDate != nil {
+			remainingDays := decimal.NewFromInt(max(daysBetween(asOf, *b.EndDate), 0))
+			projected := committed.Add(committed.Mul(remainingDays).Div(elapsed)).Round(2)
+			b.ProjectedCost = &projected
+		}
+	}
+
+	if b.LabourBudget != nil {
+		remaining := b.LabourBudget.Sub(committed)
+		b.Remaining = &remaining
+		if b.LabourBudget.IsPositive() {
+			used := committed.Mul(decimal.NewFromInt(100)).Div(*b.LabourBudget).Round(2)
+			b.UsedPercent = &used
+		}
+		if b.ProjectedCost != nil {
+			overrun := decimal.Max(b.ProjectedCost.Sub(*b.LabourBudget), decimal.Zero)
+			b.ProjectedOverrun = &overrun
+		}
+	}
+
+	return b
+}
+
+// CrossedThresholds returns the thresholds, as percentages of the budget,
+// that the committed cost has reached
+func (b *ProjectBudget) CrossedThresholds(thresholds []int) []int {
+	if b.LabourBudget == nil || !b.LabourBudget.IsPositive() {
+		return nil
+	}
+
+	var crossed []int
+	for _, t := range thresholds {
+		limit := b.LabourBudget.Mul(decimal.NewFromInt(int64(t))).Div(decimal.NewFromInt(100))
+		if b.Committed.GreaterThanOrEqual(limit) {
+			crossed = append(crossed, t)
+		}
+	}
+	return crossed
+}
+
+// daysBetween returns the whole days from one date to another
+func daysBetween(from, to time.Time) int64 {
+	return int64(to.Sub(from).Hours() / 24)
+}
@@ -0,0 +1,65 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewProjectBudget(t *testing.T) {
+	start := time.Date(2024, time.March, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2024, time.March, 31, 0, 0, 0, 0, time.UTC)
+	asOf := time.Date(2024, time.March, 10, 0, 0, 0, 0, time.UTC)
+	budget := decimal.NewFromInt(25000)
+
+	t.Run("projects the daily burn rate to the end date", func(t *testing.T) {
+		project := &Project{StartDate: &start, EndDate: &end, LabourBudget: &budget}
+		b := NewProjectBudget(project, decimal.NewFromInt(10000), nil, asOf)
+
+		assert.Equal(t, "1000", b.DailyBurnRate.String())
+		require.NotNil(t, b.ProjectedCost)
+		assert.Equal(t, "31000", b.ProjectedCost.String())
+		assert.Equal(t, "6000", b.ProjectedOverrun.String())
+		assert.Equal(t, "15000", b.Remaining.String())
+		assert.Equal(t, "40", b.UsedPercent.String())
+	})
+
+	t.Run("starts from the first work day without a start date", func(t *testing.T) {
+		firstWorkDate := time.Date(2024, time.March, 6, 0, 0, 0, 0, time.UTC)
+		project := &Project{EndDate: &end}
+		b := NewProjectBudget(project, decimal.NewFromInt(5000), &firstWorkDate, asOf)
+
+		assert.Equal(t, "1000", b.DailyBurnRate.String())
+		assert.Equal(t, "26000", b.ProjectedCost.String())
+		assert.Nil(t, b.Remaining)
+		assert.Nil(t, b.ProjectedOverrun)
+	})
+
+	t.Run("no projection before work starts", func(t *testing.T) {
+		b := NewProjectBudget(&Project{EndDate: &end, LabourBudget: &budget}, decimal.Zero, nil, asOf)
+
+		assert.True(t, b.DailyBurnRate.IsZero())
+		assert.Nil(t, b.ProjectedCost)
+		assert.Equal(t, "0", b.UsedPercent.String())
+		assert.NotNil(t, b.Alerts)
+	})
+}
+
+func TestProjectBudget_CrossedThresholds(t *testing.T) {
+	budget := decimal.NewFromInt(10000)
+	thresholds := []int{50, 80, 100}
+
+	b := &ProjectBudget{LabourBudget: &budget, Committed: decimal.NewFromInt(8000)}
+	assert.Equal(t, []int{50, 80}, b.CrossedThresholds(thresholds))
+
+	b.Committed = decimal.NewFromInt(12000)
+	assert.Equal(t, []int{50, 80, 100}, b.CrossedThresholds(thresholds))
+
+	b.Committed = decimal.NewFromInt(4999)
+	assert.Empty(t, b.CrossedThresholds(thresholds))
+
+	assert.Empty(t, (&ProjectBudget{Committed: decimal.NewFromInt(8000)}).CrossedThresholds(thresholds))
+}
@@ -13,6 +13,52 @@ var (
 	ErrInvalidProject     = errors.New("invalid project")
 	ErrInvalidLabour      = errors.New("invalid labour")
 	ErrInvalidOTP         = errors.New("invalid or expired OTP")
+	ErrInvalidOvertime    = errors.New("invalid overtime hours")
+	ErrInvalidTime        = errors.New("invalid check-in or check-out time")
+	ErrInvalidToken       = errors.New("invalid or expired token")
+	ErrTokenReused        = errors.New("refresh token reuse detected")
+	ErrSessionRevoked     = errors.New("session has been revoked")
+	ErrInvalidRole        = errors.New("invalid role")
+	ErrLastOwner          = errors.New("organisation must keep at least one owner")
+	ErrInvalidCapability  = errors.New("invalid capability")
+	ErrInvalidReason      = errors.New("a reason is required")
+	ErrInvalidRecovery    = errors.New("invalid recovery plan")
+	ErrInvalidAuditFilter = errors.New("invalid audit filter")
+	ErrInvalidBillingRate = errors.New("invalid billing rate")
+)
+
+// Payroll and period errors
+var (
+	ErrPeriodLocked     = errors.New("period is closed, reopen it to make changes")
+	ErrPayrollApproved  = errors.New("payroll run is already approved")
+	ErrPayrollOverlap   = errors.New("an approved payroll run already covers this period")
+	ErrAdvanceRecovered = errors.New("advance has already been partly recovered")
+	ErrPaymentVoided    = errors.New("payment has been voided")
+	ErrProjectInactive  = errors.New("project is completed or archived, reopen it to make changes")
+)
+
+// Trash errors
+var (
+	ErrUnsettledBalance  = errors.New("labour has an unsettled balance")
+	ErrPurgeNotConfirmed = errors.New("confirm the purge by repeating the name")
+	ErrProjectSettled    = errors.New("project has approved payroll or issued invoices and cannot be purged")
+)
+
+// Invoice errors
+var (
+	ErrInvoiceOverlap  = errors.New("another invoice already covers part of this period")
+	ErrInvoiceEmpty    = errors.New("no billable attendance in this period")
+	ErrInvoiceNotDraft = errors.New("invoice has already been sent")
+	ErrInvoiceNotSent  = errors.New("invoice must be sent before recording receipts")
+	ErrInvoicePaid     = errors.New("invoice is already paid")
+)
+
+// Rate limit errors
+var (
+	ErrOTPCooldown    = errors.New("please wait before requesting another OTP")
+	ErrOTPSendLimit   = errors.New("too many OTP requests, try again later")
+	ErrOTPVerifyLimit = errors.New("too many verification attempts, try again later")
+	ErrOTPLocked      = errors.New("too many wrong codes, request a new OTP")
 )
 
 // Database errors
@@ -9,46 +9,71 @@ import (
 
 // Labour represents a labourer in the system
 type Labour struct {
-	ID        uuid.UUID       `json:"id" db:"id"`
-	Name      string          `json:"name" db:"name"`
-	Phone     string          `json:"phone,omitempty" db:"phone"`
-	DailyWage decimal.Decimal `json:"daily_wage" db:"daily_wage"`
-	CreatedAt time.Time       `json:"created_at" db:"created_at"`
-	UpdatedAt time.Time       `json:"updated_at" db:"updated_at"`
+	ID             uuid.UUID        `json:"id" db:"id"`
+	UserID         uuid.UUID        `json:"user_id" db:"user_id"` // Creator
+	OrganisationID uuid.UUID        `json:"organisation_id" db:"organisation_id"`
+	Name           string           `json:"name" db:"name"`
+	Phone          string           `json:"phone,omitempty" db:"phone"`
+	DailyWage      decimal.Decimal  `json:"daily_wage" db:"daily_wage"`
+	OvertimeRate   *decimal.Decimal `json:"overtime_rate,omitempty" db:"overtime_rate"` // Hourly, for work days recorded after it is set; nil = project or default rate
+	CreatedAt      time.Time        `json:"created_at" db:"created_at"`
+	UpdatedAt      time.Time        `json:"updated_at" db:"updated_at"`
+	DeletedAt      *time.Time       `json:"deleted_at,omitempty" db:"deleted_at"` // Set while the labour is in the trash
+	DeletedBy      *uuid.UUID       `json:"deleted_by,omitempty" db:"deleted_by"`
 }
 
 // ProjectLabour represents the association between a project and a labour
 type ProjectLabour struct {
-	ProjectID  uuid.UUID `json:"project_id" db:"project_id"`
-	LabourID   uuid.UUID `json:"labour_id" db:"labour_id"`
-	AssignedAt time.Time `json:"assigned_at" db:"assigned_at"`
+	ProjectID  uuid.UUID        `json:"project_id" db:"project_id"`
+	LabourID   uuid.UUID        `json:"labour_id" db:"labour_id"`
+	DailyWage  *decimal.Decimal `json:"daily_wage,omitempty" db:"daily_wage"` // nil = use the labour's own wage
+	Role       string           `json:"role,omitempty" db:"role"`
+	AssignedAt time.Time        `json:"assigned_at" db:"assigned_at"`
+}
+
+// AssignedLabour represents a labour together with their terms on a project
+type AssignedLabour struct {
+	Labour
+	ProjectDailyWage *decimal.Decimal `json:"project_daily_wage,omitempty"`
+	Role             string           `json:"role,omitempty"`
+	AssignedAt       time.Time        `json:"assigned_at"`
 }
 
 // LabourWithBalance represents a labour with their payment balance
 type LabourWithBalance struct {
 	Labour
-	TotalEarned decimal.Decimal `json:"total_earned"`
-	TotalPaid   decimal.Decimal `json:"total_paid"`
-	Balance     decimal.Decimal `json:"balance"` // TotalEarned - TotalPaid
+	TotalEarned        decimal.Decimal `json:"total_earned"`
+	OvertimeEarned     decimal.Decimal `json:"overtime_earned"` // Included in TotalEarned
+	TotalPaid          decimal.Decimal `json:"total_paid"`
+	TotalAdvances      decimal.Decimal `json:"total_advances"`      // Included in TotalPaid
+	AdvanceOutstanding decimal.Decimal `json:"advance_outstanding"` // Advances not yet recovered by payroll
+	Balance            decimal.Decimal `json:"balance"`             // TotalEarned - TotalPaid
 }
 
 // CreateLabourRequest represents the request to create a labour
 type CreateLabourRequest struct {
-	Name      string          `json:"name" binding:"required,max=255"`
-	Phone     string          `json:"phone" binding:"max=20"`
-	DailyWage decimal.Decimal `json:"daily_wage" binding:"required"`
+	OrganisationID *uuid.UUID       `json:"organisation_id"` // nil = the caller's own organisation
+	Name           string           `json:"name" binding:"required,max=255"`
+	Phone          string           `json:"phone" binding:"max=20"`
+	DailyWage      decimal.Decimal  `json:"daily_wage" binding:"required"`
+	OvertimeRate   *decimal.Decimal `json:"overtime_rate"`
 }
 
 // UpdateLabourRequest represents the request to update a labour
 type UpdateLabourRequest struct {
-	Name      string          `json:"name" binding:"required,max=255"`
-	Phone     string          `json:"phone" binding:"max=20"`
-	DailyWage decimal.Decimal `json:"daily_wage" binding:"required"`
+	Name              string           `json:"name" binding:"required,max=255"`
+	Phone             string           `json:"phone" binding:"max=20"`
+	DailyWage         decimal.Decimal  `json:"daily_wage" binding:"required"`
+	WageEffectiveFrom string           `json:"wage_effective_from"` // Format: YYYY-MM-DD, defaults to today
+	OvertimeRate      *decimal.Decimal `json:"overtime_rate"`
 }
 
 // AssignLabourRequest represents the request to assign a labour to a project
 type AssignLabourRequest struct {
-	LabourID uuid.UUID `json:"labour_id" binding:"required"`
+	LabourID          uuid.UUID        `json:"labour_id" binding:"required"`
+	DailyWage         *decimal.Decimal `json:"daily_wage"` // Optional project-specific wage
+	Role              string           `json:"role" binding:"max=100"`
+	WageEffectiveFrom string           `json:"wage_effective_from"` // Format: YYYY-MM-DD, defaults to today
 }
 
 // Validate validates the labour data
@@ -62,5 +87,22 @@ func (l *Labour) Validate() error {
 	if l.DailyWage.IsNegative() {
 		return ErrInvalidAmount
 	}
+	if l.OvertimeRate != nil && l.OvertimeRate.IsNegative() {
+		return ErrInvalidAmount
+	}
+	return nil
+}
+
+// Validate validates the project labour assignment
+func (pl *ProjectLabour) Validate() error {
+	if pl.ProjectID == uuid.Nil {
+		return ErrInvalidProject
+	}
+	if pl.LabourID == uuid.Nil {
+		return ErrInvalidLabour
+	}
+	if pl.DailyWage != nil && pl.DailyWage.IsNegative() {
+		return ErrInvalidAmount
+	}
 	return nil
 }
@@ -0,0 +1,111 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+// LedgerEntryKind distinguishes earnings from payments in a ledger
+type LedgerEntryKind string
+
+const (
+	LedgerEntryEarning LedgerEntryKind = "earning"
+	LedgerEntryPayment LedgerEntryKind = "payment"
+)
+
+// LedgerEntry represents one line of a labour's statement. Earnings and
+// refunds are credits, other payments are debits; Balance is the running
+// balance after it.
+type LedgerEntry struct {
+	Date        time.Time       `json:"date"`
+	Kind        LedgerEntryKind `json:"kind"`
+	ReferenceID uuid.UUID       `json:"reference_id"` // Work day or payment ID
+	ProjectID   uuid.UUID       `json:"project_id"`
+	ProjectName string          `json:"project_name"`
+	Description string          `json:"description"` // Attendance status or payment type
+	Credit      decimal.Decimal `json:"credit"`
+	Debit       decimal.Decimal `json:"debit"`
+	Balance     decimal.Decimal `json:"balance"`
+}
+
+// LedgerProjectSubtotal represents a labour's totals on one project over the ledger period
+type LedgerProjectSubtotal struct {
+	ProjectID      uuid.UUID       `json:"project_id"`
+	ProjectName    string          `json:"project_name"`
+	OpeningBalance decimal.Decimal `json:"opening_balance"`
+	Earned         decimal.Decimal `json:"earned"`
+	Paid           decimal.Decimal `json:"paid"`
+	ClosingBalance decimal.Decimal `json:"closing_balance"`
+}
+
+// Ledger represents a labour's chronological statement across projects
+type Ledger struct {
+	LabourID       uuid.UUID               `json:"labour_id"`
+	LabourName     string                  `json:"labour_name"`
+	From           *time.Time              `json:"from,omitempty"`
+	To             *time.Time              `json:"to,omitempty"`
+	OpeningBalance decimal.Decimal         `json:"opening_balance"`
+	TotalEarned    decimal.Decimal         `json:"total_earned"`
+	TotalPaid      decimal.Decimal         `json:"total_paid"`
+	ClosingBalance decimal.Decimal         `json:"closing_balance"` // Positive = due, negative = overpaid
+	Projects       []LedgerProjectSubtotal `json:"projects"`
+	Entries        []LedgerEntry           `json:"entries"`
+}
+
+// NewLedger builds a ledger from entries in chronological order, filling in
+// the running balance and per-project subtotals. opening holds each
+// project's balance before the first entry.
+func NewLedger(labour *Labour, projects []Project, opening map[uuid.UUID]decimal.Decimal, entries []LedgerEntry) *Ledger {
+	ledger := &Ledger{
+		LabourID:       labour.ID,
+		LabourName:     labour.Name,
+		OpeningBalance: decimal.Zero,
+		TotalEarned:    decimal.Zero,
+		TotalPaid:      decimal.Zero,
+		Projects:       make([]LedgerProjectSubtotal, 0, len(projects)),
+		Entries:        entries,
+	}
+
+	index := make(map[uuid.UUID]int, len(projects))
+	for _, p := range projects {
+		index[p.ID] = len(ledger.Projects)
+		ledger.Projects = append(ledger.Projects, LedgerProjectSubtotal{
+			ProjectID:      p.ID,
+			ProjectName:    p.Name,
+			OpeningBalance: opening[p.ID],
+			Earned:         decimal.Zero,
+			Paid:           decimal.Zero,
+		})
+		ledger.OpeningBalance = ledger.OpeningBalance.Add(opening[p.ID])
+	}
+
+	balance := ledger.OpeningBalance
+	for i := range ledger.Entries {
+		e := &ledger.Entries[i]
+		balance = balance.Add(e.Credit).Sub(e.Debit)
+		e.Balance = balance
+
+		earned, paid := e.Credit, e.Debit
+		if e.Kind == LedgerEntryPayment {
+			// Refunds are credits that reduce what has been paid
+			earned, paid = decimal.Zero, e.Debit.Sub(e.Credit)
+		}
+
+		ledger.TotalEarned = ledger.TotalEarned.Add(earned)
+		ledger.TotalPaid = ledger.TotalPaid.Add(paid)
+		if j, ok := index[e.ProjectID]; ok {
+			ledger.Projects[j].Earned = ledger.Projects[j].Earned.Add(earned)
+			ledger.Projects[j].Paid = ledger.Projects[j].Paid.Add(paid)
+		}
+	}
+	ledger.ClosingBalance = balance
+
+	for i := range ledger.Projects {
+		p := &ledger.Projects[i]
+		p.ClosingBalance = p.OpeningBalance.Add(p.Earned).Sub(p.Paid)
+	}
+
+	return ledger
+}
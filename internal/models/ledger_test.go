@@ -0,0 +1,74 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewLedger(t *testing.T) {
+	labour := &Labour{ID: uuid.New(), Name: "Ramesh"}
+	siteA := Project{ID: uuid.New(), Name: "Site A"}
+	siteB := Project{ID: uuid.New(), Name: "Site B"}
+
+	entry := func(p Project, kind LedgerEntryKind, amount int64) LedgerEntry {
+		e := LedgerEntry{Kind: kind, ProjectID: p.ID, ProjectName: p.Name, Credit: decimal.Zero, Debit: decimal.Zero}
+		if kind == LedgerEntryEarning {
+			e.Credit = decimal.NewFromInt(amount)
+		} else {
+			e.Debit = decimal.NewFromInt(amount)
+		}
+		return e
+	}
+
+	opening := map[uuid.UUID]decimal.Decimal{siteA.ID: decimal.NewFromInt(100)}
+	entries := []LedgerEntry{
+		entry(siteA, LedgerEntryEarning, 500),
+		entry(siteB, LedgerEntryEarning, 600),
+		entry(siteA, LedgerEntryPayment, 300),
+		entry(siteB, LedgerEntryPayment, 1000),
+	}
+
+	ledger := NewLedger(labour, []Project{siteA, siteB}, opening, entries)
+
+	t.Run("running balance starts from the opening balance", func(t *testing.T) {
+		require.Len(t, ledger.Entries, 4)
+		assert.Equal(t, "100", ledger.OpeningBalance.String())
+		assert.Equal(t, "600", ledger.Entries[0].Balance.String())
+		assert.Equal(t, "1200", ledger.Entries[1].Balance.String())
+		assert.Equal(t, "900", ledger.Entries[2].Balance.String())
+		assert.Equal(t, "-100", ledger.Entries[3].Balance.String())
+	})
+
+	t.Run("totals cover the period only", func(t *testing.T) {
+		assert.Equal(t, "1100", ledger.TotalEarned.String())
+		assert.Equal(t, "1300", ledger.TotalPaid.String())
+		assert.Equal(t, "-100", ledger.ClosingBalance.String())
+	})
+
+	t.Run("per-project subtotals", func(t *testing.T) {
+		require.Len(t, ledger.Projects, 2)
+		assert.Equal(t, "300", ledger.Projects[0].ClosingBalance.String())
+		assert.Equal(t, "0", ledger.Projects[1].OpeningBalance.String())
+		assert.Equal(t, "-400", ledger.Projects[1].ClosingBalance.String())
+	})
+
+	t.Run("refunds are credited against payments", func(t *testing.T) {
+		refund := LedgerEntry{Kind: LedgerEntryPayment, ProjectID: siteB.ID, Credit: decimal.NewFromInt(200), Debit: decimal.Zero}
+		withRefund := NewLedger(labour, []Project{siteA, siteB}, opening, append(append([]LedgerEntry{}, entries...), refund))
+
+		assert.Equal(t, "1100", withRefund.TotalEarned.String())
+		assert.Equal(t, "1100", withRefund.TotalPaid.String())
+		assert.Equal(t, "100", withRefund.ClosingBalance.String())
+		assert.Equal(t, "-200", withRefund.Projects[1].ClosingBalance.String())
+	})
+
+	t.Run("empty ledger", func(t *testing.T) {
+		empty := NewLedger(labour, nil, nil, nil)
+		assert.True(t, empty.ClosingBalance.IsZero())
+		assert.Empty(t, empty.Projects)
+	})
+}
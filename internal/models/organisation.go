@@ -0,0 +1,152 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Role represents a member's role in an organisation
+type Role string
+
+const (
+	RoleOwner      Role = "owner"
+	RoleSupervisor Role = "supervisor"
+	RoleAccountant Role = "accountant"
+	RoleViewer     Role = "viewer"
+)
+
+// IsValid checks if the role is valid
+func (r Role) IsValid() bool {
+	switch r {
+	case RoleOwner, RoleSupervisor, RoleAccountant, RoleViewer:
+		return true
+	}
+	return false
+}
+
+// Permission represents an action a member may take on organisation data
+type Permission string
+
+const (
+	PermProjectsRead    Permission = "projects:read"
+	PermProjectsWrite   Permission = "projects:write"
+	PermLaboursRead     Permission = "labours:read"
+	PermLaboursWrite    Permission = "labours:write"
+	PermAttendanceRead  Permission = "attendance:read"
+	PermAttendanceWrite Permission = "attendance:write"
+	PermPaymentsRead    Permission = "payments:read"
+	PermPaymentsWrite   Permission = "payments:write"
+	PermMembersManage   Permission = "members:manage"
+	PermPeriodsClose    Permission = "periods:close"
+	PermPeriodsReopen   Permission = "periods:reopen"
+	PermBillingRead     Permission = "billing:read"
+	PermBillingWrite    Permission = "billing:write"
+	PermAuditRead       Permission = "audit:read"
+	PermTrashPurge      Permission = "trash:purge"
+)
+
+// rolePermissions lists what each role may do. Owners may do everything.
+// Periods, billing and audit permissions come only from a role, never from
+// project member capabilities.
+var rolePermissions = map[Role][]Permission{
+	RoleSupervisor: {
+		PermProjectsRead, PermLaboursRead, PermLaboursWrite,
+		PermAttendanceRead, PermAttendanceWrite,
+	},
+	RoleAccountant: {
+		PermProjectsRead, PermLaboursRead, PermAttendanceRead,
+		PermPaymentsRead, PermPaymentsWrite, PermPeriodsClose,
+		PermBillingRead, PermBillingWrite, PermAuditRead,
+	},
+	RoleViewer: {
+		PermProjectsRead, PermLaboursRead, PermAttendanceRead, PermPaymentsRead,
+		PermBillingRead, PermAuditRead,
+	},
+}
+
+// Can reports whether the role grants the permission
+func (r Role) Can(perm Permission) bool {
+	if r == RoleOwner {
+		return true
+	}
+	for _, p := range rolePermissions[r] {
+		if p == perm {
+			return true
+		}
+	}
+	return false
+}
+
+// Organisation represents a contracting business that owns projects and labours
+type Organisation struct {
+	ID        uuid.UUID  `json:"id" db:"id"`
+	Name      string     `json:"name" db:"name"`
+	CreatedBy *uuid.UUID `json:"created_by,omitempty" db:"created_by"`
+	CreatedAt time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at" db:"updated_at"`
+}
+
+// OrganisationWithRole represents an organisation together with the caller's role in it
+type OrganisationWithRole struct {
+	Organisation
+	Role Role `json:"role"`
+}
+
+// OrganisationMember represents a user's membership of an organisation
+type OrganisationMember struct {
+	OrganisationID uuid.UUID `json:"organisation_id" db:"organisation_id"`
+	UserID         uuid.UUID `json:"user_id" db:"user_id"`
+	Phone          string    `json:"phone" db:"phone"`
+	Name           string    `json:"name,omitempty" db:"name"`
+	Role           Role      `json:"role" db:"role"`
+	CreatedAt      time.Time `json:"created_at" db:"created_at"`
+}
+
+// Invitation represents an invitation for a phone number to join an organisation
+type Invitation struct {
+	ID               uuid.UUID  `json:"id" db:"id"`
+	OrganisationID   uuid.UUID  `json:"organisation_id" db:"organisation_id"`
+	OrganisationName string     `json:"organisation_name,omitempty" db:"organisation_name"`
+	Phone            string     `json:"phone" db:"phone"`
+	Role             Role       `json:"role" db:"role"`
+	InvitedBy        *uuid.UUID `json:"invited_by,omitempty" db:"invited_by"`
+	ExpiresAt        time.Time  `json:"expires_at" db:"expires_at"`
+	AcceptedAt       *time.Time `json:"accepted_at,omitempty" db:"accepted_at"`
+	CreatedAt        time.Time  `json:"created_at" db:"created_at"`
+}
+
+// CreateOrganisationRequest represents the request to create an organisation
+type CreateOrganisationRequest struct {
+	Name string `json:"name" binding:"required,max=255"`
+}
+
+// CreateInvitationRequest represents the request to invite a phone number
+type CreateInvitationRequest struct {
+	Phone string `json:"phone" binding:"required,min=10,max=15"`
+	Role  Role   `json:"role" binding:"required"`
+}
+
+// UpdateMemberRequest represents the request to change a member's role
+type UpdateMemberRequest struct {
+	Role Role `json:"role" binding:"required"`
+}
+
+// Validate validates the organisation data
+func (o *Organisation) Validate() error {
+	if o.Name == "" || len(o.Name) > 255 {
+		return ErrInvalidName
+	}
+	return nil
+}
+
+// Validate validates the invitation data
+func (i *Invitation) Validate() error {
+	if len(i.Phone) < 10 || len(i.Phone) > 15 {
+		return ErrInvalidPhone
+	}
+	if !i.Role.IsValid() {
+		return ErrInvalidRole
+	}
+	return nil
+}
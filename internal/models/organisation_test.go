@@ -0,0 +1,48 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRole_Can(t *testing.T) {
+	t.Run("owner can do everything", func(t *testing.T) {
+		assert.True(t, RoleOwner.Can(PermMembersManage))
+		assert.True(t, RoleOwner.Can(PermPaymentsWrite))
+		assert.True(t, RoleOwner.Can(PermProjectsWrite))
+		assert.True(t, RoleOwner.Can(PermPeriodsReopen))
+		assert.True(t, RoleOwner.Can(PermTrashPurge))
+		assert.True(t, RoleOwner.Can(PermBillingWrite))
+	})
+
+	t.Run("supervisor marks attendance but cannot see payments", func(t *testing.T) {
+		assert.True(t, RoleSupervisor.Can(PermAttendanceWrite))
+		assert.True(t, RoleSupervisor.Can(PermLaboursWrite))
+		assert.False(t, RoleSupervisor.Can(PermPaymentsRead))
+		assert.False(t, RoleSupervisor.Can(PermProjectsWrite))
+	})
+
+	t.Run("accountant records payments but not attendance", func(t *testing.T) {
+		assert.True(t, RoleAccountant.Can(PermPaymentsWrite))
+		assert.True(t, RoleAccountant.Can(PermAttendanceRead))
+		assert.False(t, RoleAccountant.Can(PermAttendanceWrite))
+		assert.False(t, RoleAccountant.Can(PermPeriodsReopen))
+		assert.False(t, RoleAccountant.Can(PermTrashPurge))
+		assert.True(t, RoleAccountant.Can(PermPeriodsClose))
+		assert.True(t, RoleAccountant.Can(PermBillingWrite))
+		assert.True(t, RoleAccountant.Can(PermAuditRead))
+	})
+
+	t.Run("viewer is read-only", func(t *testing.T) {
+		assert.True(t, RoleViewer.Can(PermPaymentsRead))
+		assert.False(t, RoleViewer.Can(PermAttendanceWrite))
+		assert.False(t, RoleViewer.Can(PermPaymentsWrite))
+		assert.False(t, RoleViewer.Can(PermBillingWrite))
+		assert.False(t, RoleViewer.Can(PermPeriodsClose))
+	})
+
+	t.Run("no role grants nothing", func(t *testing.T) {
+		assert.False(t, Role("").Can(PermProjectsRead))
+	})
+}
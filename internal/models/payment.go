@@ -14,17 +14,29 @@ const (
 	PaymentTypeAdvance   PaymentType = "advance"
 	PaymentTypeDailyWage PaymentType = "daily_wage"
 	PaymentTypeBonus     PaymentType = "bonus"
+	PaymentTypeDeduction PaymentType = "deduction" // Fines and charges withheld from the labour
+	PaymentTypeRefund    PaymentType = "refund"    // Money the labour paid back
 )
 
 // IsValid checks if the payment type is valid
 func (pt PaymentType) IsValid() bool {
 	switch pt {
-	case PaymentTypeAdvance, PaymentTypeDailyWage, PaymentTypeBonus:
+	case PaymentTypeAdvance, PaymentTypeDailyWage, PaymentTypeBonus, PaymentTypeDeduction, PaymentTypeRefund:
 		return true
 	}
 	return false
 }
 
+// Sign returns how a payment of this type moves the labour's balance: 1 when
+// it settles what the labour is owed, -1 when it adds to it. Amounts are
+// always stored positive.
+func (pt PaymentType) Sign() int {
+	if pt == PaymentTypeRefund {
+		return -1
+	}
+	return 1
+}
+
 // Payment represents a payment made to a labour
 type Payment struct {
 	ID          uuid.UUID       `json:"id" db:"id"`
@@ -35,6 +47,29 @@ type Payment struct {
 	PaymentType PaymentType     `json:"payment_type" db:"payment_type"`
 	Notes       string          `json:"notes,omitempty" db:"notes"`
 	CreatedAt   time.Time       `json:"created_at" db:"created_at"`
+	UpdatedAt   time.Time       `json:"updated_at" db:"updated_at"`
+
+	// Recovery plan, advances only
+	RecoveryType  *RecoveryType    `json:"recovery_type,omitempty" db:"recovery_type"`
+	RecoveryValue *decimal.Decimal `json:"recovery_value,omitempty" db:"recovery_value"`
+
+	// Set once voided; voided payments don't count towards balances
+	VoidedAt   *time.Time `json:"voided_at,omitempty" db:"voided_at"`
+	VoidedBy   *uuid.UUID `json:"voided_by,omitempty" db:"voided_by"`
+	VoidReason string     `json:"void_reason,omitempty" db:"void_reason"`
+}
+
+// AdvanceRecovery represents part of an advance recovered through a payroll
+// deduction
+type AdvanceRecovery struct {
+	ID            uuid.UUID       `json:"id" db:"id"`
+	AdvanceID     uuid.UUID       `json:"advance_id" db:"advance_id"`
+	PayrollItemID *uuid.UUID      `json:"payroll_item_id,omitempty" db:"payroll_item_id"`
+	ProjectID     uuid.UUID       `json:"project_id"`
+	LabourID      uuid.UUID       `json:"labour_id"`
+	Amount        decimal.Decimal `json:"amount" db:"amount"`
+	RecoveredOn   time.Time       `json:"recovered_on" db:"recovered_on"`
+	CreatedAt     time.Time       `json:"created_at" db:"created_at"`
 }
 
 // PaymentWithLabour represents a payment with labour details
@@ -43,6 +78,12 @@ type PaymentWithLabour struct {
 	LabourName string `json:"labour_name"`
 }
 
+// PaymentWithProject represents a payment with project details
+type PaymentWithProject struct {
+	Payment
+	ProjectName string `json:"project_name"`
+}
+
 // CreatePaymentRequest represents the request to create a payment
 type CreatePaymentRequest struct {
 	LabourID    uuid.UUID       `json:"labour_id" binding:"required"`
@@ -50,15 +91,92 @@ type CreatePaymentRequest struct {
 	PaymentDate string          `json:"payment_date" binding:"required"` // Format: YYYY-MM-DD
 	PaymentType PaymentType     `json:"payment_type" binding:"required"`
 	Notes       string          `json:"notes" binding:"max=500"`
+
+	// Optional recovery plan for advances
+	RecoveryType  *RecoveryType    `json:"recovery_type"`
+	RecoveryValue *decimal.Decimal `json:"recovery_value"`
+}
+
+// UpdatePaymentRequest represents the request to correct a payment
+type UpdatePaymentRequest struct {
+	Amount      decimal.Decimal `json:"amount" binding:"required"`
+	PaymentDate string          `json:"payment_date" binding:"required"` // Format: YYYY-MM-DD
+	PaymentType PaymentType     `json:"payment_type" binding:"required"`
+	Notes       string          `json:"notes" binding:"max=500"`
+}
+
+// VoidPaymentRequest represents the request to void a payment
+type VoidPaymentRequest struct {
+	Reason string `json:"reason" binding:"required,max=500"`
+}
+
+// IsVoided reports whether the payment has been voided
+func (p *Payment) IsVoided() bool {
+	return p.VoidedAt != nil
 }
 
 // BalanceResponse represents the balance for a labour
 type BalanceResponse struct {
-	LabourID    uuid.UUID       `json:"labour_id"`
-	LabourName  string          `json:"labour_name"`
-	TotalEarned decimal.Decimal `json:"total_earned"`
-	TotalPaid   decimal.Decimal `json:"total_paid"`
-	Balance     decimal.Decimal `json:"balance"` // TotalEarned - TotalPaid (positive = due, negative = overpaid)
+	LabourID       uuid.UUID       `json:"labour_id"`
+	LabourName     string          `json:"labour_name"`
+	TotalEarned    decimal.Decimal `json:"total_earned"`
+	OvertimeEarned decimal.Decimal `json:"overtime_earned"` // Included in TotalEarned
+	TotalPaid      decimal.Decimal `json:"total_paid"`      // Payments and deductions less refunds
+	Balance        decimal.Decimal `json:"balance"`         // TotalEarned - TotalPaid (positive = due, negative = overpaid)
+}
+
+// ProjectBalance represents a labour's balance on one project
+type ProjectBalance struct {
+	ProjectID      uuid.UUID       `json:"project_id"`
+	ProjectName    string          `json:"project_name"`
+	LabourID       uuid.UUID       `json:"labour_id"`
+	TotalEarned    decimal.Decimal `json:"total_earned"`
+	OvertimeEarned decimal.Decimal `json:"overtime_earned"` // Included in TotalEarned
+	TotalPaid      decimal.Decimal `json:"total_paid"`
+	Balance        decimal.Decimal `json:"balance"` // TotalEarned - TotalPaid
+}
+
+// CombinedBalanceResponse represents a labour's balances across projects with totals
+type CombinedBalanceResponse struct {
+	Projects    []ProjectBalance `json:"projects"`
+	TotalEarned decimal.Decimal  `json:"total_earned"`
+	TotalPaid   decimal.Decimal  `json:"total_paid"`
+	Balance     decimal.Decimal  `json:"balance"`
+}
+
+// ProjectBalanceSheet represents the balances of every labour assigned to a project
+type ProjectBalanceSheet struct {
+	ProjectID          uuid.UUID           `json:"project_id"`
+	ProjectName        string              `json:"project_name"`
+	Labours            []LabourWithBalance `json:"labours"`
+	TotalEarned        decimal.Decimal     `json:"total_earned"`
+	TotalPaid          decimal.Decimal     `json:"total_paid"`
+	AdvanceOutstanding decimal.Decimal     `json:"advance_outstanding"`
+	Balance            decimal.Decimal     `json:"balance"`
+}
+
+// NewProjectBalanceSheet builds a balance sheet with project totals
+func NewProjectBalanceSheet(project *Project, labours []LabourWithBalance) *ProjectBalanceSheet {
+	sheet := &ProjectBalanceSheet{
+		ProjectID:          project.ID,
+		ProjectName:        project.Name,
+		Labours:            labours,
+		TotalEarned:        decimal.Zero,
+		TotalPaid:          decimal.Zero,
+		AdvanceOutstanding: decimal.Zero,
+	}
+	if sheet.Labours == nil {
+		sheet.Labours = []LabourWithBalance{}
+	}
+
+	for _, l := range labours {
+		sheet.TotalEarned = sheet.TotalEarned.Add(l.TotalEarned)
+		sheet.TotalPaid = sheet.TotalPaid.Add(l.TotalPaid)
+		sheet.AdvanceOutstanding = sheet.AdvanceOutstanding.Add(l.AdvanceOutstanding)
+	}
+	sheet.Balance = sheet.TotalEarned.Sub(sheet.TotalPaid)
+
+	return sheet
 }
 
 // Validate validates the payment data
@@ -78,5 +196,5 @@ func (p *Payment) Validate() error {
 	if !p.PaymentType.IsValid() {
 		return ErrInvalidPaymentType
 	}
-	return nil
+	return p.validateRecovery()
 }
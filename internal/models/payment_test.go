@@ -0,0 +1,49 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPaymentType_Sign(t *testing.T) {
+	assert.Equal(t, 1, PaymentTypeAdvance.Sign())
+	assert.Equal(t, 1, PaymentTypeDailyWage.Sign())
+	assert.Equal(t, 1, PaymentTypeBonus.Sign())
+	assert.Equal(t, 1, PaymentTypeDeduction.Sign())
+	assert.Equal(t, -1, PaymentTypeRefund.Sign())
+}
+
+func TestNewProjectBalanceSheet(t *testing.T) {
+	project := &Project{ID: uuid.New(), Name: "Site A"}
+
+	balance := func(earned, paid, outstanding int64) LabourWithBalance {
+		return LabourWithBalance{
+			TotalEarned:        decimal.NewFromInt(earned),
+			TotalPaid:          decimal.NewFromInt(paid),
+			AdvanceOutstanding: decimal.NewFromInt(outstanding),
+			Balance:            decimal.NewFromInt(earned - paid),
+		}
+	}
+
+	t.Run("sums labour balances into project totals", func(t *testing.T) {
+		sheet := NewProjectBalanceSheet(project, []LabourWithBalance{
+			balance(5000, 3000, 0),
+			balance(1000, 1500, 500),
+		})
+
+		assert.Equal(t, project.ID, sheet.ProjectID)
+		assert.Equal(t, "6000", sheet.TotalEarned.String())
+		assert.Equal(t, "4500", sheet.TotalPaid.String())
+		assert.Equal(t, "500", sheet.AdvanceOutstanding.String())
+		assert.Equal(t, "1500", sheet.Balance.String())
+	})
+
+	t.Run("project without labours", func(t *testing.T) {
+		sheet := NewProjectBalanceSheet(project, nil)
+		assert.NotNil(t, sheet.Labours)
+		assert.True(t, sheet.Balance.IsZero())
+	})
+}
@@ -0,0 +1,102 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+// PayrollStatus represents the state of a payroll run
+type PayrollStatus string
+
+const (
+	PayrollStatusDraft    PayrollStatus = "draft"
+	PayrollStatusApproved PayrollStatus = "approved"
+)
+
+// PayrollApprovePermissions are all needed to approve a payroll run.
+// Approval closes the period as well as paying it, so it needs periods:close,
+// which only an organisation role grants.
+var PayrollApprovePermissions = []Permission{PermPaymentsWrite, PermPeriodsClose}
+
+// PayrollRun represents the settlement of a period's earnings for one
+// project, or every project in an organisation when ProjectID is nil
+type PayrollRun struct {
+	ID                    uuid.UUID       `json:"id" db:"id"`
+	OrganisationID        uuid.UUID       `json:"organisation_id" db:"organisation_id"`
+	ProjectID             *uuid.UUID      `json:"project_id,omitempty" db:"project_id"`
+	PeriodStart           time.Time       `json:"period_start" db:"period_start"`
+	PeriodEnd             time.Time       `json:"period_end" db:"period_end"`
+	Status                PayrollStatus   `json:"status" db:"status"`
+	CreatedBy             *uuid.UUID      `json:"created_by,omitempty" db:"created_by"`
+	ApprovedBy            *uuid.UUID      `json:"approved_by,omitempty" db:"approved_by"`
+	ApprovedAt            *time.Time      `json:"approved_at,omitempty" db:"approved_at"`
+	CreatedAt             time.Time       `json:"created_at" db:"created_at"`
+	UpdatedAt             time.Time       `json:"updated_at" db:"updated_at"`
+	ItemCount             int             `json:"item_count" db:"-"`
+	TotalEarned           decimal.Decimal `json:"total_earned" db:"-"`
+	TotalAdvanceDeduction decimal.Decimal `json:"total_advance_deduction" db:"-"`
+	TotalNet              decimal.Decimal `json:"total_net" db:"-"`
+}
+
+// PayrollItem represents one labour's pay on one project in a payroll run
+type PayrollItem struct {
+	ID               uuid.UUID       `json:"id" db:"id"`
+	PayrollRunID     uuid.UUID       `json:"payroll_run_id" db:"payroll_run_id"`
+	ProjectID        uuid.UUID       `json:"project_id" db:"project_id"`
+	ProjectName      string          `json:"project_name" db:"-"`
+	LabourID         uuid.UUID       `json:"labour_id" db:"labour_id"`
+	LabourName       string          `json:"labour_name" db:"-"`
+	DaysWorked       decimal.Decimal `json:"days_worked" db:"days_worked"`
+	Earned           decimal.Decimal `json:"earned" db:"earned"`
+	AdvanceDeduction decimal.Decimal `json:"advance_deduction" db:"advance_deduction"` // Advance instalments recovered from the earnings
+	Adjustment       decimal.Decimal `json:"adjustment" db:"adjustment"`               // Manual correction, may be negative
+	NetAmount        decimal.Decimal `json:"net_amount" db:"net_amount"`               // Earned - AdvanceDeduction + Adjustment
+	Notes            string          `json:"notes,omitempty" db:"notes"`
+	PaymentID        *uuid.UUID      `json:"payment_id,omitempty" db:"payment_id"` // Set once approved
+}
+
+// PayrollRunWithItems represents a payroll run with its items
+type PayrollRunWithItems struct {
+	PayrollRun
+	Items []PayrollItem `json:"items"`
+}
+
+// CreatePayrollRunRequest represents the request to draft a payroll run.
+// Without a project the run covers every project in the organisation.
+type CreatePayrollRunRequest struct {
+	OrganisationID *uuid.UUID `json:"organisation_id"` // nil = the caller's own organisation
+	ProjectID      *uuid.UUID `json:"project_id"`
+	PeriodStart    string     `json:"period_start" binding:"required"` // Format: YYYY-MM-DD
+	PeriodEnd      string     `json:"period_end" binding:"required"`   // Format: YYYY-MM-DD
+}
+
+// UpdatePayrollItemRequest represents the request to edit a draft payroll item
+type UpdatePayrollItemRequest struct {
+	AdvanceDeduction decimal.Decimal `json:"advance_deduction"`
+	Adjustment       decimal.Decimal `json:"adjustment"`
+	Notes            string          `json:"notes" binding:"max=500"`
+}
+
+// ApprovePayrollRunRequest represents the request to approve a payroll run
+type ApprovePayrollRunRequest struct {
+	PaymentDate string `json:"payment_date"` // Format: YYYY-MM-DD, defaults to the period end
+}
+
+// Recalculate updates the net amount from the item's other amounts. The
+// advance deduction may not exceed what the labour has outstanding in
+// advances on the item's project.
+func (i *PayrollItem) Recalculate(outstanding decimal.Decimal) error {
+	if i.AdvanceDeduction.IsNegative() || i.AdvanceDeduction.GreaterThan(outstanding) {
+		return ErrInvalidAmount
+	}
+
+	net := i.Earned.Sub(i.AdvanceDeduction).Add(i.Adjustment)
+	if net.IsNegative() {
+		return ErrInvalidAmount
+	}
+
+	i.NetAmount = net
+	return nil
+}
@@ -0,0 +1,69 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPayrollItem_Recalculate(t *testing.T) {
+	outstanding := decimal.NewFromInt(1500)
+	item := &PayrollItem{
+		Earned:           decimal.NewFromInt(3000),
+		AdvanceDeduction: decimal.NewFromInt(1000),
+		Adjustment:       decimal.NewFromInt(-200),
+	}
+
+	require.NoError(t, item.Recalculate(outstanding))
+	assert.Equal(t, "1800", item.NetAmount.String())
+
+	t.Run("rejects a negative deduction", func(t *testing.T) {
+		item := &PayrollItem{Earned: decimal.NewFromInt(100), AdvanceDeduction: decimal.NewFromInt(-1)}
+		assert.ErrorIs(t, item.Recalculate(outstanding), ErrInvalidAmount)
+	})
+
+	t.Run("rejects a negative net amount", func(t *testing.T) {
+		item := &PayrollItem{Earned: decimal.NewFromInt(100), AdvanceDeduction: decimal.NewFromInt(150)}
+		assert.ErrorIs(t, item.Recalculate(outstanding), ErrInvalidAmount)
+	})
+
+	t.Run("rejects a deduction above the outstanding advance", func(t *testing.T) {
+		item := &PayrollItem{Earned: decimal.NewFromInt(3000), AdvanceDeduction: decimal.NewFromInt(1600)}
+		assert.ErrorIs(t, item.Recalculate(outstanding), ErrInvalidAmount)
+
+		item.AdvanceDeduction = decimal.NewFromInt(1500)
+		assert.NoError(t, item.Recalculate(outstanding))
+	})
+
+	t.Run("rejects any deduction without an advance", func(t *testing.T) {
+		item := &PayrollItem{Earned: decimal.NewFromInt(3000), AdvanceDeduction: decimal.NewFromInt(1)}
+		assert.ErrorIs(t, item.Recalculate(decimal.Zero), ErrInvalidAmount)
+
+		item.AdvanceDeduction = decimal.Zero
+		assert.NoError(t, item.Recalculate(decimal.Zero))
+	})
+}
+
+func TestPayrollApprovePermissions(t *testing.T) {
+	canApprove := func(can func(Permission) bool) bool {
+		for _, perm := range PayrollApprovePermissions {
+			if !can(perm) {
+				return false
+			}
+		}
+		return true
+	}
+
+	// Approval closes the period, so a project member with payments:write is
+	// refused even though they may record payments
+	member := &ProjectMember{Capabilities: []Permission{PermPaymentsWrite}}
+	assert.True(t, member.Can(PermPaymentsWrite))
+	assert.False(t, canApprove(member.Can))
+
+	assert.True(t, canApprove(RoleOwner.Can))
+	assert.True(t, canApprove(RoleAccountant.Can))
+	assert.False(t, canApprove(RoleSupervisor.Can))
+	assert.False(t, canApprove(RoleViewer.Can))
+}
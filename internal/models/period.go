@@ -0,0 +1,70 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PeriodAction represents a change to a project's closed-through date
+type PeriodAction string
+
+const (
+	PeriodActionClose  PeriodAction = "close"
+	PeriodActionReopen PeriodAction = "reopen"
+)
+
+// PeriodEvent records a project's period being closed or reopened
+type PeriodEvent struct {
+	ID                    uuid.UUID    `json:"id" db:"id"`
+	ProjectID             uuid.UUID    `json:"project_id" db:"project_id"`
+	Action                PeriodAction `json:"action" db:"action"`
+	PreviousClosedThrough *time.Time   `json:"previous_closed_through,omitempty" db:"previous_closed_through"`
+	ClosedThrough         *time.Time   `json:"closed_through,omitempty" db:"closed_through"` // nil = fully reopened
+	Reason                string       `json:"reason,omitempty" db:"reason"`
+	UserID                *uuid.UUID   `json:"user_id,omitempty" db:"user_id"`
+	CreatedAt             time.Time    `json:"created_at" db:"created_at"`
+}
+
+// ClosePeriodRequest represents the request to close a project's period
+type ClosePeriodRequest struct {
+	ClosedThrough string `json:"closed_through" binding:"required"` // Format: YYYY-MM-DD
+}
+
+// ReopenPeriodRequest represents the request to reopen a closed period
+type ReopenPeriodRequest struct {
+	ClosedThrough string `json:"closed_through"` // Format: YYYY-MM-DD, empty = reopen everything
+	Reason        string `json:"reason" binding:"required,max=500"`
+}
+
+// Validate checks that the event moves the closed-through date in the
+// direction of its action. PreviousClosedThrough must be the current date.
+func (e *PeriodEvent) Validate() error {
+	switch e.Action {
+	case PeriodActionClose:
+		if e.ClosedThrough == nil {
+			return ErrInvalidDate
+		}
+		if e.PreviousClosedThrough != nil && !e.ClosedThrough.After(*e.PreviousClosedThrough) {
+			return ErrInvalidDate
+		}
+	case PeriodActionReopen:
+		if e.PreviousClosedThrough == nil {
+			return ErrInvalidDate
+		}
+		if e.ClosedThrough != nil && !e.ClosedThrough.Before(*e.PreviousClosedThrough) {
+			return ErrInvalidDate
+		}
+		if e.Reason == "" {
+			return ErrInvalidReason
+		}
+	default:
+		return ErrInvalidDate
+	}
+	return nil
+}
+
+// IsClosed reports whether changes dated on the given day are locked
+func (p *Project) IsClosed(date time.Time) bool {
+	return p.ClosedThrough != nil && !date.After(*p.ClosedThrough)
+}
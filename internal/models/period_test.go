@@ -0,0 +1,41 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPeriodEvent_Validate(t *testing.T) {
+	day := func(d int) *time.Time {
+		date := time.Date(2024, time.March, d, 0, 0, 0, 0, time.UTC)
+		return &date
+	}
+
+	t.Run("closing moves the date forward", func(t *testing.T) {
+		assert.NoError(t, (&PeriodEvent{Action: PeriodActionClose, ClosedThrough: day(9)}).Validate())
+		assert.NoError(t, (&PeriodEvent{Action: PeriodActionClose, PreviousClosedThrough: day(2), ClosedThrough: day(9)}).Validate())
+		assert.ErrorIs(t, (&PeriodEvent{Action: PeriodActionClose, PreviousClosedThrough: day(9), ClosedThrough: day(9)}).Validate(), ErrInvalidDate)
+		assert.ErrorIs(t, (&PeriodEvent{Action: PeriodActionClose}).Validate(), ErrInvalidDate)
+	})
+
+	t.Run("reopening moves the date back and needs a reason", func(t *testing.T) {
+		assert.NoError(t, (&PeriodEvent{Action: PeriodActionReopen, PreviousClosedThrough: day(9), ClosedThrough: day(2), Reason: "wrong attendance"}).Validate())
+		assert.NoError(t, (&PeriodEvent{Action: PeriodActionReopen, PreviousClosedThrough: day(9), Reason: "wrong attendance"}).Validate())
+		assert.ErrorIs(t, (&PeriodEvent{Action: PeriodActionReopen, PreviousClosedThrough: day(9), ClosedThrough: day(9), Reason: "x"}).Validate(), ErrInvalidDate)
+		assert.ErrorIs(t, (&PeriodEvent{Action: PeriodActionReopen, ClosedThrough: day(2), Reason: "x"}).Validate(), ErrInvalidDate)
+		assert.ErrorIs(t, (&PeriodEvent{Action: PeriodActionReopen, PreviousClosedThrough: day(9)}).Validate(), ErrInvalidReason)
+	})
+}
+
+func TestProject_IsClosed(t *testing.T) {
+	closed := time.Date(2024, time.March, 9, 0, 0, 0, 0, time.UTC)
+	p := &Project{}
+	assert.False(t, p.IsClosed(closed))
+
+	p.ClosedThrough = &closed
+	assert.True(t, p.IsClosed(closed))
+	assert.True(t, p.IsClosed(closed.AddDate(0, 0, -1)))
+	assert.False(t, p.IsClosed(closed.AddDate(0, 0, 1)))
+}
@@ -1,37 +1,195 @@
 package models
 
 import (
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
 )
 
+// ProjectStatus represents where a project is in its lifecycle
+type ProjectStatus string
+
+const (
+	ProjectStatusPlanned   ProjectStatus = "planned"
+	ProjectStatusActive    ProjectStatus = "active"
+	ProjectStatusOnHold    ProjectStatus = "on_hold"
+	ProjectStatusCompleted ProjectStatus = "completed"
+	ProjectStatusArchived  ProjectStatus = "archived"
+)
+
+// IsValid checks if the project status is valid
+func (s ProjectStatus) IsValid() bool {
+	switch s {
+	case ProjectStatusPlanned, ProjectStatusActive, ProjectStatusOnHold, ProjectStatusCompleted, ProjectStatusArchived:
+		return true
+	}
+	return false
+}
+
+// IsOpen reports whether attendance and payments may be recorded. Completed
+// and archived projects must be moved back to an open status first.
+func (s ProjectStatus) IsOpen() bool {
+	return s != ProjectStatusCompleted && s != ProjectStatusArchived
+}
+
 // Project represents a project in the system
 type Project struct {
-	ID          uuid.UUID `json:"id" db:"id"`
-	UserID      uuid.UUID `json:"user_id" db:"user_id"`
-	Name        string    `json:"name" db:"name"`
-	Description string    `json:"description,omitempty" db:"description"`
-	CreatedAt   time.Time `json:"created_at" db:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at" db:"updated_at"`
+	ID             uuid.UUID        `json:"id" db:"id"`
+	UserID         uuid.UUID        `json:"user_id" db:"user_id"` // Creator
+	OrganisationID uuid.UUID        `json:"organisation_id" db:"organisation_id"`
+	Name           string           `json:"name" db:"name"`
+	Description    string           `json:"description,omitempty" db:"description"`
+	Status         ProjectStatus    `json:"status" db:"status"`
+	StartDate      *time.Time       `json:"start_date,omitempty" db:"start_date"`
+	EndDate        *time.Time       `json:"end_date,omitempty" db:"end_date"` // Planned end
+	SiteAddress    string           `json:"site_address,omitempty" db:"site_address"`
+	ClientName     string           `json:"client_name,omitempty" db:"client_name"`
+	LabourBudget   *decimal.Decimal `json:"labour_budget,omitempty" db:"labour_budget"`
+	OvertimeRate   *decimal.Decimal `json:"overtime_rate,omitempty" db:"overtime_rate"`   // Hourly, for work days recorded after it is set; nil = daily wage / 8
+	ClosedThrough  *time.Time       `json:"closed_through,omitempty" db:"closed_through"` // Attendance and payments up to this date are locked
+	CreatedAt      time.Time        `json:"created_at" db:"created_at"`
+	UpdatedAt      time.Time        `json:"updated_at" db:"updated_at"`
+	DeletedAt      *time.Time       `json:"deleted_at,omitempty" db:"deleted_at"` // Set while the project is in the trash
+	DeletedBy      *uuid.UUID       `json:"deleted_by,omitempty" db:"deleted_by"`
 }
 
 // ProjectWithLabours represents a project with its assigned labours
 type ProjectWithLabours struct {
 	Project
-	Labours []Labour `json:"labours,omitempty"`
+	Labours []AssignedLabour `json:"labours,omitempty"`
 }
 
 // CreateProjectRequest represents the request to create a project
 type CreateProjectRequest struct {
-	Name        string `json:"name" binding:"required,max=255"`
-	Description string `json:"description" binding:"max=1000"`
+	OrganisationID *uuid.UUID       `json:"organisation_id"` // nil = the caller's own organisation
+	Name           string           `json:"name" binding:"required,max=255"`
+	Description    string           `json:"description" binding:"max=1000"`
+	Status         ProjectStatus    `json:"status"`     // Empty = active
+	StartDate      string           `json:"start_date"` // Format: YYYY-MM-DD
+	EndDate        string           `json:"end_date"`   // Format: YYYY-MM-DD
+	SiteAddress    string           `json:"site_address" binding:"max=1000"`
+	ClientName     string           `json:"client_name" binding:"max=255"`
+	LabourBudget   *decimal.Decimal `json:"labour_budget"`
+	OvertimeRate   *decimal.Decimal `json:"overtime_rate"`
 }
 
-// UpdateProjectRequest represents the request to update a project
+// UpdateProjectRequest represents the request to update a project. Omitted
+// fields are left unchanged; an empty string clears a text field or date,
+// and the clear flags remove the budget or overtime rate. Moving a completed
+// or archived project back to an open status reopens it.
 type UpdateProjectRequest struct {
-	Name        string `json:"name" binding:"required,max=255"`
-	Description string `json:"description" binding:"max=1000"`
+	Name              string           `json:"name" binding:"required,max=255"`
+	Description       *string          `json:"description" binding:"omitempty,max=1000"`
+	Status            ProjectStatus    `json:"status"`     // Empty = unchanged
+	StartDate         *string          `json:"start_date"` // Format: YYYY-MM-DD
+	EndDate           *string          `json:"end_date"`   // Format: YYYY-MM-DD
+	SiteAddress       *string          `json:"site_address" binding:"omitempty,max=1000"`
+	ClientName        *string          `json:"client_name" binding:"omitempty,max=255"`
+	LabourBudget      *decimal.Decimal `json:"labour_budget"`
+	OvertimeRate      *decimal.Decimal `json:"overtime_rate"`
+	ClearLabourBudget bool             `json:"clear_labour_budget"`
+	ClearOvertimeRate bool             `json:"clear_overtime_rate"`
+}
+
+// Apply copies the fields given in the request onto the project
+func (r *UpdateProjectRequest) Apply(p *Project) error {
+	p.Name = r.Name
+	if r.Description != nil {
+		p.Description = *r.Description
+	}
+	if r.Status != "" {
+		p.Status = r.Status
+	}
+	if r.SiteAddress != nil {
+		p.SiteAddress = *r.SiteAddress
+	}
+	if r.ClientName != nil {
+		p.ClientName = *r.ClientName
+	}
+
+	var err error
+	if r.StartDate != nil {
+		if p.StartDate, err = parseProjectDate(*r.StartDate); err != nil {
+			return err
+		}
+	}
+	if r.EndDate != nil {
+		if p.EndDate, err = parseProjectDate(*r.EndDate); err != nil {
+			return err
+		}
+	}
+
+	if r.ClearLabourBudget {
+		p.LabourBudget = nil
+	} else if r.LabourBudget != nil {
+		p.LabourBudget = r.LabourBudget
+	}
+	if r.ClearOvertimeRate {
+		p.OvertimeRate = nil
+	} else if r.OvertimeRate != nil {
+		p.OvertimeRate = r.OvertimeRate
+	}
+
+	return nil
+}
+
+// parseProjectDate parses a YYYY-MM-DD date, an empty string meaning none
+func parseProjectDate(s string) (*time.Time, error) {
+	if s == "" {
+		return nil, nil
+	}
+
+	date, err := time.Parse("2006-01-02", s)
+	if err != nil {
+		return nil, ErrInvalidDate
+	}
+
+	return &date, nil
+}
+
+// ProjectQuery represents the filters for listing projects
+type ProjectQuery struct {
+	Status string `form:"status"` // Comma-separated statuses; empty = all but archived
+}
+
+// Statuses returns the statuses to list
+func (q *ProjectQuery) Statuses() ([]ProjectStatus, error) {
+	if q.Status == "" {
+		return []ProjectStatus{ProjectStatusPlanned, ProjectStatusActive, ProjectStatusOnHold, ProjectStatusCompleted}, nil
+	}
+
+	var statuses []ProjectStatus
+	for _, s := range strings.Split(q.Status, ",") {
+		status := ProjectStatus(strings.TrimSpace(s))
+		if !status.IsValid() {
+			return nil, ErrInvalidStatus
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses, nil
+}
+
+// PurgeRequest represents the request to permanently delete a trashed
+// project or labour. Confirm must repeat its name.
+type PurgeRequest struct {
+	Confirm string `json:"confirm" binding:"required"`
+}
+
+// PurgeBlockers counts the settled records that purging a project would
+// silently remove
+type PurgeBlockers struct {
+	ApprovedPayrollItems int // Items in approved runs, including organisation-wide ones
+	IssuedInvoices       int // Invoices that have been sent to the client
+}
+
+// Check returns ErrProjectSettled if the project has any settled records
+func (b PurgeBlockers) Check() error {
+	if b.ApprovedPayrollItems > 0 || b.IssuedInvoices > 0 {
+		return ErrProjectSettled
+	}
+	return nil
 }
 
 // Validate validates the project data
@@ -42,5 +200,17 @@ func (p *Project) Validate() error {
 	if len(p.Name) > 255 {
 		return ErrInvalidName
 	}
+	if p.OvertimeRate != nil && p.OvertimeRate.IsNegative() {
+		return ErrInvalidAmount
+	}
+	if p.LabourBudget != nil && p.LabourBudget.IsNegative() {
+		return ErrInvalidAmount
+	}
+	if !p.Status.IsValid() {
+		return ErrInvalidStatus
+	}
+	if p.StartDate != nil && p.EndDate != nil && p.EndDate.Before(*p.StartDate) {
+		return ErrInvalidDate
+	}
 	return nil
 }
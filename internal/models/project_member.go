@@ -0,0 +1,73 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// projectCapabilities are the permissions that can be granted on a single project
+var projectCapabilities = map[Permission]bool{
+	PermLaboursRead:     true,
+	PermAttendanceRead:  true,
+	PermAttendanceWrite: true,
+	PermPaymentsRead:    true,
+	PermPaymentsWrite:   true,
+}
+
+// impliedBy lists the capabilities that also grant a permission, so that
+// e.g. a member who marks attendance can see the project's labours
+var impliedBy = map[Permission][]Permission{
+	PermLaboursRead:    {PermAttendanceRead, PermAttendanceWrite, PermPaymentsRead, PermPaymentsWrite},
+	PermAttendanceRead: {PermAttendanceWrite},
+	PermPaymentsRead:   {PermPaymentsWrite},
+}
+
+// ProjectMember represents a user granted capabilities on one project
+type ProjectMember struct {
+	ProjectID    uuid.UUID    `json:"project_id" db:"project_id"`
+	UserID       uuid.UUID    `json:"user_id" db:"user_id"`
+	Phone        string       `json:"phone" db:"phone"`
+	Name         string       `json:"name,omitempty" db:"name"`
+	Capabilities []Permission `json:"capabilities" db:"capabilities"`
+	CreatedAt    time.Time    `json:"created_at" db:"created_at"`
+	UpdatedAt    time.Time    `json:"updated_at" db:"updated_at"`
+}
+
+// AddProjectMemberRequest represents the request to add or update a project member
+type AddProjectMemberRequest struct {
+	Phone        string       `json:"phone" binding:"required,min=10,max=15"`
+	Capabilities []Permission `json:"capabilities" binding:"required,min=1"`
+}
+
+// Validate validates the project member data
+func (m *ProjectMember) Validate() error {
+	if len(m.Capabilities) == 0 {
+		return ErrInvalidCapability
+	}
+	for _, granted := range m.Capabilities {
+		if !projectCapabilities[granted] {
+			return ErrInvalidCapability
+		}
+	}
+	return nil
+}
+
+// Can reports whether the member's capabilities grant the permission.
+// Every member can read the project itself.
+func (m *ProjectMember) Can(perm Permission) bool {
+	if perm == PermProjectsRead {
+		return true
+	}
+	for _, granted := range m.Capabilities {
+		if granted == perm {
+			return true
+		}
+		for _, implied := range impliedBy[perm] {
+			if granted == implied {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,42 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProjectMember(t *testing.T) {
+	t.Run("validates capabilities", func(t *testing.T) {
+		m := &ProjectMember{Capabilities: []Permission{PermAttendanceWrite}}
+		assert.NoError(t, m.Validate())
+
+		m.Capabilities = nil
+		assert.ErrorIs(t, m.Validate(), ErrInvalidCapability)
+
+		m.Capabilities = []Permission{PermMembersManage}
+		assert.ErrorIs(t, m.Validate(), ErrInvalidCapability)
+	})
+
+	t.Run("site supervisor marks attendance but cannot see payments", func(t *testing.T) {
+		m := &ProjectMember{Capabilities: []Permission{PermAttendanceWrite}}
+		assert.True(t, m.Can(PermProjectsRead))
+		assert.True(t, m.Can(PermAttendanceWrite))
+		assert.True(t, m.Can(PermAttendanceRead))
+		assert.True(t, m.Can(PermLaboursRead))
+		assert.False(t, m.Can(PermPaymentsRead))
+		assert.False(t, m.Can(PermLaboursWrite))
+		assert.False(t, m.Can(PermProjectsWrite))
+	})
+	t.Run("payments capability does not grant periods, billing or audit", func(t *testing.T) {
+		m := &ProjectMember{Capabilities: []Permission{PermPaymentsWrite}}
+		assert.True(t, m.Can(PermPaymentsRead))
+		assert.False(t, m.Can(PermPeriodsClose))
+		assert.False(t, m.Can(PermBillingRead))
+		assert.False(t, m.Can(PermBillingWrite))
+		assert.False(t, m.Can(PermAuditRead))
+
+		m.Capabilities = []Permission{PermBillingWrite}
+		assert.ErrorIs(t, m.Validate(), ErrInvalidCapability)
+	})
+}
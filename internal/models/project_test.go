@@ -0,0 +1,118 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProjectStatus_IsOpen(t *testing.T) {
+	assert.True(t, ProjectStatusPlanned.IsOpen())
+	assert.True(t, ProjectStatusActive.IsOpen())
+	assert.True(t, ProjectStatusOnHold.IsOpen())
+	assert.False(t, ProjectStatusCompleted.IsOpen())
+	assert.False(t, ProjectStatusArchived.IsOpen())
+}
+
+func TestProject_Validate(t *testing.T) {
+	start := time.Date(2024, time.March, 1, 0, 0, 0, 0, time.UTC)
+	end := start.AddDate(0, 6, 0)
+
+	valid := func() Project {
+		return Project{Name: "Tower B", Status: ProjectStatusActive, StartDate: &start, EndDate: &end}
+	}
+
+	p := valid()
+	assert.NoError(t, p.Validate())
+
+	p = valid()
+	p.Status = "finished"
+	assert.ErrorIs(t, p.Validate(), ErrInvalidStatus)
+
+	p = valid()
+	p.EndDate = &start
+	p.StartDate = &end
+	assert.ErrorIs(t, p.Validate(), ErrInvalidDate)
+
+	p = valid()
+	budget := decimal.NewFromInt(-1)
+	p.LabourBudget = &budget
+	assert.ErrorIs(t, p.Validate(), ErrInvalidAmount)
+}
+
+func TestUpdateProjectRequest_Apply(t *testing.T) {
+	start := time.Date(2024, time.March, 1, 0, 0, 0, 0, time.UTC)
+	budget := decimal.NewFromInt(500000)
+	rate := decimal.NewFromInt(90)
+
+	existing := func() Project {
+		return Project{
+			Name: "Tower B", Description: "12 floors", Status: ProjectStatusActive, StartDate: &start,
+			SiteAddress: "Plot 7", ClientName: "Acme", LabourBudget: &budget, OvertimeRate: &rate,
+		}
+	}
+
+	t.Run("omitted fields are unchanged", func(t *testing.T) {
+		p := existing()
+		require.NoError(t, (&UpdateProjectRequest{Name: "Tower C"}).Apply(&p))
+
+		want := existing()
+		want.Name = "Tower C"
+		assert.Equal(t, want, p)
+	})
+
+	t.Run("empty strings and clear flags remove values", func(t *testing.T) {
+		empty := ""
+		p := existing()
+		req := &UpdateProjectRequest{
+			Name: "Tower B", StartDate: &empty, ClientName: &empty,
+			ClearLabourBudget: true, ClearOvertimeRate: true,
+		}
+		require.NoError(t, req.Apply(&p))
+		assert.Nil(t, p.StartDate)
+		assert.Empty(t, p.ClientName)
+		assert.Nil(t, p.LabourBudget)
+		assert.Nil(t, p.OvertimeRate)
+		assert.Equal(t, "Plot 7", p.SiteAddress)
+	})
+
+	t.Run("given fields are set", func(t *testing.T) {
+		end := "2024-09-30"
+		newBudget := decimal.NewFromInt(600000)
+		p := existing()
+		req := &UpdateProjectRequest{Name: "Tower B", EndDate: &end, LabourBudget: &newBudget}
+		require.NoError(t, req.Apply(&p))
+		assert.Equal(t, "2024-09-30", p.EndDate.Format("2006-01-02"))
+		assert.True(t, p.LabourBudget.Equal(newBudget))
+		assert.True(t, p.OvertimeRate.Equal(rate))
+	})
+
+	t.Run("invalid date", func(t *testing.T) {
+		bad := "30-09-2024"
+		p := existing()
+		assert.ErrorIs(t, (&UpdateProjectRequest{Name: "Tower B", EndDate: &bad}).Apply(&p), ErrInvalidDate)
+	})
+}
+
+func TestPurgeBlockers_Check(t *testing.T) {
+	assert.NoError(t, PurgeBlockers{}.Check())
+	assert.ErrorIs(t, PurgeBlockers{ApprovedPayrollItems: 3}.Check(), ErrProjectSettled)
+	assert.ErrorIs(t, PurgeBlockers{IssuedInvoices: 1}.Check(), ErrProjectSettled)
+}
+
+func TestProjectQuery_Statuses(t *testing.T) {
+	statuses, err := (&ProjectQuery{}).Statuses()
+	require.NoError(t, err)
+	assert.NotContains(t, statuses, ProjectStatusArchived)
+	assert.Contains(t, statuses, ProjectStatusCompleted)
+
+	statuses, err = (&ProjectQuery{Status: "active, on_hold"}).Statuses()
+	require.NoError(t, err)
+	assert.Equal(t, []ProjectStatus{ProjectStatusActive, ProjectStatusOnHold}, statuses)
+
+	_, err = (&ProjectQuery{Status: "active,done"}).Statuses()
+	assert.ErrorIs(t, err, ErrInvalidStatus)
+}
@@ -0,0 +1,21 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Session represents one login on one device
+type Session struct {
+	ID         uuid.UUID  `json:"id" db:"id"`
+	UserID     uuid.UUID  `json:"user_id" db:"user_id"`
+	DeviceName string     `json:"device_name" db:"device_name"`
+	IP         string     `json:"ip" db:"ip"`
+	UserAgent  string     `json:"user_agent" db:"user_agent"`
+	ExpiresAt  time.Time  `json:"expires_at" db:"expires_at"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty" db:"revoked_at"`
+	LastSeenAt time.Time  `json:"last_seen_at" db:"last_seen_at"`
+	CreatedAt  time.Time  `json:"created_at" db:"created_at"`
+	Current    bool       `json:"current" db:"-"` // Set when listing for the requesting session
+}
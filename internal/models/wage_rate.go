@@ -0,0 +1,39 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+// WageRate represents a labour's daily wage in force from a given date
+type WageRate struct {
+	ID            uuid.UUID       `json:"id" db:"id"`
+	LabourID      uuid.UUID       `json:"labour_id" db:"labour_id"`
+	ProjectID     *uuid.UUID      `json:"project_id,omitempty" db:"project_id"` // nil = applies to all projects
+	DailyWage     decimal.Decimal `json:"daily_wage" db:"daily_wage"`
+	EffectiveFrom time.Time       `json:"effective_from" db:"effective_from"`
+	CreatedAt     time.Time       `json:"created_at" db:"created_at"`
+}
+
+// CreateWageRateRequest represents the request to record a new wage rate
+type CreateWageRateRequest struct {
+	ProjectID     *uuid.UUID      `json:"project_id"`
+	DailyWage     decimal.Decimal `json:"daily_wage" binding:"required"`
+	EffectiveFrom string          `json:"effective_from" binding:"required"` // Format: YYYY-MM-DD
+}
+
+// Validate validates the wage rate data
+func (wr *WageRate) Validate() error {
+	if wr.LabourID == uuid.Nil {
+		return ErrInvalidLabour
+	}
+	if wr.DailyWage.IsNegative() {
+		return ErrInvalidAmount
+	}
+	if wr.EffectiveFrom.IsZero() {
+		return ErrInvalidDate
+	}
+	return nil
+}
@@ -4,8 +4,12 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
 )
 
+// MaxOvertimeHours is the most overtime that can be recorded on a single work day
+const MaxOvertimeHours = 16
+
 // WorkStatus represents the status of a work day
 type WorkStatus string
 
@@ -26,13 +30,16 @@ func (ws WorkStatus) IsValid() bool {
 
 // WorkDay represents a work day record for a labour
 type WorkDay struct {
-	ID        uuid.UUID  `json:"id" db:"id"`
-	ProjectID uuid.UUID  `json:"project_id" db:"project_id"`
-	LabourID  uuid.UUID  `json:"labour_id" db:"labour_id"`
-	WorkDate  time.Time  `json:"work_date" db:"work_date"`
-	Status    WorkStatus `json:"status" db:"status"`
-	Notes     string     `json:"notes,omitempty" db:"notes"`
-	CreatedAt time.Time  `json:"created_at" db:"created_at"`
+	ID            uuid.UUID       `json:"id" db:"id"`
+	ProjectID     uuid.UUID       `json:"project_id" db:"project_id"`
+	LabourID      uuid.UUID       `json:"labour_id" db:"labour_id"`
+	WorkDate      time.Time       `json:"work_date" db:"work_date"`
+	Status        WorkStatus      `json:"status" db:"status"`
+	OvertimeHours decimal.Decimal `json:"overtime_hours" db:"overtime_hours"`
+	CheckIn       *string         `json:"check_in,omitempty" db:"check_in"`   // Format: HH:MM
+	CheckOut      *string         `json:"check_out,omitempty" db:"check_out"` // Format: HH:MM
+	Notes         string          `json:"notes,omitempty" db:"notes"`
+	CreatedAt     time.Time       `json:"created_at" db:"created_at"`
 }
 
 // WorkDayWithLabour represents a work day with labour details
@@ -41,18 +48,72 @@ type WorkDayWithLabour struct {
 	LabourName string `json:"labour_name"`
 }
 
+// LabourWorkDay represents a work day as seen by the labour, with the project
+// name and the amount earned that day
+type LabourWorkDay struct {
+	WorkDay
+	ProjectName string          `json:"project_name"`
+	Amount      decimal.Decimal `json:"amount"`
+}
+
 // CreateWorkDayRequest represents the request to create a work day
 type CreateWorkDayRequest struct {
-	LabourID uuid.UUID  `json:"labour_id" binding:"required"`
-	WorkDate string     `json:"work_date" binding:"required"` // Format: YYYY-MM-DD
-	Status   WorkStatus `json:"status" binding:"required"`
-	Notes    string     `json:"notes" binding:"max=500"`
+	LabourID      uuid.UUID       `json:"labour_id" binding:"required"`
+	WorkDate      string          `json:"work_date" binding:"required"` // Format: YYYY-MM-DD
+	Status        WorkStatus      `json:"status" binding:"required"`
+	OvertimeHours decimal.Decimal `json:"overtime_hours"`
+	CheckIn       *string         `json:"check_in"`  // Format: HH:MM
+	CheckOut      *string         `json:"check_out"` // Format: HH:MM
+	Notes         string          `json:"notes" binding:"max=500"`
 }
 
 // UpdateWorkDayRequest represents the request to update a work day
 type UpdateWorkDayRequest struct {
-	Status WorkStatus `json:"status" binding:"required"`
-	Notes  string     `json:"notes" binding:"max=500"`
+	Status        WorkStatus      `json:"status" binding:"required"`
+	OvertimeHours decimal.Decimal `json:"overtime_hours"`
+	CheckIn       *string         `json:"check_in"`  // Format: HH:MM
+	CheckOut      *string         `json:"check_out"` // Format: HH:MM
+	Notes         string          `json:"notes" binding:"max=500"`
+}
+
+// BulkAttendanceEntry represents one labour's attendance within a bulk request
+type BulkAttendanceEntry struct {
+	LabourID      uuid.UUID       `json:"labour_id" binding:"required"`
+	Status        WorkStatus      `json:"status" binding:"required"`
+	OvertimeHours decimal.Decimal `json:"overtime_hours"`
+	CheckIn       *string         `json:"check_in"`  // Format: HH:MM
+	CheckOut      *string         `json:"check_out"` // Format: HH:MM
+	Notes         string          `json:"notes" binding:"max=500"`
+}
+
+// BulkAttendanceRequest represents the request to mark attendance for many labours on one date
+type BulkAttendanceRequest struct {
+	WorkDate string                `json:"work_date" binding:"required"` // Format: YYYY-MM-DD
+	Entries  []BulkAttendanceEntry `json:"entries" binding:"required,min=1,max=500,dive"`
+}
+
+// BulkAttendanceResult values
+const (
+	BulkResultCreated = "created"
+	BulkResultUpdated = "updated"
+	BulkResultError   = "error"
+)
+
+// BulkAttendanceResult represents the outcome for one entry of a bulk attendance request
+type BulkAttendanceResult struct {
+	LabourID uuid.UUID `json:"labour_id"`
+	Result   string    `json:"result"` // created, updated or error
+	WorkDay  *WorkDay  `json:"work_day,omitempty"`
+	Error    string    `json:"error,omitempty"`
+}
+
+// BulkAttendanceResponse represents the response to a bulk attendance request
+type BulkAttendanceResponse struct {
+	WorkDate time.Time              `json:"work_date"`
+	Created  int                    `json:"created"`
+	Updated  int                    `json:"updated"`
+	Failed   int                    `json:"failed"`
+	Results  []BulkAttendanceResult `json:"results"`
 }
 
 // Validate validates the work day data
@@ -69,5 +130,31 @@ func (wd *WorkDay) Validate() error {
 	if !wd.Status.IsValid() {
 		return ErrInvalidStatus
 	}
+	if wd.OvertimeHours.IsNegative() || wd.OvertimeHours.GreaterThan(decimal.NewFromInt(MaxOvertimeHours)) {
+		return ErrInvalidOvertime
+	}
+	if wd.Status == WorkStatusAbsent && wd.OvertimeHours.IsPositive() {
+		return ErrInvalidOvertime
+	}
+	return validateShiftTimes(wd.CheckIn, wd.CheckOut)
+}
+
+// validateShiftTimes checks that check-in and check-out are HH:MM and in order
+func validateShiftTimes(checkIn, checkOut *string) error {
+	var in, out time.Time
+	var err error
+	if checkIn != nil {
+		if in, err = time.Parse("15:04", *checkIn); err != nil {
+			return ErrInvalidTime
+		}
+	}
+	if checkOut != nil {
+		if out, err = time.Parse("15:04", *checkOut); err != nil {
+			return ErrInvalidTime
+		}
+	}
+	if checkIn != nil && checkOut != nil && !out.After(in) {
+		return ErrInvalidTime
+	}
 	return nil
 }
@@ -0,0 +1,66 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWorkDay_Validate(t *testing.T) {
+	newWorkDay := func() *WorkDay {
+		return &WorkDay{
+			ProjectID: uuid.New(),
+			LabourID:  uuid.New(),
+			WorkDate:  time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC),
+			Status:    WorkStatusFullDay,
+		}
+	}
+	strPtr := func(s string) *string { return &s }
+
+	t.Run("accepts a plain full day", func(t *testing.T) {
+		assert.NoError(t, newWorkDay().Validate())
+	})
+
+	t.Run("accepts overtime with shift times", func(t *testing.T) {
+		wd := newWorkDay()
+		wd.OvertimeHours = decimal.NewFromFloat(2.5)
+		wd.CheckIn = strPtr("08:00")
+		wd.CheckOut = strPtr("19:30")
+		assert.NoError(t, wd.Validate())
+	})
+
+	t.Run("rejects negative overtime", func(t *testing.T) {
+		wd := newWorkDay()
+		wd.OvertimeHours = decimal.NewFromInt(-1)
+		assert.ErrorIs(t, wd.Validate(), ErrInvalidOvertime)
+	})
+
+	t.Run("rejects overtime above the daily maximum", func(t *testing.T) {
+		wd := newWorkDay()
+		wd.OvertimeHours = decimal.NewFromInt(MaxOvertimeHours + 1)
+		assert.ErrorIs(t, wd.Validate(), ErrInvalidOvertime)
+	})
+
+	t.Run("rejects overtime on an absent day", func(t *testing.T) {
+		wd := newWorkDay()
+		wd.Status = WorkStatusAbsent
+		wd.OvertimeHours = decimal.NewFromInt(2)
+		assert.ErrorIs(t, wd.Validate(), ErrInvalidOvertime)
+	})
+
+	t.Run("rejects malformed check-in", func(t *testing.T) {
+		wd := newWorkDay()
+		wd.CheckIn = strPtr("8am")
+		assert.ErrorIs(t, wd.Validate(), ErrInvalidTime)
+	})
+
+	t.Run("rejects check-out before check-in", func(t *testing.T) {
+		wd := newWorkDay()
+		wd.CheckIn = strPtr("18:00")
+		wd.CheckOut = strPtr("09:00")
+		assert.ErrorIs(t, wd.Validate(), ErrInvalidTime)
+	})
+}
@@ -0,0 +1,87 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/vivekanand/labour-thekedar-backend/internal/models"
+)
+
+// AuditRepository handles audit log database operations. Entries can only
+// be added, never changed.
+type AuditRepository struct {
+	db *pgxpool.Pool
+}
+
+// NewAuditRepository creates a new AuditRepository
+func NewAuditRepository(db *pgxpool.Pool) *AuditRepository {
+	return &AuditRepository{db: db}
+}
+
+// Create appends an audit entry
+func (r *AuditRepository) Create(ctx context.Context, entry *models.AuditLog) error {
+	query := `
+		INSERT INTO audit_logs (actor_id, entity_type, entity_id, project_id, labour_id, action, before, after)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING id, created_at
+	`
+
+	return conn(ctx, r.db).QueryRow(ctx, query, entry.ActorID, entry.EntityType, entry.EntityID, entry.ProjectID,
+		entry.LabourID, entry.Action, entry.Before, entry.After).
+		Scan(&entry.ID, &entry.CreatedAt)
+}
+
+// GetByProjectID retrieves a project's audit entries, newest first
+func (r *AuditRepository) GetByProjectID(ctx context.Context, projectID uuid.UUID, filter *models.AuditFilter) ([]models.AuditLog, error) {
+	return r.query(ctx, `a.project_id = $1`, filter, projectID)
+}
+
+// GetByLabourID retrieves a labour's audit entries that are either not tied
+// to a project or tied to one of the given projects, newest first
+func (r *AuditRepository) GetByLabourID(ctx context.Context, labourID uuid.UUID, projectIDs []uuid.UUID, filter *models.AuditFilter) ([]models.AuditLog, error) {
+	return r.query(ctx, `a.labour_id = $1 AND (a.project_id IS NULL OR a.project_id = ANY($9))`, filter, labourID, projectIDs)
+}
+
+// query lists audit entries matching scope, which may use $1 and $9 onwards,
+// and the filter
+func (r *AuditRepository) query(ctx context.Context, scope string, filter *models.AuditFilter, scopeArgs ...any) ([]models.AuditLog, error) {
+	query := `
+		SELECT a.id, a.actor_id, COALESCE(u.name, ''), a.entity_type, a.entity_id, a.project_id, a.labour_id,
+			a.action, a.before, a.after, a.created_at
+		FROM audit_logs a
+		LEFT JOIN users u ON u.id = a.actor_id
+		WHERE ` + scope + `
+			AND ($2::text IS NULL OR a.entity_type = $2)
+			AND ($3::uuid IS NULL OR a.entity_id = $3)
+			AND ($4::text IS NULL OR a.action = $4)
+			AND ($5::uuid IS NULL OR a.actor_id = $5)
+			AND ($6::timestamptz IS NULL OR a.created_at >= $6)
+			AND ($7::timestamptz IS NULL OR a.created_at < $7)
+		ORDER BY a.created_at DESC
+		LIMIT $8
+	`
+
+	args := []any{scopeArgs[0], filter.EntityType, filter.EntityID, filter.Action, filter.ActorID,
+		filter.From, filter.Before, filter.Limit}
+	args = append(args, scopeArgs[1:]...)
+
+	rows, err := conn(ctx, r.db).Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var entries []models.AuditLog
+	for rows.Next() {
+		var e models.AuditLog
+		err := rows.Scan(&e.ID, &e.ActorID, &e.ActorName, &e.EntityType, &e.EntityID, &e.ProjectID,
+			&e.LabourID, &e.Action, &e.Before, &e.After, &e.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, e)
+	}
+
+	return entries, rows.Err()
+}
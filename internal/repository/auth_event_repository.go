@@ -0,0 +1,103 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Auth event actions
+const (
+	AuthEventOTPSend   = "otp_send"
+	AuthEventOTPVerify = "otp_verify"
+)
+
+// AuthEventRepository records authentication events for rate limiting
+type AuthEventRepository struct {
+	db *pgxpool.Pool
+}
+
+// NewAuthEventRepository creates a new AuthEventRepository
+func NewAuthEventRepository(db *pgxpool.Pool) *AuthEventRepository {
+	return &AuthEventRepository{db: db}
+}
+
+// RecordWithinLimits stores an event for a phone and client IP unless either
+// already has its limit of events since the given time, and reports whether
+// it was stored. The counts and the insert run under locks on the phone and
+// the IP, so concurrent requests can't all pass the check.
+func (r *AuthEventRepository) RecordWithinLimits(ctx context.Context, action, phone, ip string, since time.Time, perPhone, perIP int) (bool, error) {
+	tx, err := conn(ctx, r.db).Begin(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback(ctx)
+
+	// Always lock the phone before the IP so two requests can't deadlock
+	lockQuery := `SELECT pg_advisory_xact_lock(hashtext($1 || ':' || $2 || ':' || $3))`
+	if _, err := tx.Exec(ctx, lockQuery, action, "phone", phone); err != nil {
+		return false, err
+	}
+	if _, err := tx.Exec(ctx, lockQuery, action, "ip", ip); err != nil {
+		return false, err
+	}
+
+	countQuery := `
+		SELECT
+			COUNT(*) FILTER (WHERE phone = $2),
+			COUNT(*) FILTER (WHERE ip = $3)
+		FROM auth_events
+		WHERE action = $1 AND (phone = $2 OR ip = $3) AND created_at >= $4
+	`
+	var phoneCount, ipCount int
+	if err := tx.QueryRow(ctx, countQuery, action, phone, ip, since).Scan(&phoneCount, &ipCount); err != nil {
+		return false, err
+	}
+	if phoneCount >= perPhone || ipCount >= perIP {
+		return false, nil
+	}
+
+	query := `INSERT INTO auth_events (action, phone, ip) VALUES ($1, $2, $3)`
+	if _, err := tx.Exec(ctx, query, action, phone, ip); err != nil {
+		return false, err
+	}
+
+	return true, tx.Commit(ctx)
+}
+
+// LastByPhone returns the time of a phone's most recent event, or the zero time if none
+func (r *AuthEventRepository) LastByPhone(ctx context.Context, action, phone string) (time.Time, error) {
+	query := `
+		SELECT created_at
+		FROM auth_events
+		WHERE action = $1 AND phone = $2
+		ORDER BY created_at DESC
+		LIMIT 1
+	`
+
+	var last time.Time
+	err := conn(ctx, r.db).QueryRow(ctx, query, action, phone).Scan(&last)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return time.Time{}, nil
+		}
+		return time.Time{}, err
+	}
+
+	return last, nil
+}
+
+// DeleteBefore removes events older than the cutoff
+func (r *AuthEventRepository) DeleteBefore(ctx context.Context, cutoff time.Time) (int64, error) {
+	query := `DELETE FROM auth_events WHERE created_at < $1`
+
+	result, err := conn(ctx, r.db).Exec(ctx, query, cutoff)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected(), nil
+}
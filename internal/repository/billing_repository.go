@@ -0,0 +1,398 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/vivekanand/labour-thekedar-backend/internal/models"
+)
+
+// billingRateColumns selects every column of a billing rate, in the order of
+// billingRateFields
+const billingRateColumns = `id, project_id, labour_id, role, day_rate, markup_percent, created_at, updated_at`
+
+// billingRateFields returns the scan destinations for billingRateColumns
+func billingRateFields(r *models.BillingRate) []any {
+	return []any{&r.ID, &r.ProjectID, &r.LabourID, &r.Role, &r.DayRate, &r.MarkupPercent, &r.CreatedAt, &r.UpdatedAt}
+}
+
+// invoiceColumns selects every column of invoice i and what has been
+// received against it, in the order of invoiceFields
+const invoiceColumns = `
+	i.id, i.organisation_id, i.project_id, i.number, i.period_start, i.period_end, i.status, i.client_name,
+	i.total, COALESCE((SELECT SUM(r.amount) FROM invoice_receipts r WHERE r.invoice_id = i.id), 0),
+	i.notes, i.created_by, i.sent_at, i.paid_at, i.created_at, i.updated_at
+`
+
+// invoiceFields returns the scan destinations for invoiceColumns
+func invoiceFields(i *models.Invoice) []any {
+	return []any{&i.ID, &i.OrganisationID, &i.ProjectID, &i.Number, &i.PeriodStart, &i.PeriodEnd, &i.Status, &i.ClientName,
+		&i.Total, &i.Received,
+		&i.Notes, &i.CreatedBy, &i.SentAt, &i.PaidAt, &i.CreatedAt, &i.UpdatedAt}
+}
+
+// BillingRepository handles billing rate and invoice database operations
+type BillingRepository struct {
+	db *pgxpool.Pool
+}
+
+// NewBillingRepository creates a new BillingRepository
+func NewBillingRepository(db *pgxpool.Pool) *BillingRepository {
+	return &BillingRepository{db: db}
+}
+
+// GetRates retrieves a project's billing rates
+func (r *BillingRepository) GetRates(ctx context.Context, projectID uuid.UUID) ([]models.BillingRate, error) {
+	query := `
+		SELECT ` + billingRateColumns + `
+		FROM billing_rates
+		WHERE project_id = $1
+		ORDER BY labour_id NULLS LAST, role DESC, created_at ASC
+	`
+
+	rows, err := conn(ctx, r.db).Query(ctx, query, projectID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var rates []models.BillingRate
+	for rows.Next() {
+		var rate models.BillingRate
+		if err := rows.Scan(billingRateFields(&rate)...); err != nil {
+			return nil, err
+		}
+		rates = append(rates, rate)
+	}
+
+	return rates, rows.Err()
+}
+
+// UpsertRate sets a billing rate, replacing the project's rate with the same
+// labour and role. It reports whether the rate is new.
+func (r *BillingRepository) UpsertRate(ctx context.Context, rate *models.BillingRate) (bool, error) {
+	query := `
+		INSERT INTO billing_rates (project_id, labour_id, role, day_rate, markup_percent)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT ON CONSTRAINT billing_rates_scope_key DO UPDATE
+		SET day_rate = EXCLUDED.day_rate, markup_percent = EXCLUDED.markup_percent
+		RETURNING id, created_at, updated_at, (xmax = 0) AS inserted
+	`
+
+	var inserted bool
+	err := conn(ctx, r.db).QueryRow(ctx, query, rate.ProjectID, rate.LabourID, rate.Role, rate.DayRate, rate.MarkupPercent).
+		Scan(&rate.ID, &rate.CreatedAt, &rate.UpdatedAt, &inserted)
+	if err != nil {
+		return false, err
+	}
+
+	return inserted, nil
+}
+
+// DeleteRate deletes a project's billing rate and returns it
+func (r *BillingRepository) DeleteRate(ctx context.Context, projectID, rateID uuid.UUID) (*models.BillingRate, error) {
+	query := `
+		DELETE FROM billing_rates
+		WHERE id = $1 AND project_id = $2
+		RETURNING ` + billingRateColumns
+
+	rate := &models.BillingRate{}
+	err := conn(ctx, r.db).QueryRow(ctx, query, rateID, projectID).Scan(billingRateFields(rate)...)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, models.ErrNotFound
+		}
+		return nil, err
+	}
+
+	return rate, nil
+}
+
+// GetBillable sums each labour's attendance on a project between two dates,
+// inclusive, with their role on the project
+func (r *BillingRepository) GetBillable(ctx context.Context, projectID uuid.UUID, start, end time.Time) ([]models.BillableLabour, error) {
+	query := `
+		SELECT e.labour_id, l.name, COALESCE(pl.role, ''),
+			SUM(e.day_fraction), SUM(e.day_fraction * e.daily_wage),
+			SUM(e.overtime_hours), SUM(e.overtime_amount)
+		FROM work_day_earnings e
+		INNER JOIN labours l ON l.id = e.labour_id
+		LEFT JOIN project_labours pl ON pl.project_id = e.project_id AND pl.labour_id = e.labour_id
+		WHERE e.project_id = $1 AND e.work_date BETWEEN $2 AND $3
+		GROUP BY e.labour_id, l.name, pl.role
+		HAVING SUM(e.amount) > 0
+		ORDER BY l.name ASC
+	`
+
+	rows, err := conn(ctx, r.db).Query(ctx, query, projectID, start, end)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var billable []models.BillableLabour
+	for rows.Next() {
+		var b models.BillableLabour
+		err := rows.Scan(&b.LabourID, &b.LabourName, &b.Role, &b.Days, &b.Wages, &b.OvertimeHours, &b.OvertimeAmount)
+		if err != nil {
+			return nil, err
+		}
+		billable = append(billable, b)
+	}
+
+	return billable, rows.Err()
+}
+
+// CreateInvoice saves a draft invoice with its items. Invoices of a project
+// may not overlap, which is checked while the project row is locked.
+func (r *BillingRepository) CreateInvoice(ctx context.Context, invoice *models.Invoice, items []models.InvoiceItem) error {
+	tx, err := conn(ctx, r.db).Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, `SELECT 1 FROM projects WHERE id = $1 FOR UPDATE`, invoice.ProjectID); err != nil {
+		return err
+	}
+
+	overlapQuery := `
+		SELECT EXISTS(
+			SELECT 1 FROM invoices
+			WHERE project_id = $1 AND period_start <= $3 AND period_end >= $2
+		)
+	`
+	var overlaps bool
+	err = tx.QueryRow(ctx, overlapQuery, invoice.ProjectID, invoice.PeriodStart, invoice.PeriodEnd).Scan(&overlaps)
+	if err != nil {
+		return err
+	}
+	if overlaps {
+		return models.ErrInvoiceOverlap
+	}
+
+	query := `
+		INSERT INTO invoices (organisation_id, project_id, period_start, period_end, client_name, total, notes, created_by)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING id, status, created_at, updated_at
+	`
+	err = tx.QueryRow(ctx, query, invoice.OrganisationID, invoice.ProjectID, invoice.PeriodStart, invoice.PeriodEnd,
+		invoice.ClientName, invoice.Total, invoice.Notes, invoice.CreatedBy).
+		Scan(&invoice.ID, &invoice.Status, &invoice.CreatedAt, &invoice.UpdatedAt)
+	if err != nil {
+		return err
+	}
+
+	itemQuery := `
+		INSERT INTO invoice_items (invoice_id, labour_id, description, unit, quantity, unit_rate, amount)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id
+	`
+	for i := range items {
+		item := &items[i]
+		item.InvoiceID = invoice.ID
+		err := tx.QueryRow(ctx, itemQuery, item.InvoiceID, item.LabourID, item.Description, item.Unit,
+			item.Quantity, item.UnitRate, item.Amount).
+			Scan(&item.ID)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit(ctx)
+}
+
+// GetInvoice retrieves an invoice by ID
+func (r *BillingRepository) GetInvoice(ctx context.Context, id uuid.UUID) (*models.Invoice, error) {
+	query := `SELECT ` + invoiceColumns + ` FROM invoices i WHERE i.id = $1`
+
+	invoice := &models.Invoice{}
+	err := conn(ctx, r.db).QueryRow(ctx, query, id).Scan(invoiceFields(invoice)...)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, models.ErrNotFound
+		}
+		return nil, err
+	}
+
+	return invoice, nil
+}
+
+// GetInvoicesByProjectID retrieves a project's invoices, latest period first
+func (r *BillingRepository) GetInvoicesByProjectID(ctx context.Context, projectID uuid.UUID) ([]models.Invoice, error) {
+	query := `SELECT ` + invoiceColumns + ` FROM invoices i WHERE i.project_id = $1 ORDER BY i.period_start DESC`
+
+	rows, err := conn(ctx, r.db).Query(ctx, query, projectID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var invoices []models.Invoice
+	for rows.Next() {
+		var invoice models.Invoice
+		if err := rows.Scan(invoiceFields(&invoice)...); err != nil {
+			return nil, err
+		}
+		invoices = append(invoices, invoice)
+	}
+
+	return invoices, rows.Err()
+}
+
+// GetItems retrieves an invoice's items
+func (r *BillingRepository) GetItems(ctx context.Context, invoiceID uuid.UUID) ([]models.InvoiceItem, error) {
+	query := `
+		SELECT id, invoice_id, labour_id, description, unit, quantity, unit_rate, amount
+		FROM invoice_items
+		WHERE invoice_id = $1
+		ORDER BY description ASC
+	`
+
+	rows, err := conn(ctx, r.db).Query(ctx, query, invoiceID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items []models.InvoiceItem
+	for rows.Next() {
+		var i models.InvoiceItem
+		err := rows.Scan(&i.ID, &i.InvoiceID, &i.LabourID, &i.Description, &i.Unit, &i.Quantity, &i.UnitRate, &i.Amount)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+
+	return items, rows.Err()
+}
+
+// GetReceipts retrieves the receipts recorded against an invoice
+func (r *BillingRepository) GetReceipts(ctx context.Context, invoiceID uuid.UUID) ([]models.InvoiceReceipt, error) {
+	query := `
+		SELECT id, invoice_id, amount, received_on, reference, created_by, created_at
+		FROM invoice_receipts
+		WHERE invoice_id = $1
+		ORDER BY received_on ASC, created_at ASC
+	`
+
+	rows, err := conn(ctx, r.db).Query(ctx, query, invoiceID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var receipts []models.InvoiceReceipt
+	for rows.Next() {
+		var rc models.InvoiceReceipt
+		err := rows.Scan(&rc.ID, &rc.InvoiceID, &rc.Amount, &rc.ReceivedOn, &rc.Reference, &rc.CreatedBy, &rc.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		receipts = append(receipts, rc)
+	}
+
+	return receipts, rows.Err()
+}
+
+// SendInvoice marks a draft invoice as sent and gives it the next number
+// from its organisation's counter, taken while the organisation row is locked
+func (r *BillingRepository) SendInvoice(ctx context.Context, invoice *models.Invoice) error {
+	tx, err := conn(ctx, r.db).Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	var number int
+	err = tx.QueryRow(ctx, `SELECT next_invoice_number FROM organisations WHERE id = $1 FOR UPDATE`, invoice.OrganisationID).
+		Scan(&number)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		UPDATE invoices
+		SET status = 'sent', sent_at = NOW(), number = $2
+		WHERE id = $1 AND status = 'draft'
+		RETURNING number, status, sent_at, updated_at
+	`
+	err = tx.QueryRow(ctx, query, invoice.ID, number).
+		Scan(&invoice.Number, &invoice.Status, &invoice.SentAt, &invoice.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return models.ErrInvoiceNotDraft
+		}
+		return err
+	}
+
+	_, err = tx.Exec(ctx, `UPDATE organisations SET next_invoice_number = next_invoice_number + 1 WHERE id = $1`, invoice.OrganisationID)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// DeleteInvoice deletes a draft invoice
+func (r *BillingRepository) DeleteInvoice(ctx context.Context, id uuid.UUID) error {
+	result, err := conn(ctx, r.db).Exec(ctx, `DELETE FROM invoices WHERE id = $1 AND status = 'draft'`, id)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return models.ErrInvoiceNotDraft
+	}
+
+	return nil
+}
+
+// CreateReceipt records a receipt against an invoice, marking the invoice
+// paid once its total has been received. The receipt is checked against the
+// invoice while its row is locked.
+func (r *BillingRepository) CreateReceipt(ctx context.Context, receipt *models.InvoiceReceipt) error {
+	tx, err := conn(ctx, r.db).Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	lockQuery := `SELECT ` + invoiceColumns + ` FROM invoices i WHERE i.id = $1 FOR UPDATE`
+	invoice := &models.Invoice{}
+	if err := tx.QueryRow(ctx, lockQuery, receipt.InvoiceID).Scan(invoiceFields(invoice)...); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return models.ErrNotFound
+		}
+		return err
+	}
+	if err := invoice.AcceptReceipt(receipt.Amount); err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO invoice_receipts (invoice_id, amount, received_on, reference, created_by)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, created_at
+	`
+	err = tx.QueryRow(ctx, query, receipt.InvoiceID, receipt.Amount, receipt.ReceivedOn, receipt.Reference,
+		receipt.CreatedBy).
+		Scan(&receipt.ID, &receipt.CreatedAt)
+	if err != nil {
+		return err
+	}
+
+	if receipt.Amount.Equal(invoice.Outstanding()) {
+		_, err := tx.Exec(ctx, `UPDATE invoices SET status = 'paid', paid_at = NOW() WHERE id = $1`, receipt.InvoiceID)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit(ctx)
+}
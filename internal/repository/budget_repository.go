@@ -0,0 +1,91 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/shopspring/decimal"
+	"github.com/vivekanand/labour-thekedar-backend/internal/models"
+)
+
+// BudgetRepository handles project budget database operations
+type BudgetRepository struct {
+	db *pgxpool.Pool
+}
+
+// NewBudgetRepository creates a new BudgetRepository
+func NewBudgetRepository(db *pgxpool.Pool) *BudgetRepository {
+	return &BudgetRepository{db: db}
+}
+
+// GetCommitted returns a project's labour cost to date, each work day at the
+// wage in force on its date including overtime, and its first work day
+func (r *BudgetRepository) GetCommitted(ctx context.Context, projectID uuid.UUID) (decimal.Decimal, *time.Time, error) {
+	query := `
+		SELECT COALESCE(SUM(amount), 0), MIN(work_date)
+		FROM work_day_earnings
+		WHERE project_id = $1
+	`
+
+	var committed decimal.Decimal
+	var firstWorkDate *time.Time
+	if err := conn(ctx, r.db).QueryRow(ctx, query, projectID).Scan(&committed, &firstWorkDate); err != nil {
+		return decimal.Zero, nil, err
+	}
+
+	return committed, firstWorkDate, nil
+}
+
+// CreateAlert records a budget alert. It returns false without error when the
+// threshold has already been raised for the same budget.
+func (r *BudgetRepository) CreateAlert(ctx context.Context, alert *models.BudgetAlert) (bool, error) {
+	query := `
+		INSERT INTO budget_alerts (project_id, threshold, budget, committed)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (project_id, threshold, budget) DO NOTHING
+		RETURNING id, created_at
+	`
+
+	err := conn(ctx, r.db).QueryRow(ctx, query, alert.ProjectID, alert.Threshold, alert.Budget, alert.Committed).
+		Scan(&alert.ID, &alert.CreatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	return true, nil
+}
+
+// GetAlerts retrieves a project's budget alerts, newest first
+func (r *BudgetRepository) GetAlerts(ctx context.Context, projectID uuid.UUID) ([]models.BudgetAlert, error) {
+	query := `
+		SELECT id, project_id, threshold, budget, committed, created_at
+		FROM budget_alerts
+		WHERE project_id = $1
+		ORDER BY created_at DESC, threshold DESC
+	`
+
+	rows, err := conn(ctx, r.db).Query(ctx, query, projectID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var alerts []models.BudgetAlert
+	for rows.Next() {
+		var a models.BudgetAlert
+		err := rows.Scan(&a.ID, &a.ProjectID, &a.Threshold, &a.Budget, &a.Committed, &a.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		alerts = append(alerts, a)
+	}
+
+	return alerts, rows.Err()
+}
@@ -20,15 +20,22 @@ func NewLabourRepository(db *pgxpool.Pool) *LabourRepository {
 	return &LabourRepository{db: db}
 }
 
-// Create creates a new labour
+// Create creates a new labour and seeds its wage history with the initial wage
 func (r *LabourRepository) Create(ctx context.Context, labour *models.Labour) error {
 	query := `
-		INSERT INTO labours (name, phone, daily_wage)
-		VALUES ($1, $2, $3)
-		RETURNING id, created_at, updated_at
+		WITH new_labour AS (
+			INSERT INTO labours (user_id, organisation_id, name, phone, daily_wage, overtime_rate)
+			VALUES ($1, $2, $3, $4, $5, $6)
+			RETURNING id, daily_wage, created_at, updated_at
+		), initial_rate AS (
+			INSERT INTO labour_wage_rates (labour_id, daily_wage, effective_from)
+			SELECT id, daily_wage, created_at::date FROM new_labour
+		)
+		SELECT id, created_at, updated_at FROM new_labour
 	`
 
-	err := r.db.QueryRow(ctx, query, labour.Name, labour.Phone, labour.DailyWage).
+	err := conn(ctx, r.db).QueryRow(ctx, query, labour.UserID, labour.OrganisationID, labour.Name, labour.Phone, labour.DailyWage,
+		labour.OvertimeRate).
 		Scan(&labour.ID, &labour.CreatedAt, &labour.UpdatedAt)
 	if err != nil {
 		return err
@@ -40,15 +47,15 @@ func (r *LabourRepository) Create(ctx context.Context, labour *models.Labour) er
 // GetByID retrieves a labour by ID
 func (r *LabourRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Labour, error) {
 	query := `
-		SELECT id, name, phone, daily_wage, created_at, updated_at
+		SELECT id, user_id, organisation_id, name, phone, daily_wage, overtime_rate, created_at, updated_at
 		FROM labours
-		WHERE id = $1
+		WHERE id = $1 AND deleted_at IS NULL
 	`
 
 	labour := &models.Labour{}
-	err := r.db.QueryRow(ctx, query, id).
-		Scan(&labour.ID, &labour.Name, &labour.Phone, &labour.DailyWage,
-			&labour.CreatedAt, &labour.UpdatedAt)
+	err := conn(ctx, r.db).QueryRow(ctx, query, id).
+		Scan(&labour.ID, &labour.UserID, &labour.OrganisationID, &labour.Name, &labour.Phone, &labour.DailyWage,
+			&labour.OvertimeRate, &labour.CreatedAt, &labour.UpdatedAt)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, models.ErrNotFound
@@ -59,15 +66,17 @@ func (r *LabourRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.L
 	return labour, nil
 }
 
-// GetAll retrieves all labours
-func (r *LabourRepository) GetAll(ctx context.Context) ([]models.Labour, error) {
+// GetByUserID retrieves all labours in the organisations a user belongs to
+func (r *LabourRepository) GetByUserID(ctx context.Context, userID uuid.UUID) ([]models.Labour, error) {
 	query := `
-		SELECT id, name, phone, daily_wage, created_at, updated_at
+		SELECT id, user_id, organisation_id, name, phone, daily_wage, overtime_rate, created_at, updated_at
 		FROM labours
+		WHERE deleted_at IS NULL
+			AND organisation_id IN (SELECT organisation_id FROM organisation_members WHERE user_id = $1)
 		ORDER BY name ASC
 	`
 
-	rows, err := r.db.Query(ctx, query)
+	rows, err := conn(ctx, r.db).Query(ctx, query, userID)
 	if err != nil {
 		return nil, err
 	}
@@ -76,8 +85,8 @@ func (r *LabourRepository) GetAll(ctx context.Context) ([]models.Labour, error)
 	var labours []models.Labour
 	for rows.Next() {
 		var l models.Labour
-		err := rows.Scan(&l.ID, &l.Name, &l.Phone, &l.DailyWage,
-			&l.CreatedAt, &l.UpdatedAt)
+		err := rows.Scan(&l.ID, &l.UserID, &l.OrganisationID, &l.Name, &l.Phone, &l.DailyWage,
+			&l.OvertimeRate, &l.CreatedAt, &l.UpdatedAt)
 		if err != nil {
 			return nil, err
 		}
@@ -87,27 +96,28 @@ func (r *LabourRepository) GetAll(ctx context.Context) ([]models.Labour, error)
 	return labours, rows.Err()
 }
 
-// GetByProjectID retrieves all labours assigned to a project
-func (r *LabourRepository) GetByProjectID(ctx context.Context, projectID uuid.UUID) ([]models.Labour, error) {
+// GetByProjectID retrieves all labours assigned to a project along with their project terms
+func (r *LabourRepository) GetByProjectID(ctx context.Context, projectID uuid.UUID) ([]models.AssignedLabour, error) {
 	query := `
-		SELECT l.id, l.name, l.phone, l.daily_wage, l.created_at, l.updated_at
+		SELECT l.id, l.user_id, l.organisation_id, l.name, l.phone, l.daily_wage, l.overtime_rate, l.created_at, l.updated_at,
+			pl.daily_wage, pl.role, pl.assigned_at
 		FROM labours l
 		INNER JOIN project_labours pl ON l.id = pl.labour_id
-		WHERE pl.project_id = $1
+		WHERE pl.project_id = $1 AND l.deleted_at IS NULL
 		ORDER BY l.name ASC
 	`
 
-	rows, err := r.db.Query(ctx, query, projectID)
+	rows, err := conn(ctx, r.db).Query(ctx, query, projectID)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
-	var labours []models.Labour
+	var labours []models.AssignedLabour
 	for rows.Next() {
-		var l models.Labour
-		err := rows.Scan(&l.ID, &l.Name, &l.Phone, &l.DailyWage,
-			&l.CreatedAt, &l.UpdatedAt)
+		var l models.AssignedLabour
+		err := rows.Scan(&l.ID, &l.UserID, &l.OrganisationID, &l.Name, &l.Phone, &l.DailyWage,
+			&l.OvertimeRate, &l.CreatedAt, &l.UpdatedAt, &l.ProjectDailyWage, &l.Role, &l.AssignedAt)
 		if err != nil {
 			return nil, err
 		}
@@ -117,16 +127,24 @@ func (r *LabourRepository) GetByProjectID(ctx context.Context, projectID uuid.UU
 	return labours, rows.Err()
 }
 
-// Update updates a labour
-func (r *LabourRepository) Update(ctx context.Context, labour *models.Labour) error {
+// Update updates a labour. When rate is non-nil it is appended to the
+// labour's wage history in the same transaction, leaving earlier rates intact.
+func (r *LabourRepository) Update(ctx context.Context, labour *models.Labour, rate *models.WageRate) error {
+	tx, err := conn(ctx, r.db).Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
 	query := `
 		UPDATE labours
-		SET name = $2, phone = $3, daily_wage = $4, updated_at = NOW()
-		WHERE id = $1
+		SET name = $2, phone = $3, daily_wage = $4, overtime_rate = $5, updated_at = NOW()
+		WHERE id = $1 AND deleted_at IS NULL
 		RETURNING updated_at
 	`
 
-	err := r.db.QueryRow(ctx, query, labour.ID, labour.Name, labour.Phone, labour.DailyWage).
+	err = tx.QueryRow(ctx, query, labour.ID, labour.Name, labour.Phone, labour.DailyWage,
+		labour.OvertimeRate).
 		Scan(&labour.UpdatedAt)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
@@ -135,42 +153,208 @@ func (r *LabourRepository) Update(ctx context.Context, labour *models.Labour) er
 		return err
 	}
 
+	if rate != nil {
+		if err := insertWageRate(ctx, tx, rate); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit(ctx)
+}
+
+// Delete moves a labour to the trash, keeping their work days and payments.
+// labour.DeletedBy is recorded as the user who deleted them.
+func (r *LabourRepository) Delete(ctx context.Context, labour *models.Labour) error {
+	query := `
+		UPDATE labours
+		SET deleted_at = NOW(), deleted_by = $2
+		WHERE id = $1 AND deleted_at IS NULL
+		RETURNING deleted_at
+	`
+
+	err := conn(ctx, r.db).QueryRow(ctx, query, labour.ID, labour.DeletedBy).Scan(&labour.DeletedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return models.ErrNotFound
+		}
+		return err
+	}
+
 	return nil
 }
 
-// Delete deletes a labour
-func (r *LabourRepository) Delete(ctx context.Context, id uuid.UUID) error {
-	query := `DELETE FROM labours WHERE id = $1`
+// GetDeletedByID retrieves a labour in the trash by ID
+func (r *LabourRepository) GetDeletedByID(ctx context.Context, id uuid.UUID) (*models.Labour, error) {
+	query := `
+		SELECT id, user_id, organisation_id, name, phone, daily_wage, overtime_rate, created_at, updated_at,
+			deleted_at, deleted_by
+		FROM labours
+		WHERE id = $1 AND deleted_at IS NOT NULL
+	`
+
+	labour := &models.Labour{}
+	err := conn(ctx, r.db).QueryRow(ctx, query, id).
+		Scan(&labour.ID, &labour.UserID, &labour.OrganisationID, &labour.Name, &labour.Phone, &labour.DailyWage,
+			&labour.OvertimeRate, &labour.CreatedAt, &labour.UpdatedAt, &labour.DeletedAt, &labour.DeletedBy)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, models.ErrNotFound
+		}
+		return nil, err
+	}
+
+	return labour, nil
+}
+
+// GetDeletedByUserID retrieves the trashed labours in the organisations a
+// user belongs to, most recently deleted first
+func (r *LabourRepository) GetDeletedByUserID(ctx context.Context, userID uuid.UUID) ([]models.Labour, error) {
+	query := `
+		SELECT id, user_id, organisation_id, name, phone, daily_wage, overtime_rate, created_at, updated_at,
+			deleted_at, deleted_by
+		FROM labours
+		WHERE deleted_at IS NOT NULL
+			AND organisation_id IN (SELECT organisation_id FROM organisation_members WHERE user_id = $1)
+		ORDER BY deleted_at DESC
+	`
 
-	result, err := r.db.Exec(ctx, query, id)
+	rows, err := conn(ctx, r.db).Query(ctx, query, userID)
 	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var labours []models.Labour
+	for rows.Next() {
+		var l models.Labour
+		err := rows.Scan(&l.ID, &l.UserID, &l.OrganisationID, &l.Name, &l.Phone, &l.DailyWage,
+			&l.OvertimeRate, &l.CreatedAt, &l.UpdatedAt, &l.DeletedAt, &l.DeletedBy)
+		if err != nil {
+			return nil, err
+		}
+		labours = append(labours, l)
+	}
+
+	return labours, rows.Err()
+}
+
+// Restore takes a labour out of the trash
+func (r *LabourRepository) Restore(ctx context.Context, labour *models.Labour) error {
+	query := `
+		UPDATE labours
+		SET deleted_at = NULL, deleted_by = NULL, updated_at = NOW()
+		WHERE id = $1 AND deleted_at IS NOT NULL
+		RETURNING updated_at
+	`
+
+	err := conn(ctx, r.db).QueryRow(ctx, query, labour.ID).Scan(&labour.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return models.ErrNotFound
+		}
 		return err
 	}
 
+	labour.DeletedAt = nil
+	labour.DeletedBy = nil
+	return nil
+}
+
+// Purge permanently deletes a trashed labour along with their work days,
+// payments and wage history. Recoveries against their advances go first, as
+// they would otherwise block the cascade.
+func (r *LabourRepository) Purge(ctx context.Context, id uuid.UUID) error {
+	tx, err := conn(ctx, r.db).Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	recoveriesQuery := `
+		DELETE FROM advance_recoveries
+		WHERE advance_id IN (
+			SELECT p.id FROM payments p
+			INNER JOIN labours l ON l.id = p.labour_id
+			WHERE l.id = $1 AND l.deleted_at IS NOT NULL
+		)
+	`
+	if _, err := tx.Exec(ctx, recoveriesQuery, id); err != nil {
+		return err
+	}
+
+	result, err := tx.Exec(ctx, `DELETE FROM labours WHERE id = $1 AND deleted_at IS NOT NULL`, id)
+	if err != nil {
+		return err
+	}
 	if result.RowsAffected() == 0 {
 		return models.ErrNotFound
 	}
 
-	return nil
+	return tx.Commit(ctx)
 }
 
-// AssignToProject assigns a labour to a project
-func (r *LabourRepository) AssignToProject(ctx context.Context, projectID, labourID uuid.UUID) error {
+// AssignToProject assigns a labour to a project, or updates the terms of an
+// existing assignment. When rate is non-nil it is appended to the labour's
+// wage history in the same transaction; earnings are priced from that
+// history, and a nil daily wage keeps the assignment's current one.
+func (r *LabourRepository) AssignToProject(ctx context.Context, assignment *models.ProjectLabour, rate *models.WageRate) error {
+	tx, err := conn(ctx, r.db).Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
 	query := `
-		INSERT INTO project_labours (project_id, labour_id)
-		VALUES ($1, $2)
-		ON CONFLICT (project_id, labour_id) DO NOTHING
+		INSERT INTO project_labours (project_id, labour_id, daily_wage, role)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (project_id, labour_id) DO UPDATE
+		SET daily_wage = COALESCE(EXCLUDED.daily_wage, project_labours.daily_wage),
+			role = CASE WHEN EXCLUDED.role = '' THEN project_labours.role ELSE EXCLUDED.role END
+		RETURNING daily_wage, role, assigned_at
 	`
 
-	_, err := r.db.Exec(ctx, query, projectID, labourID)
-	return err
+	err = tx.QueryRow(ctx, query, assignment.ProjectID, assignment.LabourID,
+		assignment.DailyWage, assignment.Role).
+		Scan(&assignment.DailyWage, &assignment.Role, &assignment.AssignedAt)
+	if err != nil {
+		return err
+	}
+
+	if rate != nil {
+		if err := insertWageRate(ctx, tx, rate); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit(ctx)
+}
+
+// GetAssignment retrieves a labour's terms on a project
+func (r *LabourRepository) GetAssignment(ctx context.Context, projectID, labourID uuid.UUID) (*models.ProjectLabour, error) {
+	query := `
+		SELECT project_id, labour_id, daily_wage, role, assigned_at
+		FROM project_labours
+		WHERE project_id = $1 AND labour_id = $2
+	`
+
+	assignment := &models.ProjectLabour{}
+	err := conn(ctx, r.db).QueryRow(ctx, query, projectID, labourID).
+		Scan(&assignment.ProjectID, &assignment.LabourID, &assignment.DailyWage, &assignment.Role, &assignment.AssignedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, models.ErrNotFound
+		}
+		return nil, err
+	}
+
+	return assignment, nil
 }
 
 // RemoveFromProject removes a labour from a project
 func (r *LabourRepository) RemoveFromProject(ctx context.Context, projectID, labourID uuid.UUID) error {
 	query := `DELETE FROM project_labours WHERE project_id = $1 AND labour_id = $2`
 
-	result, err := r.db.Exec(ctx, query, projectID, labourID)
+	result, err := conn(ctx, r.db).Exec(ctx, query, projectID, labourID)
 	if err != nil {
 		return err
 	}
@@ -182,12 +366,106 @@ func (r *LabourRepository) RemoveFromProject(ctx context.Context, projectID, lab
 	return nil
 }
 
-// IsAssignedToProject checks if a labour is assigned to a project
+// IsAssignedToProject checks if a labour is assigned to a project and not in
+// the trash
 func (r *LabourRepository) IsAssignedToProject(ctx context.Context, projectID, labourID uuid.UUID) (bool, error) {
-	query := `SELECT EXISTS(SELECT 1 FROM project_labours WHERE project_id = $1 AND labour_id = $2)`
+	query := `
+		SELECT EXISTS(
+			SELECT 1 FROM project_labours pl
+			INNER JOIN labours l ON l.id = pl.labour_id
+			WHERE pl.project_id = $1 AND pl.labour_id = $2 AND l.deleted_at IS NULL
+		)
+	`
+
+	var exists bool
+	err := conn(ctx, r.db).QueryRow(ctx, query, projectID, labourID).Scan(&exists)
+	if err != nil {
+		return false, err
+	}
+
+	return exists, nil
+}
+
+// CreateWageRate appends a rate to a labour's wage history
+func (r *LabourRepository) CreateWageRate(ctx context.Context, rate *models.WageRate) error {
+	return insertWageRate(ctx, conn(ctx, r.db), rate)
+}
+
+// GetWageRates retrieves a labour's wage history, newest first
+func (r *LabourRepository) GetWageRates(ctx context.Context, labourID uuid.UUID) ([]models.WageRate, error) {
+	query := `
+		SELECT id, labour_id, project_id, daily_wage, effective_from, created_at
+		FROM labour_wage_rates
+		WHERE labour_id = $1
+		ORDER BY effective_from DESC, created_at DESC
+	`
+
+	rows, err := conn(ctx, r.db).Query(ctx, query, labourID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var rates []models.WageRate
+	for rows.Next() {
+		var wr models.WageRate
+		err := rows.Scan(&wr.ID, &wr.LabourID, &wr.ProjectID, &wr.DailyWage,
+			&wr.EffectiveFrom, &wr.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		rates = append(rates, wr)
+	}
+
+	return rates, rows.Err()
+}
+
+func insertWageRate(ctx context.Context, q querier, rate *models.WageRate) error {
+	query := `
+		INSERT INTO labour_wage_rates (labour_id, project_id, daily_wage, effective_from)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, created_at
+	`
+
+	return q.QueryRow(ctx, query, rate.LabourID, rate.ProjectID, rate.DailyWage, rate.EffectiveFrom).
+		Scan(&rate.ID, &rate.CreatedAt)
+}
+
+// GetByPhone retrieves every labour record with the given phone, across organisations
+func (r *LabourRepository) GetByPhone(ctx context.Context, phone string) ([]models.Labour, error) {
+	query := `
+		SELECT id, user_id, organisation_id, name, phone, daily_wage, overtime_rate, created_at, updated_at
+		FROM labours
+		WHERE phone = $1 AND deleted_at IS NULL
+		ORDER BY created_at ASC
+	`
+
+	rows, err := conn(ctx, r.db).Query(ctx, query, phone)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var labours []models.Labour
+	for rows.Next() {
+		var l models.Labour
+		err := rows.Scan(&l.ID, &l.UserID, &l.OrganisationID, &l.Name, &l.Phone, &l.DailyWage,
+			&l.OvertimeRate, &l.CreatedAt, &l.UpdatedAt)
+		if err != nil {
+			return nil, err
+		}
+		labours = append(labours, l)
+	}
+
+	return labours, rows.Err()
+}
+
+// ExistsByPhone checks if any labour is registered with the given phone
+func (r *LabourRepository) ExistsByPhone(ctx context.Context, phone string) (bool, error) {
+	query := `SELECT EXISTS(SELECT 1 FROM labours WHERE phone = $1 AND deleted_at IS NULL)`
 
 	var exists bool
-	err := r.db.QueryRow(ctx, query, projectID, labourID).Scan(&exists)
+	err := conn(ctx, r.db).QueryRow(ctx, query, phone).Scan(&exists)
 	if err != nil {
 		return false, err
 	}
@@ -0,0 +1,445 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/vivekanand/labour-thekedar-backend/internal/models"
+)
+
+// OrganisationRepository handles organisation, membership and invitation database operations
+type OrganisationRepository struct {
+	db *pgxpool.Pool
+}
+
+// NewOrganisationRepository creates a new OrganisationRepository
+func NewOrganisationRepository(db *pgxpool.Pool) *OrganisationRepository {
+	return &OrganisationRepository{db: db}
+}
+
+// Create creates a new organisation with the given user as its owner
+func (r *OrganisationRepository) Create(ctx context.Context, org *models.Organisation, ownerID uuid.UUID) error {
+	_, err := r.create(ctx, org, ownerID, false)
+	return err
+}
+
+// CreatePersonal creates the user's personal organisation, owned by them. It
+// returns false without error when another request created it first.
+func (r *OrganisationRepository) CreatePersonal(ctx context.Context, org *models.Organisation, ownerID uuid.UUID) (bool, error) {
+	return r.create(ctx, org, ownerID, true)
+}
+
+func (r *OrganisationRepository) create(ctx context.Context, org *models.Organisation, ownerID uuid.UUID, personal bool) (bool, error) {
+	tx, err := conn(ctx, r.db).Begin(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback(ctx)
+
+	query := `
+		INSERT INTO organisations (name, created_by, personal_for)
+		VALUES ($1, $2, CASE WHEN $3 THEN $2::uuid END)
+		ON CONFLICT (personal_for) DO NOTHING
+		RETURNING id, created_by, created_at, updated_at
+	`
+
+	err = tx.QueryRow(ctx, query, org.Name, ownerID, personal).
+		Scan(&org.ID, &org.CreatedBy, &org.CreatedAt, &org.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	query = `INSERT INTO organisation_members (organisation_id, user_id, role) VALUES ($1, $2, $3)`
+	if _, err := tx.Exec(ctx, query, org.ID, ownerID, models.RoleOwner); err != nil {
+		return false, err
+	}
+
+	return true, tx.Commit(ctx)
+}
+
+// GetByUserID retrieves all organisations a user is a member of, with their role
+func (r *OrganisationRepository) GetByUserID(ctx context.Context, userID uuid.UUID) ([]models.OrganisationWithRole, error) {
+	query := `
+		SELECT o.id, o.name, o.created_by, o.created_at, o.updated_at, m.role
+		FROM organisations o
+		INNER JOIN organisation_members m ON m.organisation_id = o.id
+		WHERE m.user_id = $1
+		ORDER BY o.created_at ASC
+	`
+
+	rows, err := conn(ctx, r.db).Query(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var orgs []models.OrganisationWithRole
+	for rows.Next() {
+		var o models.OrganisationWithRole
+		err := rows.Scan(&o.ID, &o.Name, &o.CreatedBy, &o.CreatedAt, &o.UpdatedAt, &o.Role)
+		if err != nil {
+			return nil, err
+		}
+		orgs = append(orgs, o)
+	}
+
+	return orgs, rows.Err()
+}
+
+// GetDefaultForUser retrieves the oldest organisation the user owns
+func (r *OrganisationRepository) GetDefaultForUser(ctx context.Context, userID uuid.UUID) (*models.Organisation, error) {
+	query := `
+		SELECT o.id, o.name, o.created_by, o.created_at, o.updated_at
+		FROM organisations o
+		INNER JOIN organisation_members m ON m.organisation_id = o.id
+		WHERE m.user_id = $1 AND m.role = 'owner'
+		ORDER BY o.created_at ASC
+		LIMIT 1
+	`
+
+	org := &models.Organisation{}
+	err := conn(ctx, r.db).QueryRow(ctx, query, userID).
+		Scan(&org.ID, &org.Name, &org.CreatedBy, &org.CreatedAt, &org.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, models.ErrNotFound
+		}
+		return nil, err
+	}
+
+	return org, nil
+}
+
+// GetRole returns a user's role in an organisation, or "" if they are not a member
+func (r *OrganisationRepository) GetRole(ctx context.Context, orgID, userID uuid.UUID) (models.Role, error) {
+	query := `SELECT role FROM organisation_members WHERE organisation_id = $1 AND user_id = $2`
+
+	return r.scanRole(ctx, query, orgID, userID)
+}
+
+// GetRoleForProject returns a user's role in the organisation owning a project,
+// or "" if they have none
+func (r *OrganisationRepository) GetRoleForProject(ctx context.Context, projectID, userID uuid.UUID) (models.Role, error) {
+	query := `
+		SELECT m.role
+		FROM projects p
+		INNER JOIN organisation_members m ON m.organisation_id = p.organisation_id
+		WHERE p.id = $1 AND m.user_id = $2
+	`
+
+	return r.scanRole(ctx, query, projectID, userID)
+}
+
+// GetRoleForLabour returns a user's role in the organisation owning a labour,
+// or "" if they have none
+func (r *OrganisationRepository) GetRoleForLabour(ctx context.Context, labourID, userID uuid.UUID) (models.Role, error) {
+	query := `
+		SELECT m.role
+		FROM labours l
+		INNER JOIN organisation_members m ON m.organisation_id = l.organisation_id
+		WHERE l.id = $1 AND m.user_id = $2
+	`
+
+	return r.scanRole(ctx, query, labourID, userID)
+}
+
+func (r *OrganisationRepository) scanRole(ctx context.Context, query string, args ...any) (models.Role, error) {
+	var role models.Role
+	err := conn(ctx, r.db).QueryRow(ctx, query, args...).Scan(&role)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return "", nil
+		}
+		return "", err
+	}
+
+	return role, nil
+}
+
+// GetMembers retrieves all members of an organisation
+func (r *OrganisationRepository) GetMembers(ctx context.Context, orgID uuid.UUID) ([]models.OrganisationMember, error) {
+	query := `
+		SELECT m.organisation_id, m.user_id, u.phone, COALESCE(u.name, ''), m.role, m.created_at
+		FROM organisation_members m
+		INNER JOIN users u ON u.id = m.user_id
+		WHERE m.organisation_id = $1
+		ORDER BY m.created_at ASC
+	`
+
+	rows, err := conn(ctx, r.db).Query(ctx, query, orgID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var members []models.OrganisationMember
+	for rows.Next() {
+		var m models.OrganisationMember
+		err := rows.Scan(&m.OrganisationID, &m.UserID, &m.Phone, &m.Name, &m.Role, &m.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		members = append(members, m)
+	}
+
+	return members, rows.Err()
+}
+
+// UpdateMemberRole changes a member's role. Demoting the last owner returns
+// models.ErrLastOwner.
+func (r *OrganisationRepository) UpdateMemberRole(ctx context.Context, orgID, userID uuid.UUID, role models.Role) error {
+	tx, err := conn(ctx, r.db).Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if role != models.RoleOwner {
+		if err := guardLastOwner(ctx, tx, orgID, userID); err != nil {
+			return err
+		}
+	}
+
+	query := `UPDATE organisation_members SET role = $3 WHERE organisation_id = $1 AND user_id = $2`
+
+	result, err := tx.Exec(ctx, query, orgID, userID, role)
+	if err != nil {
+		return err
+	}
+	if result.RowsAffected() == 0 {
+		return models.ErrNotFound
+	}
+
+	if role != models.RoleOwner {
+		if err := releasePersonal(ctx, tx, orgID, userID); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit(ctx)
+}
+
+// RemoveMember removes a member from an organisation. Removing the last owner
+// returns models.ErrLastOwner.
+func (r *OrganisationRepository) RemoveMember(ctx context.Context, orgID, userID uuid.UUID) error {
+	tx, err := conn(ctx, r.db).Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if err := guardLastOwner(ctx, tx, orgID, userID); err != nil {
+		return err
+	}
+
+	query := `DELETE FROM organisation_members WHERE organisation_id = $1 AND user_id = $2`
+
+	result, err := tx.Exec(ctx, query, orgID, userID)
+	if err != nil {
+		return err
+	}
+	if result.RowsAffected() == 0 {
+		return models.ErrNotFound
+	}
+
+	if err := releasePersonal(ctx, tx, orgID, userID); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// releasePersonal stops an organisation being the user's personal one once
+// they no longer own it, so a new one is created when they next need it
+func releasePersonal(ctx context.Context, tx pgx.Tx, orgID, userID uuid.UUID) error {
+	query := `UPDATE organisations SET personal_for = NULL WHERE id = $1 AND personal_for = $2`
+
+	_, err := tx.Exec(ctx, query, orgID, userID)
+	return err
+}
+
+// guardLastOwner returns models.ErrLastOwner if userID is the only owner of
+// the organisation. The owner rows are locked so concurrent demotions can't
+// both pass the check.
+func guardLastOwner(ctx context.Context, tx pgx.Tx, orgID, userID uuid.UUID) error {
+	query := `
+		SELECT user_id FROM organisation_members
+		WHERE organisation_id = $1 AND role = 'owner'
+		FOR UPDATE
+	`
+
+	rows, err := tx.Query(ctx, query, orgID)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	var owners []uuid.UUID
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return err
+		}
+		owners = append(owners, id)
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	if len(owners) == 1 && owners[0] == userID {
+		return models.ErrLastOwner
+	}
+
+	return nil
+}
+
+// CreateInvitation creates an invitation, replacing any expired one for the
+// same phone. An open invitation for the phone returns models.ErrAlreadyExists.
+func (r *OrganisationRepository) CreateInvitation(ctx context.Context, inv *models.Invitation) error {
+	tx, err := conn(ctx, r.db).Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	query := `
+		DELETE FROM organisation_invitations
+		WHERE organisation_id = $1 AND phone = $2 AND accepted_at IS NULL AND expires_at <= NOW()
+	`
+	if _, err := tx.Exec(ctx, query, inv.OrganisationID, inv.Phone); err != nil {
+		return err
+	}
+
+	query = `
+		INSERT INTO organisation_invitations (organisation_id, phone, role, invited_by, expires_at)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, created_at
+	`
+
+	err = tx.QueryRow(ctx, query, inv.OrganisationID, inv.Phone, inv.Role, inv.InvitedBy, inv.ExpiresAt).
+		Scan(&inv.ID, &inv.CreatedAt)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return models.ErrAlreadyExists
+		}
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// GetPendingInvitations retrieves an organisation's open invitations
+func (r *OrganisationRepository) GetPendingInvitations(ctx context.Context, orgID uuid.UUID) ([]models.Invitation, error) {
+	query := `
+		SELECT i.id, i.organisation_id, o.name, i.phone, i.role, i.invited_by, i.expires_at, i.accepted_at, i.created_at
+		FROM organisation_invitations i
+		INNER JOIN organisations o ON o.id = i.organisation_id
+		WHERE i.organisation_id = $1 AND i.accepted_at IS NULL AND i.expires_at > NOW()
+		ORDER BY i.created_at DESC
+	`
+
+	return r.queryInvitations(ctx, query, orgID)
+}
+
+// GetPendingInvitationsByPhone retrieves open invitations addressed to a phone number
+func (r *OrganisationRepository) GetPendingInvitationsByPhone(ctx context.Context, phone string) ([]models.Invitation, error) {
+	query := `
+		SELECT i.id, i.organisation_id, o.name, i.phone, i.role, i.invited_by, i.expires_at, i.accepted_at, i.created_at
+		FROM organisation_invitations i
+		INNER JOIN organisations o ON o.id = i.organisation_id
+		WHERE i.phone = $1 AND i.accepted_at IS NULL AND i.expires_at > NOW()
+		ORDER BY i.created_at DESC
+	`
+
+	return r.queryInvitations(ctx, query, phone)
+}
+
+func (r *OrganisationRepository) queryInvitations(ctx context.Context, query string, args ...any) ([]models.Invitation, error) {
+	rows, err := conn(ctx, r.db).Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var invitations []models.Invitation
+	for rows.Next() {
+		var i models.Invitation
+		err := rows.Scan(&i.ID, &i.OrganisationID, &i.OrganisationName, &i.Phone, &i.Role,
+			&i.InvitedBy, &i.ExpiresAt, &i.AcceptedAt, &i.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		invitations = append(invitations, i)
+	}
+
+	return invitations, rows.Err()
+}
+
+// DeleteInvitation withdraws an open invitation
+func (r *OrganisationRepository) DeleteInvitation(ctx context.Context, orgID, invitationID uuid.UUID) error {
+	query := `
+		DELETE FROM organisation_invitations
+		WHERE id = $1 AND organisation_id = $2 AND accepted_at IS NULL
+	`
+
+	result, err := conn(ctx, r.db).Exec(ctx, query, invitationID, orgID)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return models.ErrNotFound
+	}
+
+	return nil
+}
+
+// AcceptInvitation marks an open invitation for the phone as accepted and
+// adds the user to the organisation. Existing members keep their current role.
+func (r *OrganisationRepository) AcceptInvitation(ctx context.Context, invitationID, userID uuid.UUID, phone string) (uuid.UUID, error) {
+	tx, err := conn(ctx, r.db).Begin(ctx)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	query := `
+		UPDATE organisation_invitations
+		SET accepted_at = NOW()
+		WHERE id = $1 AND phone = $2 AND accepted_at IS NULL AND expires_at > NOW()
+		RETURNING organisation_id, role
+	`
+
+	var orgID uuid.UUID
+	var role models.Role
+	err = tx.QueryRow(ctx, query, invitationID, phone).Scan(&orgID, &role)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return uuid.Nil, models.ErrNotFound
+		}
+		return uuid.Nil, err
+	}
+
+	query = `
+		INSERT INTO organisation_members (organisation_id, user_id, role)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (organisation_id, user_id) DO NOTHING
+	`
+	if _, err := tx.Exec(ctx, query, orgID, userID, role); err != nil {
+		return uuid.Nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return uuid.Nil, err
+	}
+
+	return orgID, nil
+}
@@ -0,0 +1,91 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/vivekanand/labour-thekedar-backend/pkg/otp"
+)
+
+// OTPRepository stores hashed OTP codes in Postgres so they survive restarts
+// and are shared by every instance. It implements otp.Store.
+type OTPRepository struct {
+	db *pgxpool.Pool
+}
+
+// NewOTPRepository creates a new OTPRepository
+func NewOTPRepository(db *pgxpool.Pool) *OTPRepository {
+	return &OTPRepository{db: db}
+}
+
+// Save stores a new code for the phone, invalidating any earlier unused code
+func (r *OTPRepository) Save(ctx context.Context, phone string, codeHash string, expiresAt time.Time) error {
+	query := `
+		WITH invalidated AS (
+			UPDATE otp_codes
+			SET expires_at = NOW()
+			WHERE phone = $1 AND NOT verified AND expires_at > NOW()
+		)
+		INSERT INTO otp_codes (phone, code_hash, expires_at)
+		VALUES ($1, $2, $3)
+	`
+
+	_, err := conn(ctx, r.db).Exec(ctx, query, phone, codeHash, expiresAt)
+	return err
+}
+
+// Consume checks codeHash against the phone's latest active code and counts
+// the attempt. The row is locked, so a code can only be consumed once even
+// when verified concurrently on different instances. The wrong guess that
+// reaches otp.MaxVerifyAttempts expires the code.
+func (r *OTPRepository) Consume(ctx context.Context, phone string, codeHash string) (bool, error) {
+	query := `
+		UPDATE otp_codes
+		SET attempts = attempts + 1,
+			verified = (code_hash = $2),
+			expires_at = CASE
+				WHEN code_hash <> $2 AND attempts + 1 >= $3 THEN NOW()
+				ELSE expires_at
+			END
+		WHERE id = (
+			SELECT id
+			FROM otp_codes
+			WHERE phone = $1 AND NOT verified AND expires_at > NOW()
+			ORDER BY created_at DESC
+			LIMIT 1
+			FOR UPDATE
+		) AND NOT verified
+		RETURNING verified, attempts
+	`
+
+	var verified bool
+	var attempts int
+	err := conn(ctx, r.db).QueryRow(ctx, query, phone, codeHash, otp.MaxVerifyAttempts).Scan(&verified, &attempts)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	if !verified && attempts >= otp.MaxVerifyAttempts {
+		return false, otp.ErrTooManyAttempts
+	}
+
+	return verified, nil
+}
+
+// DeleteExpired removes expired and used codes
+func (r *OTPRepository) DeleteExpired(ctx context.Context) (int64, error) {
+	query := `DELETE FROM otp_codes WHERE expires_at < NOW() OR verified`
+
+	result, err := conn(ctx, r.db).Exec(ctx, query)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected(), nil
+}
@@ -3,6 +3,7 @@ package repository
 import (
 	"context"
 	"errors"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
@@ -11,6 +12,24 @@ import (
 	"github.com/vivekanand/labour-thekedar-backend/internal/models"
 )
 
+// signedAmount is a payment's effect on what the labour is owed, matching
+// models.PaymentType.Sign: refunds add to it, every other type settles it.
+const signedAmount = `CASE WHEN payment_type = 'refund' THEN -amount ELSE amount END`
+
+// paymentColumns selects every column of payment p, in the order of paymentFields
+const paymentColumns = `
+	p.id, p.project_id, p.labour_id, p.amount, p.payment_date, p.payment_type, p.notes,
+	p.recovery_type, p.recovery_value, p.voided_at, p.voided_by, p.void_reason,
+	p.created_at, p.updated_at
+`
+
+// paymentFields returns the scan destinations for paymentColumns
+func paymentFields(p *models.Payment) []any {
+	return []any{&p.ID, &p.ProjectID, &p.LabourID, &p.Amount, &p.PaymentDate, &p.PaymentType, &p.Notes,
+		&p.RecoveryType, &p.RecoveryValue, &p.VoidedAt, &p.VoidedBy, &p.VoidReason,
+		&p.CreatedAt, &p.UpdatedAt}
+}
+
 // PaymentRepository handles payment database operations
 type PaymentRepository struct {
 	db *pgxpool.Pool
@@ -24,14 +43,15 @@ func NewPaymentRepository(db *pgxpool.Pool) *PaymentRepository {
 // Create creates a new payment record
 func (r *PaymentRepository) Create(ctx context.Context, payment *models.Payment) error {
 	query := `
-		INSERT INTO payments (project_id, labour_id, amount, payment_date, payment_type, notes)
-		VALUES ($1, $2, $3, $4, $5, $6)
-		RETURNING id, created_at
+		INSERT INTO payments (project_id, labour_id, amount, payment_date, payment_type, notes, recovery_type, recovery_value)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING id, created_at, updated_at
 	`
 
-	err := r.db.QueryRow(ctx, query, payment.ProjectID, payment.LabourID,
-		payment.Amount, payment.PaymentDate, payment.PaymentType, payment.Notes).
-		Scan(&payment.ID, &payment.CreatedAt)
+	err := conn(ctx, r.db).QueryRow(ctx, query, payment.ProjectID, payment.LabourID,
+		payment.Amount, payment.PaymentDate, payment.PaymentType, payment.Notes,
+		payment.RecoveryType, payment.RecoveryValue).
+		Scan(&payment.ID, &payment.CreatedAt, &payment.UpdatedAt)
 	if err != nil {
 		return err
 	}
@@ -41,17 +61,10 @@ func (r *PaymentRepository) Create(ctx context.Context, payment *models.Payment)
 
 // GetByID retrieves a payment by ID
 func (r *PaymentRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Payment, error) {
-	query := `
-		SELECT id, project_id, labour_id, amount, payment_date, payment_type, notes, created_at
-		FROM payments
-		WHERE id = $1
-	`
+	query := `SELECT ` + paymentColumns + ` FROM payments p WHERE p.id = $1`
 
 	payment := &models.Payment{}
-	err := r.db.QueryRow(ctx, query, id).
-		Scan(&payment.ID, &payment.ProjectID, &payment.LabourID,
-			&payment.Amount, &payment.PaymentDate, &payment.PaymentType,
-			&payment.Notes, &payment.CreatedAt)
+	err := conn(ctx, r.db).QueryRow(ctx, query, id).Scan(paymentFields(payment)...)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, models.ErrNotFound
@@ -62,17 +75,17 @@ func (r *PaymentRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.
 	return payment, nil
 }
 
-// GetByProjectID retrieves all payments for a project
+// GetByProjectID retrieves all payments for a project, voided ones included
 func (r *PaymentRepository) GetByProjectID(ctx context.Context, projectID uuid.UUID) ([]models.PaymentWithLabour, error) {
 	query := `
-		SELECT p.id, p.project_id, p.labour_id, p.amount, p.payment_date, p.payment_type, p.notes, p.created_at, l.name
+		SELECT ` + paymentColumns + `, l.name
 		FROM payments p
 		INNER JOIN labours l ON p.labour_id = l.id
 		WHERE p.project_id = $1
 		ORDER BY p.payment_date DESC, l.name ASC
 	`
 
-	rows, err := r.db.Query(ctx, query, projectID)
+	rows, err := conn(ctx, r.db).Query(ctx, query, projectID)
 	if err != nil {
 		return nil, err
 	}
@@ -81,9 +94,7 @@ func (r *PaymentRepository) GetByProjectID(ctx context.Context, projectID uuid.U
 	var payments []models.PaymentWithLabour
 	for rows.Next() {
 		var p models.PaymentWithLabour
-		err := rows.Scan(&p.ID, &p.ProjectID, &p.LabourID,
-			&p.Amount, &p.PaymentDate, &p.PaymentType,
-			&p.Notes, &p.CreatedAt, &p.LabourName)
+		err := rows.Scan(append(paymentFields(&p.Payment), &p.LabourName)...)
 		if err != nil {
 			return nil, err
 		}
@@ -93,16 +104,16 @@ func (r *PaymentRepository) GetByProjectID(ctx context.Context, projectID uuid.U
 	return payments, rows.Err()
 }
 
-// GetByLabourID retrieves all payments for a labour
+// GetByLabourID retrieves all payments for a labour, voided ones included
 func (r *PaymentRepository) GetByLabourID(ctx context.Context, labourID uuid.UUID) ([]models.Payment, error) {
 	query := `
-		SELECT id, project_id, labour_id, amount, payment_date, payment_type, notes, created_at
-		FROM payments
-		WHERE labour_id = $1
-		ORDER BY payment_date DESC
+		SELECT ` + paymentColumns + `
+		FROM payments p
+		WHERE p.labour_id = $1
+		ORDER BY p.payment_date DESC
 	`
 
-	rows, err := r.db.Query(ctx, query, labourID)
+	rows, err := conn(ctx, r.db).Query(ctx, query, labourID)
 	if err != nil {
 		return nil, err
 	}
@@ -111,9 +122,7 @@ func (r *PaymentRepository) GetByLabourID(ctx context.Context, labourID uuid.UUI
 	var payments []models.Payment
 	for rows.Next() {
 		var p models.Payment
-		err := rows.Scan(&p.ID, &p.ProjectID, &p.LabourID,
-			&p.Amount, &p.PaymentDate, &p.PaymentType,
-			&p.Notes, &p.CreatedAt)
+		err := rows.Scan(paymentFields(&p)...)
 		if err != nil {
 			return nil, err
 		}
@@ -124,13 +133,18 @@ func (r *PaymentRepository) GetByLabourID(ctx context.Context, labourID uuid.UUI
 }
 
 // GetBalance calculates the balance for a labour in a project
-// Balance = Total Earned (from work days) - Total Paid
+// Balance = Total Earned (from work days) - Total Paid. Voided payments are left out.
+// A labour not assigned to the project returns models.ErrNotFound.
 func (r *PaymentRepository) GetBalance(ctx context.Context, projectID, labourID uuid.UUID) (*models.BalanceResponse, error) {
 	// Get labour info
-	labourQuery := `SELECT name, daily_wage FROM labours WHERE id = $1`
+	labourQuery := `
+		SELECT l.name
+		FROM labours l
+		INNER JOIN project_labours pl ON pl.labour_id = l.id
+		WHERE l.id = $1 AND pl.project_id = $2 AND l.deleted_at IS NULL
+	`
 	var labourName string
-	var dailyWage decimal.Decimal
-	err := r.db.QueryRow(ctx, labourQuery, labourID).Scan(&labourName, &dailyWage)
+	err := conn(ctx, r.db).QueryRow(ctx, labourQuery, labourID, projectID).Scan(&labourName)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, models.ErrNotFound
@@ -138,52 +152,327 @@ func (r *PaymentRepository) GetBalance(ctx context.Context, projectID, labourID
 		return nil, err
 	}
 
-	// Calculate total earned from work days
-	// full_day = 1.0 * daily_wage, half_day = 0.5 * daily_wage, absent = 0
+	// Calculate total earned from work days, each at the wage in force on its date,
+	// including overtime
 	earnedQuery := `
-		SELECT COALESCE(SUM(
-			CASE status
-				WHEN 'full_day' THEN 1.0
-				WHEN 'half_day' THEN 0.5
-				ELSE 0
-			END
-		), 0)
-		FROM work_days
+		SELECT COALESCE(SUM(amount), 0), COALESCE(SUM(overtime_amount), 0)
+		FROM work_day_earnings
 		WHERE project_id = $1 AND labour_id = $2
 	`
-	var workDayMultiplier decimal.Decimal
-	err = r.db.QueryRow(ctx, earnedQuery, projectID, labourID).Scan(&workDayMultiplier)
+	var totalEarned, overtimeEarned decimal.Decimal
+	err = conn(ctx, r.db).QueryRow(ctx, earnedQuery, projectID, labourID).Scan(&totalEarned, &overtimeEarned)
 	if err != nil {
 		return nil, err
 	}
-	totalEarned := dailyWage.Mul(workDayMultiplier)
 
 	// Calculate total paid
 	paidQuery := `
-		SELECT COALESCE(SUM(amount), 0)
+		SELECT COALESCE(SUM(` + signedAmount + `), 0)
 		FROM payments
-		WHERE project_id = $1 AND labour_id = $2
+		WHERE project_id = $1 AND labour_id = $2 AND voided_at IS NULL
 	`
 	var totalPaid decimal.Decimal
-	err = r.db.QueryRow(ctx, paidQuery, projectID, labourID).Scan(&totalPaid)
+	err = conn(ctx, r.db).QueryRow(ctx, paidQuery, projectID, labourID).Scan(&totalPaid)
 	if err != nil {
 		return nil, err
 	}
 
 	return &models.BalanceResponse{
-		LabourID:    labourID,
-		LabourName:  labourName,
-		TotalEarned: totalEarned,
-		TotalPaid:   totalPaid,
-		Balance:     totalEarned.Sub(totalPaid), // Positive = due, Negative = overpaid
+		LabourID:       labourID,
+		LabourName:     labourName,
+		TotalEarned:    totalEarned,
+		OvertimeEarned: overtimeEarned,
+		TotalPaid:      totalPaid,
+		Balance:        totalEarned.Sub(totalPaid), // Positive = due, Negative = overpaid
 	}, nil
 }
 
-// Delete deletes a payment record
-func (r *PaymentRepository) Delete(ctx context.Context, id uuid.UUID) error {
-	query := `DELETE FROM payments WHERE id = $1`
+// GetLabourBalance calculates a labour's balance across all their projects,
+// leaving out voided payments. Positive = due, negative = overpaid.
+func (r *PaymentRepository) GetLabourBalance(ctx context.Context, labourID uuid.UUID) (decimal.Decimal, error) {
+	query := `
+		SELECT
+			(SELECT COALESCE(SUM(amount), 0) FROM work_day_earnings WHERE labour_id = $1)
+			- (SELECT COALESCE(SUM(` + signedAmount + `), 0) FROM payments WHERE labour_id = $1 AND voided_at IS NULL)
+	`
+
+	var balance decimal.Decimal
+	if err := conn(ctx, r.db).QueryRow(ctx, query, labourID).Scan(&balance); err != nil {
+		return decimal.Zero, err
+	}
+
+	return balance, nil
+}
+
+// GetProjectBalances calculates the balance of every labour assigned to a
+// project in a single query. Advance outstanding is what payroll runs have
+// not yet recovered from the labour's advances.
+func (r *PaymentRepository) GetProjectBalances(ctx context.Context, projectID uuid.UUID) ([]models.LabourWithBalance, error) {
+	query := `
+		WITH earned AS (
+			SELECT labour_id, SUM(amount) AS amount, SUM(overtime_amount) AS overtime
+			FROM work_day_earnings
+			WHERE project_id = $1
+			GROUP BY labour_id
+		), paid AS (
+			SELECT labour_id, SUM(` + signedAmount + `) AS amount,
+				SUM(amount) FILTER (WHERE payment_type = 'advance') AS advances
+			FROM payments
+			WHERE project_id = $1 AND voided_at IS NULL
+			GROUP BY labour_id
+		), advances AS (
+			SELECT labour_id, SUM(outstanding) AS outstanding
+			FROM advance_balances
+			WHERE project_id = $1
+			GROUP BY labour_id
+		), totals AS (
+			SELECT pl.labour_id,
+				COALESCE(earned.amount, 0) AS earned,
+				COALESCE(earned.overtime, 0) AS overtime,
+				COALESCE(paid.amount, 0) AS paid,
+				COALESCE(paid.advances, 0) AS advances,
+				COALESCE(advances.outstanding, 0) AS outstanding
+			FROM project_labours pl
+			LEFT JOIN earned ON earned.labour_id = pl.labour_id
+			LEFT JOIN paid ON paid.labour_id = pl.labour_id
+			LEFT JOIN advances ON advances.labour_id = pl.labour_id
+			WHERE pl.project_id = $1
+		)
+		SELECT l.id, l.user_id, l.organisation_id, l.name, l.phone, l.daily_wage, l.overtime_rate, l.created_at, l.updated_at,
+			t.earned, t.overtime, t.paid, t.advances, t.outstanding
+		FROM totals t
+		INNER JOIN labours l ON l.id = t.labour_id
+		WHERE l.deleted_at IS NULL
+		ORDER BY l.name ASC
+	`
+
+	rows, err := conn(ctx, r.db).Query(ctx, query, projectID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var labours []models.LabourWithBalance
+	for rows.Next() {
+		var l models.LabourWithBalance
+		err := rows.Scan(&l.ID, &l.UserID, &l.OrganisationID, &l.Name, &l.Phone, &l.DailyWage,
+			&l.OvertimeRate, &l.CreatedAt, &l.UpdatedAt,
+			&l.TotalEarned, &l.OvertimeEarned, &l.TotalPaid, &l.TotalAdvances, &l.AdvanceOutstanding)
+		if err != nil {
+			return nil, err
+		}
+		l.Balance = l.TotalEarned.Sub(l.TotalPaid)
+		labours = append(labours, l)
+	}
+
+	return labours, rows.Err()
+}
+
+// GetLedgerEntries retrieves a labour's earnings and payments on the given
+// projects in chronological order. Refunds are credits and every other
+// payment a debit. Absent days are left out. A nil from or
+// to leaves that end of the range open.
+func (r *PaymentRepository) GetLedgerEntries(ctx context.Context, labourID uuid.UUID, projectIDs []uuid.UUID, from, to *time.Time) ([]models.LedgerEntry, error) {
+	query := `
+		SELECT entry_date, kind, reference_id, project_id, project_name, description, credit, debit
+		FROM (
+			SELECT e.work_date AS entry_date, 'earning' AS kind, e.id AS reference_id,
+				e.project_id, pr.name AS project_name, e.status::text AS description,
+				e.amount AS credit, 0::numeric AS debit, wd.created_at
+			FROM work_day_earnings e
+			INNER JOIN work_days wd ON wd.id = e.id
+			INNER JOIN projects pr ON e.project_id = pr.id
+			WHERE e.labour_id = $1 AND e.project_id = ANY($2) AND e.status <> 'absent'
+			UNION ALL
+			SELECT p.payment_date, 'payment', p.id,
+				p.project_id, pr.name, p.payment_type::text,
+				CASE WHEN p.payment_type = 'refund' THEN p.amount ELSE 0 END,
+				CASE WHEN p.payment_type = 'refund' THEN 0 ELSE p.amount END,
+				p.created_at
+			FROM payments p
+			INNER JOIN projects pr ON p.project_id = pr.id
+			WHERE p.labour_id = $1 AND p.project_id = ANY($2) AND p.voided_at IS NULL
+		) entries
+		WHERE ($3::date IS NULL OR entry_date >= $3) AND ($4::date IS NULL OR entry_date <= $4)
+		ORDER BY entry_date ASC, kind ASC, created_at ASC
+	`
+
+	rows, err := conn(ctx, r.db).Query(ctx, query, labourID, projectIDs, from, to)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var entries []models.LedgerEntry
+	for rows.Next() {
+		var e models.LedgerEntry
+		err := rows.Scan(&e.Date, &e.Kind, &e.ReferenceID, &e.ProjectID, &e.ProjectName,
+			&e.Description, &e.Credit, &e.Debit)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, e)
+	}
+
+	return entries, rows.Err()
+}
+
+// GetBalancesBefore calculates a labour's balance on each of the given
+// projects from earnings and payments dated before the given date
+func (r *PaymentRepository) GetBalancesBefore(ctx context.Context, labourID uuid.UUID, projectIDs []uuid.UUID, before time.Time) (map[uuid.UUID]decimal.Decimal, error) {
+	query := `
+		SELECT project_id, SUM(amount)
+		FROM (
+			SELECT project_id, amount
+			FROM work_day_earnings
+			WHERE labour_id = $1 AND project_id = ANY($2) AND work_date < $3
+			UNION ALL
+			SELECT project_id, -(` + signedAmount + `)
+			FROM payments
+			WHERE labour_id = $1 AND project_id = ANY($2) AND payment_date < $3 AND voided_at IS NULL
+		) movements
+		GROUP BY project_id
+	`
+
+	rows, err := conn(ctx, r.db).Query(ctx, query, labourID, projectIDs, before)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	balances := make(map[uuid.UUID]decimal.Decimal)
+	for rows.Next() {
+		var projectID uuid.UUID
+		var balance decimal.Decimal
+		if err := rows.Scan(&projectID, &balance); err != nil {
+			return nil, err
+		}
+		balances[projectID] = balance
+	}
+
+	return balances, rows.Err()
+}
+
+// GetByLabourPhone retrieves the payments of every labour record with the given phone
+func (r *PaymentRepository) GetByLabourPhone(ctx context.Context, phone string) ([]models.PaymentWithProject, error) {
+	query := `
+		SELECT ` + paymentColumns + `, pr.name
+		FROM payments p
+		INNER JOIN labours l ON p.labour_id = l.id
+		INNER JOIN projects pr ON p.project_id = pr.id
+		WHERE l.phone = $1 AND l.deleted_at IS NULL AND pr.deleted_at IS NULL
+		ORDER BY p.payment_date DESC
+	`
+
+	rows, err := conn(ctx, r.db).Query(ctx, query, phone)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var payments []models.PaymentWithProject
+	for rows.Next() {
+		var p models.PaymentWithProject
+		err := rows.Scan(append(paymentFields(&p.Payment), &p.ProjectName)...)
+		if err != nil {
+			return nil, err
+		}
+		payments = append(payments, p)
+	}
+
+	return payments, rows.Err()
+}
+
+// GetBalancesByLabourPhone calculates per-project balances for every labour
+// record with the given phone
+func (r *PaymentRepository) GetBalancesByLabourPhone(ctx context.Context, phone string) ([]models.ProjectBalance, error) {
+	query := `
+		WITH earned AS (
+			SELECT e.project_id, e.labour_id, SUM(e.amount) AS amount, SUM(e.overtime_amount) AS overtime
+			FROM work_day_earnings e
+			INNER JOIN labours l ON e.labour_id = l.id
+			WHERE l.phone = $1
+			GROUP BY e.project_id, e.labour_id
+		), paid AS (
+			SELECT p.project_id, p.labour_id, SUM(` + signedAmount + `) AS amount
+			FROM payments p
+			INNER JOIN labours l ON p.labour_id = l.id
+			WHERE l.phone = $1 AND p.voided_at IS NULL
+			GROUP BY p.project_id, p.labour_id
+		)
+		SELECT pl.project_id, pr.name, pl.labour_id,
+			COALESCE(earned.amount, 0), COALESCE(earned.overtime, 0), COALESCE(paid.amount, 0)
+		FROM project_labours pl
+		INNER JOIN labours l ON pl.labour_id = l.id
+		INNER JOIN projects pr ON pl.project_id = pr.id
+		LEFT JOIN earned ON earned.project_id = pl.project_id AND earned.labour_id = pl.labour_id
+		LEFT JOIN paid ON paid.project_id = pl.project_id AND paid.labour_id = pl.labour_id
+		WHERE l.phone = $1 AND l.deleted_at IS NULL AND pr.deleted_at IS NULL
+		ORDER BY pr.name ASC
+	`
+
+	rows, err := conn(ctx, r.db).Query(ctx, query, phone)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var balances []models.ProjectBalance
+	for rows.Next() {
+		var b models.ProjectBalance
+		err := rows.Scan(&b.ProjectID, &b.ProjectName, &b.LabourID,
+			&b.TotalEarned, &b.OvertimeEarned, &b.TotalPaid)
+		if err != nil {
+			return nil, err
+		}
+		b.Balance = b.TotalEarned.Sub(b.TotalPaid)
+		balances = append(balances, b)
+	}
+
+	return balances, rows.Err()
+}
+
+// GetAdvances retrieves a labour's advances on the given projects with what
+// has been recovered from each, oldest first
+func (r *PaymentRepository) GetAdvances(ctx context.Context, labourID uuid.UUID, projectIDs []uuid.UUID) ([]models.Advance, error) {
+	query := `
+		SELECT a.id, a.project_id, a.labour_id, a.amount, a.payment_date, a.notes, a.created_at,
+			a.recovery_type, a.recovery_value, p.name, a.recovered, a.outstanding
+		FROM advance_balances a
+		INNER JOIN projects p ON p.id = a.project_id
+		WHERE a.labour_id = $1 AND a.project_id = ANY($2)
+		ORDER BY a.payment_date ASC, a.created_at ASC
+	`
+
+	rows, err := conn(ctx, r.db).Query(ctx, query, labourID, projectIDs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var advances []models.Advance
+	for rows.Next() {
+		a := models.Advance{Payment: models.Payment{PaymentType: models.PaymentTypeAdvance}}
+		err := rows.Scan(&a.ID, &a.ProjectID, &a.LabourID, &a.Amount, &a.PaymentDate, &a.Notes, &a.CreatedAt,
+			&a.RecoveryType, &a.RecoveryValue, &a.ProjectName, &a.Recovered, &a.Outstanding)
+		if err != nil {
+			return nil, err
+		}
+		advances = append(advances, a)
+	}
+
+	return advances, rows.Err()
+}
+
+// UpdateRecovery sets or clears the recovery plan of an advance
+func (r *PaymentRepository) UpdateRecovery(ctx context.Context, payment *models.Payment) error {
+	query := `
+		UPDATE payments
+		SET recovery_type = $2, recovery_value = $3
+		WHERE id = $1 AND payment_type = 'advance' AND voided_at IS NULL
+	`
 
-	result, err := r.db.Exec(ctx, query, id)
+	result, err := conn(ctx, r.db).Exec(ctx, query, payment.ID, payment.RecoveryType, payment.RecoveryValue)
 	if err != nil {
 		return err
 	}
@@ -194,3 +483,58 @@ func (r *PaymentRepository) Delete(ctx context.Context, id uuid.UUID) error {
 
 	return nil
 }
+
+// GetRecovered returns how much payroll has recovered from an advance
+func (r *PaymentRepository) GetRecovered(ctx context.Context, advanceID uuid.UUID) (decimal.Decimal, error) {
+	query := `SELECT COALESCE(SUM(amount), 0) FROM advance_recoveries WHERE advance_id = $1`
+
+	var recovered decimal.Decimal
+	if err := conn(ctx, r.db).QueryRow(ctx, query, advanceID).Scan(&recovered); err != nil {
+		return decimal.Zero, err
+	}
+
+	return recovered, nil
+}
+
+// Update corrects a payment that has not been voided
+func (r *PaymentRepository) Update(ctx context.Context, payment *models.Payment) error {
+	query := `
+		UPDATE payments
+		SET amount = $2, payment_date = $3, payment_type = $4, notes = $5, recovery_type = $6, recovery_value = $7
+		WHERE id = $1 AND voided_at IS NULL
+		RETURNING updated_at
+	`
+
+	err := conn(ctx, r.db).QueryRow(ctx, query, payment.ID, payment.Amount, payment.PaymentDate, payment.PaymentType,
+		payment.Notes, payment.RecoveryType, payment.RecoveryValue).Scan(&payment.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return models.ErrNotFound
+		}
+		return err
+	}
+
+	return nil
+}
+
+// Void marks a payment as voided. The row is kept for the record but no
+// longer counts towards balances.
+func (r *PaymentRepository) Void(ctx context.Context, payment *models.Payment) error {
+	query := `
+		UPDATE payments
+		SET voided_at = NOW(), voided_by = $2, void_reason = $3
+		WHERE id = $1 AND voided_at IS NULL
+		RETURNING voided_at, updated_at
+	`
+
+	err := conn(ctx, r.db).QueryRow(ctx, query, payment.ID, payment.VoidedBy, payment.VoidReason).
+		Scan(&payment.VoidedAt, &payment.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return models.ErrPaymentVoided
+		}
+		return err
+	}
+
+	return nil
+}
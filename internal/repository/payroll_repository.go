@@ -0,0 +1,558 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/shopspring/decimal"
+	"github.com/vivekanand/labour-thekedar-backend/internal/models"
+)
+
+// PayrollRepository handles payroll run database operations
+type PayrollRepository struct {
+	db *pgxpool.Pool
+}
+
+// NewPayrollRepository creates a new PayrollRepository
+func NewPayrollRepository(db *pgxpool.Pool) *PayrollRepository {
+	return &PayrollRepository{db: db}
+}
+
+// payrollRunColumns selects a run with totals over its items
+const payrollRunColumns = `
+	r.id, r.organisation_id, r.project_id, r.period_start, r.period_end, r.status,
+	r.created_by, r.approved_by, r.approved_at, r.created_at, r.updated_at,
+	COALESCE(t.items, 0), COALESCE(t.earned, 0), COALESCE(t.deduction, 0), COALESCE(t.net, 0)
+`
+
+// payrollRunTotals joins the item totals of run r
+const payrollRunTotals = `
+	LEFT JOIN LATERAL (
+		SELECT COUNT(*) AS items, SUM(earned) AS earned, SUM(advance_deduction) AS deduction, SUM(net_amount) AS net
+		FROM payroll_items
+		WHERE payroll_run_id = r.id
+	) t ON TRUE
+`
+
+// Create drafts a payroll run and computes an item for every labour with
+// earnings in the period. The advance deduction is the next instalment of
+// every outstanding advance on the project, capped at the period's earnings.
+// Completed and archived projects are left out. A project closed on or after
+// the period start returns models.ErrPeriodLocked.
+func (r *PayrollRepository) Create(ctx context.Context, run *models.PayrollRun) error {
+	tx, err := conn(ctx, r.db).Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	locked, err := hasApprovedOverlap(ctx, tx, run.OrganisationID, run.ProjectID, run.PeriodStart, run.PeriodEnd)
+	if err != nil {
+		return err
+	}
+	if locked {
+		return models.ErrPayrollOverlap
+	}
+
+	query := `
+		INSERT INTO payroll_runs (organisation_id, project_id, period_start, period_end, created_by)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, status, created_at, updated_at
+	`
+	err = tx.QueryRow(ctx, query, run.OrganisationID, run.ProjectID, run.PeriodStart, run.PeriodEnd, run.CreatedBy).
+		Scan(&run.ID, &run.Status, &run.CreatedAt, &run.UpdatedAt)
+	if err != nil {
+		return err
+	}
+
+	itemsQuery := `
+		INSERT INTO payroll_items (payroll_run_id, project_id, labour_id, days_worked, earned, advance_deduction, net_amount)
+		SELECT $1, s.project_id, s.labour_id, s.days, s.earned, d.deduction, s.earned - d.deduction
+		FROM (
+			SELECT e.project_id, e.labour_id, SUM(e.day_fraction) AS days, SUM(e.amount) AS earned
+			FROM work_day_earnings e
+			INNER JOIN projects p ON p.id = e.project_id
+			INNER JOIN labours l ON l.id = e.labour_id
+			WHERE p.organisation_id = $2 AND ($3::uuid IS NULL OR p.id = $3)
+				AND p.deleted_at IS NULL AND l.deleted_at IS NULL
+				AND p.status NOT IN ('completed', 'archived')
+				AND e.work_date BETWEEN $4 AND $5
+			GROUP BY e.project_id, e.labour_id
+			HAVING SUM(e.amount) > 0
+		) s
+		LEFT JOIN LATERAL (
+			SELECT COALESCE(SUM(LEAST(outstanding, CASE recovery_type
+				WHEN 'fixed' THEN recovery_value
+				WHEN 'percentage' THEN ROUND(amount * recovery_value / 100, 2)
+				ELSE outstanding
+			END)), 0) AS amount
+			FROM advance_balances
+			WHERE project_id = s.project_id AND labour_id = s.labour_id
+				AND payment_date <= $5 AND outstanding > 0
+		) planned ON TRUE
+		CROSS JOIN LATERAL (
+			SELECT LEAST(s.earned, planned.amount) AS deduction
+		) d
+	`
+	_, err = tx.Exec(ctx, itemsQuery, run.ID, run.OrganisationID, run.ProjectID, run.PeriodStart, run.PeriodEnd)
+	if err != nil {
+		return err
+	}
+
+	closed, err := coversClosedPeriod(ctx, tx, run.ID, run.PeriodStart, run.PeriodStart)
+	if err != nil {
+		return err
+	}
+	if closed {
+		return models.ErrPeriodLocked
+	}
+
+	return tx.Commit(ctx)
+}
+
+// GetByID retrieves a payroll run with its totals
+func (r *PayrollRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.PayrollRun, error) {
+	query := `SELECT ` + payrollRunColumns + ` FROM payroll_runs r ` + payrollRunTotals + ` WHERE r.id = $1`
+
+	run, err := scanPayrollRun(conn(ctx, r.db).QueryRow(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, models.ErrNotFound
+		}
+		return nil, err
+	}
+
+	return run, nil
+}
+
+// GetByOrganisationID retrieves every payroll run in an organisation
+func (r *PayrollRepository) GetByOrganisationID(ctx context.Context, orgID uuid.UUID) ([]models.PayrollRun, error) {
+	query := `SELECT ` + payrollRunColumns + ` FROM payroll_runs r ` + payrollRunTotals + `
+		WHERE r.organisation_id = $1
+		ORDER BY r.period_end DESC, r.created_at DESC`
+
+	return r.queryRuns(ctx, query, orgID)
+}
+
+// GetByProjectID retrieves the payroll runs for a single project
+func (r *PayrollRepository) GetByProjectID(ctx context.Context, projectID uuid.UUID) ([]models.PayrollRun, error) {
+	query := `SELECT ` + payrollRunColumns + ` FROM payroll_runs r ` + payrollRunTotals + `
+		WHERE r.project_id = $1
+		ORDER BY r.period_end DESC, r.created_at DESC`
+
+	return r.queryRuns(ctx, query, projectID)
+}
+
+func (r *PayrollRepository) queryRuns(ctx context.Context, query string, args ...any) ([]models.PayrollRun, error) {
+	rows, err := conn(ctx, r.db).Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var runs []models.PayrollRun
+	for rows.Next() {
+		run, err := scanPayrollRun(rows)
+		if err != nil {
+			return nil, err
+		}
+		runs = append(runs, *run)
+	}
+
+	return runs, rows.Err()
+}
+
+func scanPayrollRun(row pgx.Row) (*models.PayrollRun, error) {
+	run := &models.PayrollRun{}
+	err := row.Scan(&run.ID, &run.OrganisationID, &run.ProjectID, &run.PeriodStart, &run.PeriodEnd, &run.Status,
+		&run.CreatedBy, &run.ApprovedBy, &run.ApprovedAt, &run.CreatedAt, &run.UpdatedAt,
+		&run.ItemCount, &run.TotalEarned, &run.TotalAdvanceDeduction, &run.TotalNet)
+	if err != nil {
+		return nil, err
+	}
+	return run, nil
+}
+
+// GetItems retrieves the items of a payroll run
+func (r *PayrollRepository) GetItems(ctx context.Context, runID uuid.UUID) ([]models.PayrollItem, error) {
+	query := `
+		SELECT i.id, i.payroll_run_id, i.project_id, p.name, i.labour_id, l.name, i.days_worked, i.earned,
+			i.advance_deduction, i.adjustment, i.net_amount, i.notes, i.payment_id
+		FROM payroll_items i
+		INNER JOIN projects p ON p.id = i.project_id
+		INNER JOIN labours l ON l.id = i.labour_id
+		WHERE i.payroll_run_id = $1
+		ORDER BY p.name ASC, l.name ASC
+	`
+
+	rows, err := conn(ctx, r.db).Query(ctx, query, runID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items []models.PayrollItem
+	for rows.Next() {
+		var i models.PayrollItem
+		err := rows.Scan(&i.ID, &i.PayrollRunID, &i.ProjectID, &i.ProjectName, &i.LabourID, &i.LabourName,
+			&i.DaysWorked, &i.Earned, &i.AdvanceDeduction, &i.Adjustment, &i.NetAmount, &i.Notes, &i.PaymentID)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+
+	return items, rows.Err()
+}
+
+// GetItem retrieves a single item of a payroll run
+func (r *PayrollRepository) GetItem(ctx context.Context, runID, itemID uuid.UUID) (*models.PayrollItem, error) {
+	query := `
+		SELECT i.id, i.payroll_run_id, i.project_id, p.name, i.labour_id, l.name, i.days_worked, i.earned,
+			i.advance_deduction, i.adjustment, i.net_amount, i.notes, i.payment_id
+		FROM payroll_items i
+		INNER JOIN projects p ON p.id = i.project_id
+		INNER JOIN labours l ON l.id = i.labour_id
+		WHERE i.payroll_run_id = $1 AND i.id = $2
+	`
+
+	i := &models.PayrollItem{}
+	err := conn(ctx, r.db).QueryRow(ctx, query, runID, itemID).
+		Scan(&i.ID, &i.PayrollRunID, &i.ProjectID, &i.ProjectName, &i.LabourID, &i.LabourName,
+			&i.DaysWorked, &i.Earned, &i.AdvanceDeduction, &i.Adjustment, &i.NetAmount, &i.Notes, &i.PaymentID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, models.ErrNotFound
+		}
+		return nil, err
+	}
+
+	return i, nil
+}
+
+// GetAdvanceOutstanding sums what the item's labour still owes on advances
+// on its project paid by the end of the run's period, the advances its
+// deduction is recovered from on approval
+func (r *PayrollRepository) GetAdvanceOutstanding(ctx context.Context, item *models.PayrollItem) (decimal.Decimal, error) {
+	query := `
+		SELECT COALESCE(SUM(a.outstanding), 0)
+		FROM advance_balances a
+		INNER JOIN payroll_runs r ON r.id = $1
+		WHERE a.project_id = $2 AND a.labour_id = $3
+			AND a.payment_date <= r.period_end AND a.outstanding > 0
+	`
+
+	var outstanding decimal.Decimal
+	err := conn(ctx, r.db).QueryRow(ctx, query, item.PayrollRunID, item.ProjectID, item.LabourID).Scan(&outstanding)
+	if err != nil {
+		return decimal.Zero, err
+	}
+
+	return outstanding, nil
+}
+
+// UpdateItem saves the editable amounts of an item while its run is a draft
+func (r *PayrollRepository) UpdateItem(ctx context.Context, item *models.PayrollItem) error {
+	query := `
+		UPDATE payroll_items
+		SET advance_deduction = $3, adjustment = $4, net_amount = $5, notes = $6
+		WHERE payroll_run_id = $1 AND id = $2
+			AND payroll_run_id IN (SELECT id FROM payroll_runs WHERE status = 'draft')
+	`
+
+	result, err := conn(ctx, r.db).Exec(ctx, query, item.PayrollRunID, item.ID,
+		item.AdvanceDeduction, item.Adjustment, item.NetAmount, item.Notes)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return models.ErrPayrollApproved
+	}
+
+	return nil
+}
+
+// DeleteItem removes a labour from a draft payroll run
+func (r *PayrollRepository) DeleteItem(ctx context.Context, runID, itemID uuid.UUID) error {
+	query := `
+		DELETE FROM payroll_items
+		WHERE payroll_run_id = $1 AND id = $2
+			AND payroll_run_id IN (SELECT id FROM payroll_runs WHERE status = 'draft')
+	`
+
+	result, err := conn(ctx, r.db).Exec(ctx, query, runID, itemID)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return models.ErrNotFound
+	}
+
+	return nil
+}
+
+// Delete deletes a draft payroll run
+func (r *PayrollRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	query := `DELETE FROM payroll_runs WHERE id = $1 AND status = 'draft'`
+
+	result, err := conn(ctx, r.db).Exec(ctx, query, id)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return models.ErrPayrollApproved
+	}
+
+	return nil
+}
+
+// Approve approves a draft payroll run in one transaction: a daily_wage
+// payment is created for every item with a net amount, each item's advance
+// deduction is recovered from the labour's oldest advances first, and the
+// closed-through date of every project the run covers advances to the end of
+// its period. A project closed on or after the period start or the payment
+// date returns models.ErrPeriodLocked, and a completed or archived one
+// models.ErrProjectInactive. The payments and recoveries created are
+// returned.
+func (r *PayrollRepository) Approve(ctx context.Context, id, approvedBy uuid.UUID, paymentDate time.Time) ([]models.Payment, []models.AdvanceRecovery, error) {
+	tx, err := conn(ctx, r.db).Begin(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	var run models.PayrollRun
+	query := `
+		SELECT organisation_id, project_id, period_start, period_end, status
+		FROM payroll_runs
+		WHERE id = $1
+		FOR UPDATE
+	`
+	err = tx.QueryRow(ctx, query, id).
+		Scan(&run.OrganisationID, &run.ProjectID, &run.PeriodStart, &run.PeriodEnd, &run.Status)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil, models.ErrNotFound
+		}
+		return nil, nil, err
+	}
+	if run.Status != models.PayrollStatusDraft {
+		return nil, nil, models.ErrPayrollApproved
+	}
+
+	// Serialise approvals in the organisation so overlapping runs can't both pass
+	_, err = tx.Exec(ctx, `SELECT 1 FROM organisations WHERE id = $1 FOR UPDATE`, run.OrganisationID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	locked, err := hasApprovedOverlap(ctx, tx, run.OrganisationID, run.ProjectID, run.PeriodStart, run.PeriodEnd)
+	if err != nil {
+		return nil, nil, err
+	}
+	if locked {
+		return nil, nil, models.ErrPayrollOverlap
+	}
+
+	// Payments may not land in a period closed by hand since the run was drafted
+	closed, err := coversClosedPeriod(ctx, tx, id, run.PeriodStart, paymentDate)
+	if err != nil {
+		return nil, nil, err
+	}
+	if closed {
+		return nil, nil, models.ErrPeriodLocked
+	}
+
+	// Nor may they be paid on a project completed or archived since
+	inactive, err := coversInactiveProject(ctx, tx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	if inactive {
+		return nil, nil, models.ErrProjectInactive
+	}
+
+	paymentsQuery := `
+		WITH inserted AS (
+			INSERT INTO payments (project_id, labour_id, amount, payment_date, payment_type, notes)
+			SELECT project_id, labour_id, net_amount, $2, 'daily_wage',
+				'Payroll ' || to_char($3::date, 'YYYY-MM-DD') || ' to ' || to_char($4::date, 'YYYY-MM-DD')
+			FROM payroll_items
+			WHERE payroll_run_id = $1 AND net_amount > 0
+			RETURNING *
+		), linked AS (
+			UPDATE payroll_items i
+			SET payment_id = inserted.id
+			FROM inserted
+			WHERE i.payroll_run_id = $1 AND i.project_id = inserted.project_id AND i.labour_id = inserted.labour_id
+		)
+		SELECT ` + paymentColumns + ` FROM inserted p
+	`
+	rows, err := tx.Query(ctx, paymentsQuery, id, paymentDate, run.PeriodStart, run.PeriodEnd)
+	if err != nil {
+		return nil, nil, err
+	}
+	var payments []models.Payment
+	for rows.Next() {
+		var p models.Payment
+		if err := rows.Scan(paymentFields(&p)...); err != nil {
+			rows.Close()
+			return nil, nil, err
+		}
+		payments = append(payments, p)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	recoveriesQuery := `
+		WITH inserted AS (
+			INSERT INTO advance_recoveries (advance_id, payroll_item_id, amount, recovered_on)
+			SELECT a.id, i.id, LEAST(a.outstanding, i.advance_deduction - a.before), $2
+			FROM payroll_items i
+			CROSS JOIN LATERAL (
+				SELECT id, outstanding,
+					COALESCE(SUM(outstanding) OVER (ORDER BY payment_date, created_at, id
+						ROWS BETWEEN UNBOUNDED PRECEDING AND 1 PRECEDING), 0) AS before
+				FROM advance_balances
+				WHERE project_id = i.project_id AND labour_id = i.labour_id
+					AND payment_date <= $3 AND outstanding > 0
+			) a
+			WHERE i.payroll_run_id = $1 AND i.advance_deduction > a.before
+			RETURNING id, advance_id, payroll_item_id, amount, recovered_on, created_at
+		)
+		SELECT r.id, r.advance_id, r.payroll_item_id, i.project_id, i.labour_id, r.amount, r.recovered_on, r.created_at
+		FROM inserted r
+		INNER JOIN payroll_items i ON i.id = r.payroll_item_id
+	`
+	rows, err = tx.Query(ctx, recoveriesQuery, id, paymentDate, run.PeriodEnd)
+	if err != nil {
+		return nil, nil, err
+	}
+	var recoveries []models.AdvanceRecovery
+	for rows.Next() {
+		var rec models.AdvanceRecovery
+		err := rows.Scan(&rec.ID, &rec.AdvanceID, &rec.PayrollItemID, &rec.ProjectID, &rec.LabourID,
+			&rec.Amount, &rec.RecoveredOn, &rec.CreatedAt)
+		if err != nil {
+			rows.Close()
+			return nil, nil, err
+		}
+		recoveries = append(recoveries, rec)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	approveQuery := `
+		UPDATE payroll_runs
+		SET status = 'approved', approved_by = $2, approved_at = NOW()
+		WHERE id = $1
+	`
+	if _, err := tx.Exec(ctx, approveQuery, id, approvedBy); err != nil {
+		return nil, nil, err
+	}
+
+	closeQuery := `
+		WITH closed AS (
+			UPDATE projects p
+			SET closed_through = $4
+			FROM (
+				SELECT id, closed_through AS previous
+				FROM projects
+				WHERE organisation_id = $2 AND ($3::uuid IS NULL OR id = $3)
+					AND (closed_through IS NULL OR closed_through < $4)
+			) old
+			WHERE p.id = old.id
+			RETURNING p.id, old.previous
+		)
+		INSERT INTO project_period_events (project_id, action, previous_closed_through, closed_through, reason, user_id)
+		SELECT id, 'close', previous, $4, 'Payroll approved', $1
+		FROM closed
+	`
+	if _, err := tx.Exec(ctx, closeQuery, approvedBy, run.OrganisationID, run.ProjectID, run.PeriodEnd); err != nil {
+		return nil, nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, nil, err
+	}
+
+	return payments, recoveries, nil
+}
+
+// hasApprovedOverlap checks if an approved run overlapping the period
+// covers any of the same projects
+func hasApprovedOverlap(ctx context.Context, q querier, orgID uuid.UUID, projectID *uuid.UUID, start, end time.Time) (bool, error) {
+	query := `
+		SELECT EXISTS(
+			SELECT 1
+			FROM payroll_runs
+			WHERE organisation_id = $1 AND status = 'approved'
+				AND ($2::uuid IS NULL OR project_id IS NULL OR project_id = $2)
+				AND period_start <= $4 AND period_end >= $3
+		)
+	`
+
+	var exists bool
+	if err := q.QueryRow(ctx, query, orgID, projectID, start, end).Scan(&exists); err != nil {
+		return false, err
+	}
+
+	return exists, nil
+}
+
+// coversClosedPeriod checks if any project with an item in the run is closed
+// on or after either date. The projects are locked first so their periods
+// can't be closed before the transaction commits.
+func coversClosedPeriod(ctx context.Context, q querier, runID uuid.UUID, start, paymentDate time.Time) (bool, error) {
+	lockQuery := `
+		SELECT 1 FROM projects
+		WHERE id IN (SELECT project_id FROM payroll_items WHERE payroll_run_id = $1)
+		FOR UPDATE
+	`
+	if _, err := q.Exec(ctx, lockQuery, runID); err != nil {
+		return false, err
+	}
+
+	query := `
+		SELECT EXISTS(
+			SELECT 1 FROM projects
+			WHERE id IN (SELECT project_id FROM payroll_items WHERE payroll_run_id = $1)
+				AND closed_through >= LEAST($2::date, $3::date)
+		)
+	`
+
+	var closed bool
+	if err := q.QueryRow(ctx, query, runID, start, paymentDate).Scan(&closed); err != nil {
+		return false, err
+	}
+
+	return closed, nil
+}
+
+// coversInactiveProject checks if any project with an item in the run is
+// completed or archived. Call it after coversClosedPeriod has locked them.
+func coversInactiveProject(ctx context.Context, q querier, runID uuid.UUID) (bool, error) {
+	query := `
+		SELECT EXISTS(
+			SELECT 1 FROM projects
+			WHERE id IN (SELECT project_id FROM payroll_items WHERE payroll_run_id = $1)
+				AND status IN ('completed', 'archived')
+		)
+	`
+
+	var inactive bool
+	if err := q.QueryRow(ctx, query, runID).Scan(&inactive); err != nil {
+		return false, err
+	}
+
+	return inactive, nil
+}
@@ -10,6 +10,19 @@ import (
 	"github.com/vivekanand/labour-thekedar-backend/internal/models"
 )
 
+// projectColumns selects every column of a project, in the order of projectFields
+const projectColumns = `
+	id, user_id, organisation_id, name, description, status, start_date, end_date, site_address, client_name,
+	labour_budget, overtime_rate, closed_through, created_at, updated_at, deleted_at, deleted_by
+`
+
+// projectFields returns the scan destinations for projectColumns
+func projectFields(p *models.Project) []any {
+	return []any{&p.ID, &p.UserID, &p.OrganisationID, &p.Name, &p.Description, &p.Status, &p.StartDate, &p.EndDate,
+		&p.SiteAddress, &p.ClientName, &p.LabourBudget, &p.OvertimeRate, &p.ClosedThrough, &p.CreatedAt, &p.UpdatedAt,
+		&p.DeletedAt, &p.DeletedBy}
+}
+
 // ProjectRepository handles project database operations
 type ProjectRepository struct {
 	db *pgxpool.Pool
@@ -23,12 +36,15 @@ func NewProjectRepository(db *pgxpool.Pool) *ProjectRepository {
 // Create creates a new project
 func (r *ProjectRepository) Create(ctx context.Context, project *models.Project) error {
 	query := `
-		INSERT INTO projects (user_id, name, description)
-		VALUES ($1, $2, $3)
+		INSERT INTO projects (user_id, organisation_id, name, description, status, start_date, end_date,
+			site_address, client_name, labour_budget, overtime_rate)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
 		RETURNING id, created_at, updated_at
 	`
 
-	err := r.db.QueryRow(ctx, query, project.UserID, project.Name, project.Description).
+	err := conn(ctx, r.db).QueryRow(ctx, query, project.UserID, project.OrganisationID, project.Name, project.Description,
+		project.Status, project.StartDate, project.EndDate, project.SiteAddress, project.ClientName,
+		project.LabourBudget, project.OvertimeRate).
 		Scan(&project.ID, &project.CreatedAt, &project.UpdatedAt)
 	if err != nil {
 		return err
@@ -40,15 +56,14 @@ func (r *ProjectRepository) Create(ctx context.Context, project *models.Project)
 // GetByID retrieves a project by ID
 func (r *ProjectRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Project, error) {
 	query := `
-		SELECT id, user_id, name, description, created_at, updated_at
+		SELECT ` + projectColumns + `
 		FROM projects
-		WHERE id = $1
+		WHERE id = $1 AND deleted_at IS NULL
 	`
 
 	project := &models.Project{}
-	err := r.db.QueryRow(ctx, query, id).
-		Scan(&project.ID, &project.UserID, &project.Name, &project.Description,
-			&project.CreatedAt, &project.UpdatedAt)
+	err := conn(ctx, r.db).QueryRow(ctx, query, id).
+		Scan(projectFields(project)...)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, models.ErrNotFound
@@ -59,16 +74,19 @@ func (r *ProjectRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.
 	return project, nil
 }
 
-// GetByUserID retrieves all projects for a user
-func (r *ProjectRepository) GetByUserID(ctx context.Context, userID uuid.UUID) ([]models.Project, error) {
+// GetByUserID retrieves the projects with the given statuses in the
+// organisations a user belongs to and the projects they are a member of
+func (r *ProjectRepository) GetByUserID(ctx context.Context, userID uuid.UUID, statuses []models.ProjectStatus) ([]models.Project, error) {
 	query := `
-		SELECT id, user_id, name, description, created_at, updated_at
+		SELECT ` + projectColumns + `
 		FROM projects
-		WHERE user_id = $1
+		WHERE deleted_at IS NULL AND status = ANY($2)
+			AND (organisation_id IN (SELECT organisation_id FROM organisation_members WHERE user_id = $1)
+				OR id IN (SELECT project_id FROM project_members WHERE user_id = $1))
 		ORDER BY created_at DESC
 	`
 
-	rows, err := r.db.Query(ctx, query, userID)
+	rows, err := conn(ctx, r.db).Query(ctx, query, userID, projectStatusesToStrings(statuses))
 	if err != nil {
 		return nil, err
 	}
@@ -77,8 +95,7 @@ func (r *ProjectRepository) GetByUserID(ctx context.Context, userID uuid.UUID) (
 	var projects []models.Project
 	for rows.Next() {
 		var p models.Project
-		err := rows.Scan(&p.ID, &p.UserID, &p.Name, &p.Description,
-			&p.CreatedAt, &p.UpdatedAt)
+		err := rows.Scan(projectFields(&p)...)
 		if err != nil {
 			return nil, err
 		}
@@ -92,12 +109,15 @@ func (r *ProjectRepository) GetByUserID(ctx context.Context, userID uuid.UUID) (
 func (r *ProjectRepository) Update(ctx context.Context, project *models.Project) error {
 	query := `
 		UPDATE projects
-		SET name = $2, description = $3, updated_at = NOW()
-		WHERE id = $1
+		SET name = $2, description = $3, status = $4, start_date = $5, end_date = $6, site_address = $7,
+			client_name = $8, labour_budget = $9, overtime_rate = $10, updated_at = NOW()
+		WHERE id = $1 AND deleted_at IS NULL
 		RETURNING updated_at
 	`
 
-	err := r.db.QueryRow(ctx, query, project.ID, project.Name, project.Description).
+	err := conn(ctx, r.db).QueryRow(ctx, query, project.ID, project.Name, project.Description, project.Status,
+		project.StartDate, project.EndDate, project.SiteAddress, project.ClientName, project.LabourBudget,
+		project.OvertimeRate).
 		Scan(&project.UpdatedAt)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
@@ -109,11 +129,222 @@ func (r *ProjectRepository) Update(ctx context.Context, project *models.Project)
 	return nil
 }
 
-// Delete deletes a project
-func (r *ProjectRepository) Delete(ctx context.Context, id uuid.UUID) error {
-	query := `DELETE FROM projects WHERE id = $1`
+// Delete moves a project to the trash, keeping its work days and payments.
+// project.DeletedBy is recorded as the user who deleted it.
+func (r *ProjectRepository) Delete(ctx context.Context, project *models.Project) error {
+	query := `
+		UPDATE projects
+		SET deleted_at = NOW(), deleted_by = $2
+		WHERE id = $1 AND deleted_at IS NULL
+		RETURNING deleted_at
+	`
+
+	err := conn(ctx, r.db).QueryRow(ctx, query, project.ID, project.DeletedBy).Scan(&project.DeletedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return models.ErrNotFound
+		}
+		return err
+	}
+
+	return nil
+}
+
+// GetDeletedByID retrieves a project in the trash by ID
+func (r *ProjectRepository) GetDeletedByID(ctx context.Context, id uuid.UUID) (*models.Project, error) {
+	query := `
+		SELECT ` + projectColumns + `
+		FROM projects
+		WHERE id = $1 AND deleted_at IS NOT NULL
+	`
+
+	project := &models.Project{}
+	err := conn(ctx, r.db).QueryRow(ctx, query, id).
+		Scan(projectFields(project)...)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, models.ErrNotFound
+		}
+		return nil, err
+	}
+
+	return project, nil
+}
+
+// GetDeletedByUserID retrieves the trashed projects in the organisations a
+// user belongs to, most recently deleted first
+func (r *ProjectRepository) GetDeletedByUserID(ctx context.Context, userID uuid.UUID) ([]models.Project, error) {
+	query := `
+		SELECT ` + projectColumns + `
+		FROM projects
+		WHERE deleted_at IS NOT NULL
+			AND organisation_id IN (SELECT organisation_id FROM organisation_members WHERE user_id = $1)
+		ORDER BY deleted_at DESC
+	`
+
+	rows, err := conn(ctx, r.db).Query(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var projects []models.Project
+	for rows.Next() {
+		var p models.Project
+		err := rows.Scan(projectFields(&p)...)
+		if err != nil {
+			return nil, err
+		}
+		projects = append(projects, p)
+	}
+
+	return projects, rows.Err()
+}
+
+// Restore takes a project out of the trash
+func (r *ProjectRepository) Restore(ctx context.Context, project *models.Project) error {
+	query := `
+		UPDATE projects
+		SET deleted_at = NULL, deleted_by = NULL, updated_at = NOW()
+		WHERE id = $1 AND deleted_at IS NOT NULL
+		RETURNING updated_at
+	`
+
+	err := conn(ctx, r.db).QueryRow(ctx, query, project.ID).Scan(&project.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return models.ErrNotFound
+		}
+		return err
+	}
+
+	project.DeletedAt = nil
+	project.DeletedBy = nil
+	return nil
+}
+
+// Purge permanently deletes a trashed project along with its work days,
+// payments and payroll items. Recoveries against its advances go first, as
+// they would otherwise block the cascade. A project with items in approved
+// payroll runs or with sent invoices returns models.ErrProjectSettled.
+func (r *ProjectRepository) Purge(ctx context.Context, id uuid.UUID) error {
+	tx, err := conn(ctx, r.db).Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	// Lock the project so nothing is approved or sent while it is checked
+	if _, err := tx.Exec(ctx, `SELECT 1 FROM projects WHERE id = $1 FOR UPDATE`, id); err != nil {
+		return err
+	}
+
+	blockersQuery := `
+		SELECT
+			(SELECT COUNT(*) FROM payroll_items i
+				INNER JOIN payroll_runs r ON r.id = i.payroll_run_id
+				WHERE i.project_id = $1 AND r.status = 'approved'),
+			(SELECT COUNT(*) FROM invoices WHERE project_id = $1 AND status <> 'draft')
+	`
+	var blockers models.PurgeBlockers
+	err = tx.QueryRow(ctx, blockersQuery, id).Scan(&blockers.ApprovedPayrollItems, &blockers.IssuedInvoices)
+	if err != nil {
+		return err
+	}
+	if err := blockers.Check(); err != nil {
+		return err
+	}
+
+	recoveriesQuery := `
+		DELETE FROM advance_recoveries
+		WHERE advance_id IN (
+			SELECT p.id FROM payments p
+			INNER JOIN projects pr ON pr.id = p.project_id
+			WHERE pr.id = $1 AND pr.deleted_at IS NOT NULL
+		)
+	`
+	if _, err := tx.Exec(ctx, recoveriesQuery, id); err != nil {
+		return err
+	}
+
+	result, err := tx.Exec(ctx, `DELETE FROM projects WHERE id = $1 AND deleted_at IS NOT NULL`, id)
+	if err != nil {
+		return err
+	}
+	if result.RowsAffected() == 0 {
+		return models.ErrNotFound
+	}
+
+	return tx.Commit(ctx)
+}
+
+// UpsertMember adds a project member or replaces their capabilities
+func (r *ProjectRepository) UpsertMember(ctx context.Context, member *models.ProjectMember) error {
+	query := `
+		INSERT INTO project_members (project_id, user_id, capabilities)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (project_id, user_id) DO UPDATE
+		SET capabilities = EXCLUDED.capabilities, updated_at = NOW()
+		RETURNING created_at, updated_at
+	`
+
+	return conn(ctx, r.db).QueryRow(ctx, query, member.ProjectID, member.UserID, permissionsToStrings(member.Capabilities)).
+		Scan(&member.CreatedAt, &member.UpdatedAt)
+}
+
+// GetMember retrieves a user's membership of a project
+func (r *ProjectRepository) GetMember(ctx context.Context, projectID, userID uuid.UUID) (*models.ProjectMember, error) {
+	query := `
+		SELECT pm.project_id, pm.user_id, u.phone, COALESCE(u.name, ''), pm.capabilities, pm.created_at, pm.updated_at
+		FROM project_members pm
+		INNER JOIN users u ON u.id = pm.user_id
+		WHERE pm.project_id = $1 AND pm.user_id = $2
+	`
+
+	member, err := scanProjectMember(conn(ctx, r.db).QueryRow(ctx, query, projectID, userID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, models.ErrNotFound
+		}
+		return nil, err
+	}
+
+	return member, nil
+}
+
+// GetMembers retrieves all members of a project
+func (r *ProjectRepository) GetMembers(ctx context.Context, projectID uuid.UUID) ([]models.ProjectMember, error) {
+	query := `
+		SELECT pm.project_id, pm.user_id, u.phone, COALESCE(u.name, ''), pm.capabilities, pm.created_at, pm.updated_at
+		FROM project_members pm
+		INNER JOIN users u ON u.id = pm.user_id
+		WHERE pm.project_id = $1
+		ORDER BY pm.created_at ASC
+	`
+
+	rows, err := conn(ctx, r.db).Query(ctx, query, projectID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var members []models.ProjectMember
+	for rows.Next() {
+		member, err := scanProjectMember(rows)
+		if err != nil {
+			return nil, err
+		}
+		members = append(members, *member)
+	}
+
+	return members, rows.Err()
+}
+
+// RemoveMember removes a user from a project
+func (r *ProjectRepository) RemoveMember(ctx context.Context, projectID, userID uuid.UUID) error {
+	query := `DELETE FROM project_members WHERE project_id = $1 AND user_id = $2`
 
-	result, err := r.db.Exec(ctx, query, id)
+	result, err := conn(ctx, r.db).Exec(ctx, query, projectID, userID)
 	if err != nil {
 		return err
 	}
@@ -125,15 +356,139 @@ func (r *ProjectRepository) Delete(ctx context.Context, id uuid.UUID) error {
 	return nil
 }
 
-// IsOwner checks if a user owns a project
-func (r *ProjectRepository) IsOwner(ctx context.Context, projectID, userID uuid.UUID) (bool, error) {
-	query := `SELECT EXISTS(SELECT 1 FROM projects WHERE id = $1 AND user_id = $2)`
+func scanProjectMember(row pgx.Row) (*models.ProjectMember, error) {
+	member := &models.ProjectMember{}
+	var capabilities []string
+	err := row.Scan(&member.ProjectID, &member.UserID, &member.Phone, &member.Name,
+		&capabilities, &member.CreatedAt, &member.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	member.Capabilities = make([]models.Permission, len(capabilities))
+	for i, c := range capabilities {
+		member.Capabilities[i] = models.Permission(c)
+	}
+
+	return member, nil
+}
+
+func projectStatusesToStrings(statuses []models.ProjectStatus) []string {
+	out := make([]string, len(statuses))
+	for i, s := range statuses {
+		out[i] = string(s)
+	}
+	return out
+}
+
+func permissionsToStrings(perms []models.Permission) []string {
+	out := make([]string, len(perms))
+	for i, p := range perms {
+		out[i] = string(p)
+	}
+	return out
+}
+
+// GetByLabourID retrieves the projects a labour is assigned to or has work
+// days or payments in
+func (r *ProjectRepository) GetByLabourID(ctx context.Context, labourID uuid.UUID) ([]models.Project, error) {
+	query := `
+		SELECT ` + projectColumns + `
+		FROM projects
+		WHERE id IN (
+			SELECT project_id FROM project_labours WHERE labour_id = $1
+			UNION SELECT project_id FROM work_days WHERE labour_id = $1
+			UNION SELECT project_id FROM payments WHERE labour_id = $1
+		) AND deleted_at IS NULL
+		ORDER BY name ASC
+	`
+
+	rows, err := conn(ctx, r.db).Query(ctx, query, labourID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var projects []models.Project
+	for rows.Next() {
+		var p models.Project
+		err := rows.Scan(projectFields(&p)...)
+		if err != nil {
+			return nil, err
+		}
+		projects = append(projects, p)
+	}
+
+	return projects, rows.Err()
+}
+
+// UpdateClosedThrough moves a project's closed-through date and records the
+// event in one transaction. The event is validated against the current date
+// while the project row is locked.
+func (r *ProjectRepository) UpdateClosedThrough(ctx context.Context, event *models.PeriodEvent) error {
+	tx, err := conn(ctx, r.db).Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	query := `SELECT closed_through FROM projects WHERE id = $1 FOR UPDATE`
+	err = tx.QueryRow(ctx, query, event.ProjectID).Scan(&event.PreviousClosedThrough)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return models.ErrNotFound
+		}
+		return err
+	}
+
+	if err := event.Validate(); err != nil {
+		return err
+	}
+
+	updateQuery := `UPDATE projects SET closed_through = $2 WHERE id = $1`
+	if _, err := tx.Exec(ctx, updateQuery, event.ProjectID, event.ClosedThrough); err != nil {
+		return err
+	}
+
+	eventQuery := `
+		INSERT INTO project_period_events (project_id, action, previous_closed_through, closed_through, reason, user_id)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id, created_at
+	`
+	err = tx.QueryRow(ctx, eventQuery, event.ProjectID, event.Action, event.PreviousClosedThrough,
+		event.ClosedThrough, event.Reason, event.UserID).Scan(&event.ID, &event.CreatedAt)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// GetPeriodEvents retrieves the close and reopen history of a project, newest first
+func (r *ProjectRepository) GetPeriodEvents(ctx context.Context, projectID uuid.UUID) ([]models.PeriodEvent, error) {
+	query := `
+		SELECT id, project_id, action, previous_closed_through, closed_through, reason, user_id, created_at
+		FROM project_period_events
+		WHERE project_id = $1
+		ORDER BY created_at DESC
+	`
 
-	var exists bool
-	err := r.db.QueryRow(ctx, query, projectID, userID).Scan(&exists)
+	rows, err := conn(ctx, r.db).Query(ctx, query, projectID)
 	if err != nil {
-		return false, err
+		return nil, err
+	}
+	defer rows.Close()
+
+	var events []models.PeriodEvent
+	for rows.Next() {
+		var e models.PeriodEvent
+		err := rows.Scan(&e.ID, &e.ProjectID, &e.Action, &e.PreviousClosedThrough, &e.ClosedThrough,
+			&e.Reason, &e.UserID, &e.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, e)
 	}
 
-	return exists, nil
+	return events, rows.Err()
 }
@@ -0,0 +1,63 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// querier is satisfied by both *pgxpool.Pool and pgx.Tx, so helpers can run
+// inside or outside a transaction
+type querier interface {
+	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
+// beginner is a querier that can also start a transaction. Beginning one
+// inside a pgx.Tx creates a savepoint.
+type beginner interface {
+	querier
+	Begin(ctx context.Context) (pgx.Tx, error)
+}
+
+type txKey struct{}
+
+// conn returns the transaction carried by ctx, if any, so that repository
+// calls made within Transactor.InTx share it, and the pool otherwise
+func conn(ctx context.Context, db *pgxpool.Pool) beginner {
+	if tx, ok := ctx.Value(txKey{}).(pgx.Tx); ok {
+		return tx
+	}
+	return db
+}
+
+// Transactor runs several repository calls in one transaction
+type Transactor struct {
+	db *pgxpool.Pool
+}
+
+// NewTransactor creates a new Transactor
+func NewTransactor(db *pgxpool.Pool) *Transactor {
+	return &Transactor{db: db}
+}
+
+// InTx calls fn with a context carrying a transaction, committing it if fn
+// succeeds and rolling it back otherwise. Repository calls made with that
+// context join the transaction, and calls that begin their own transaction
+// run in a savepoint.
+func (t *Transactor) InTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	tx, err := conn(ctx, t.db).Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if err := fn(context.WithValue(ctx, txKey{}, tx)); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
@@ -0,0 +1,158 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/vivekanand/labour-thekedar-backend/internal/models"
+)
+
+// SessionRepository handles session database operations
+type SessionRepository struct {
+	db *pgxpool.Pool
+}
+
+// NewSessionRepository creates a new SessionRepository
+func NewSessionRepository(db *pgxpool.Pool) *SessionRepository {
+	return &SessionRepository{db: db}
+}
+
+// Create creates a new session
+func (r *SessionRepository) Create(ctx context.Context, session *models.Session) error {
+	query := `
+		INSERT INTO sessions (user_id, device_name, ip, user_agent, expires_at)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, last_seen_at, created_at
+	`
+
+	return conn(ctx, r.db).QueryRow(ctx, query, session.UserID, session.DeviceName, session.IP, session.UserAgent, session.ExpiresAt).
+		Scan(&session.ID, &session.LastSeenAt, &session.CreatedAt)
+}
+
+// GetByID retrieves a session by ID
+func (r *SessionRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Session, error) {
+	query := `
+		SELECT id, user_id, device_name, ip, user_agent, expires_at, revoked_at, last_seen_at, created_at
+		FROM sessions
+		WHERE id = $1
+	`
+
+	session := &models.Session{}
+	err := conn(ctx, r.db).QueryRow(ctx, query, id).
+		Scan(&session.ID, &session.UserID, &session.DeviceName, &session.IP, &session.UserAgent,
+			&session.ExpiresAt, &session.RevokedAt, &session.LastSeenAt, &session.CreatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, models.ErrNotFound
+		}
+		return nil, err
+	}
+
+	return session, nil
+}
+
+// GetActiveByUserID retrieves a user's sessions that are neither revoked nor expired
+func (r *SessionRepository) GetActiveByUserID(ctx context.Context, userID uuid.UUID) ([]models.Session, error) {
+	query := `
+		SELECT id, user_id, device_name, ip, user_agent, expires_at, revoked_at, last_seen_at, created_at
+		FROM sessions
+		WHERE user_id = $1 AND revoked_at IS NULL AND expires_at > NOW()
+		ORDER BY last_seen_at DESC
+	`
+
+	rows, err := conn(ctx, r.db).Query(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var sessions []models.Session
+	for rows.Next() {
+		var session models.Session
+		if err := rows.Scan(&session.ID, &session.UserID, &session.DeviceName, &session.IP, &session.UserAgent,
+			&session.ExpiresAt, &session.RevokedAt, &session.LastSeenAt, &session.CreatedAt); err != nil {
+			return nil, err
+		}
+		sessions = append(sessions, session)
+	}
+
+	return sessions, rows.Err()
+}
+
+// Touch reports whether a session is still active and bumps its last seen
+// time. The write is skipped if the session was seen in the last minute so
+// busy clients don't update the row on every request.
+func (r *SessionRepository) Touch(ctx context.Context, id uuid.UUID) (bool, error) {
+	query := `
+		WITH touched AS (
+			UPDATE sessions SET last_seen_at = NOW()
+			WHERE id = $1 AND revoked_at IS NULL AND expires_at > NOW()
+				AND last_seen_at < NOW() - INTERVAL '1 minute'
+		)
+		SELECT revoked_at IS NULL AND expires_at > NOW()
+		FROM sessions
+		WHERE id = $1
+	`
+
+	var active bool
+	err := conn(ctx, r.db).QueryRow(ctx, query, id).Scan(&active)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	return active, nil
+}
+
+// Revoke revokes a session and every refresh token issued to it
+func (r *SessionRepository) Revoke(ctx context.Context, id uuid.UUID) error {
+	tx, err := conn(ctx, r.db).Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, `UPDATE sessions SET revoked_at = NOW() WHERE id = $1 AND revoked_at IS NULL`, id); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(ctx, `UPDATE refresh_tokens SET revoked_at = NOW() WHERE family_id = $1 AND revoked_at IS NULL`, id); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// RevokeAllForUser revokes every session and refresh token of a user
+func (r *SessionRepository) RevokeAllForUser(ctx context.Context, userID uuid.UUID) error {
+	tx, err := conn(ctx, r.db).Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, `UPDATE sessions SET revoked_at = NOW() WHERE user_id = $1 AND revoked_at IS NULL`, userID); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(ctx, `UPDATE refresh_tokens SET revoked_at = NOW() WHERE user_id = $1 AND revoked_at IS NULL`, userID); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// DeleteExpired removes expired sessions along with their refresh tokens
+func (r *SessionRepository) DeleteExpired(ctx context.Context) (int64, error) {
+	query := `DELETE FROM sessions WHERE expires_at < NOW()`
+
+	result, err := conn(ctx, r.db).Exec(ctx, query)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected(), nil
+}
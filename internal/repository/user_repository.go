@@ -28,7 +28,7 @@ func (r *UserRepository) Create(ctx context.Context, user *models.User) error {
 		RETURNING id, created_at, updated_at
 	`
 
-	err := r.db.QueryRow(ctx, query, user.Phone, user.Name).
+	err := conn(ctx, r.db).QueryRow(ctx, query, user.Phone, user.Name).
 		Scan(&user.ID, &user.CreatedAt, &user.UpdatedAt)
 	if err != nil {
 		return err
@@ -46,7 +46,7 @@ func (r *UserRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Use
 	`
 
 	user := &models.User{}
-	err := r.db.QueryRow(ctx, query, id).
+	err := conn(ctx, r.db).QueryRow(ctx, query, id).
 		Scan(&user.ID, &user.Phone, &user.Name, &user.CreatedAt, &user.UpdatedAt)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
@@ -67,7 +67,7 @@ func (r *UserRepository) GetByPhone(ctx context.Context, phone string) (*models.
 	`
 
 	user := &models.User{}
-	err := r.db.QueryRow(ctx, query, phone).
+	err := conn(ctx, r.db).QueryRow(ctx, query, phone).
 		Scan(&user.ID, &user.Phone, &user.Name, &user.CreatedAt, &user.UpdatedAt)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
@@ -88,7 +88,7 @@ func (r *UserRepository) Update(ctx context.Context, user *models.User) error {
 		RETURNING updated_at
 	`
 
-	err := r.db.QueryRow(ctx, query, user.ID, user.Name).Scan(&user.UpdatedAt)
+	err := conn(ctx, r.db).QueryRow(ctx, query, user.ID, user.Name).Scan(&user.UpdatedAt)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return models.ErrNotFound
@@ -7,6 +7,7 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/vivekanand/labour-thekedar-backend/internal/models"
 )
@@ -21,36 +22,93 @@ func NewWorkDayRepository(db *pgxpool.Pool) *WorkDayRepository {
 	return &WorkDayRepository{db: db}
 }
 
+// overtimeRateInForce is the overtime rate a new work day for project $1 and
+// labour $2 is recorded with, so later rate changes leave it unchanged
+const overtimeRateInForce = `(
+	SELECT COALESCE(l.overtime_rate, p.overtime_rate)
+	FROM labours l, projects p
+	WHERE l.id = $2 AND p.id = $1
+)`
+
 // Create creates a new work day record
 func (r *WorkDayRepository) Create(ctx context.Context, workDay *models.WorkDay) error {
 	query := `
-		INSERT INTO work_days (project_id, labour_id, work_date, status, notes)
-		VALUES ($1, $2, $3, $4, $5)
+		INSERT INTO work_days (project_id, labour_id, work_date, status, overtime_hours, check_in, check_out, notes,
+			overtime_rate)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, ` + overtimeRateInForce + `)
 		RETURNING id, created_at
 	`
 
-	err := r.db.QueryRow(ctx, query, workDay.ProjectID, workDay.LabourID,
-		workDay.WorkDate, workDay.Status, workDay.Notes).
+	err := conn(ctx, r.db).QueryRow(ctx, query, workDay.ProjectID, workDay.LabourID,
+		workDay.WorkDate, workDay.Status, workDay.OvertimeHours,
+		workDay.CheckIn, workDay.CheckOut, workDay.Notes).
 		Scan(&workDay.ID, &workDay.CreatedAt)
 	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return models.ErrAlreadyExists
+		}
 		return err
 	}
 
 	return nil
 }
 
+// UpsertMany creates or updates work days in a single transaction, keyed on
+// (project_id, labour_id, work_date). It reports for each work day whether a
+// new row was inserted rather than an existing one updated.
+func (r *WorkDayRepository) UpsertMany(ctx context.Context, workDays []*models.WorkDay) ([]bool, error) {
+	tx, err := conn(ctx, r.db).Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	query := `
+		INSERT INTO work_days (project_id, labour_id, work_date, status, overtime_hours, check_in, check_out, notes,
+			overtime_rate)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, ` + overtimeRateInForce + `)
+		ON CONFLICT (project_id, labour_id, work_date) DO UPDATE
+		SET status = EXCLUDED.status,
+			overtime_hours = EXCLUDED.overtime_hours,
+			check_in = EXCLUDED.check_in,
+			check_out = EXCLUDED.check_out,
+			notes = EXCLUDED.notes
+		RETURNING id, created_at, (xmax = 0) AS inserted
+	`
+
+	inserted := make([]bool, len(workDays))
+	for i, workDay := range workDays {
+		err := tx.QueryRow(ctx, query, workDay.ProjectID, workDay.LabourID,
+			workDay.WorkDate, workDay.Status, workDay.OvertimeHours,
+			workDay.CheckIn, workDay.CheckOut, workDay.Notes).
+			Scan(&workDay.ID, &workDay.CreatedAt, &inserted[i])
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	return inserted, nil
+}
+
 // GetByID retrieves a work day by ID
 func (r *WorkDayRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.WorkDay, error) {
 	query := `
-		SELECT id, project_id, labour_id, work_date, status, notes, created_at
+		SELECT id, project_id, labour_id, work_date, status, overtime_hours,
+			to_char(check_in, 'HH24:MI'), to_char(check_out, 'HH24:MI'), notes, created_at
 		FROM work_days
 		WHERE id = $1
 	`
 
 	workDay := &models.WorkDay{}
-	err := r.db.QueryRow(ctx, query, id).
+	err := conn(ctx, r.db).QueryRow(ctx, query, id).
 		Scan(&workDay.ID, &workDay.ProjectID, &workDay.LabourID,
-			&workDay.WorkDate, &workDay.Status, &workDay.Notes, &workDay.CreatedAt)
+			&workDay.WorkDate, &workDay.Status, &workDay.OvertimeHours,
+			&workDay.CheckIn, &workDay.CheckOut, &workDay.Notes, &workDay.CreatedAt)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, models.ErrNotFound
@@ -64,14 +122,15 @@ func (r *WorkDayRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.
 // GetByProjectID retrieves all work days for a project
 func (r *WorkDayRepository) GetByProjectID(ctx context.Context, projectID uuid.UUID) ([]models.WorkDayWithLabour, error) {
 	query := `
-		SELECT wd.id, wd.project_id, wd.labour_id, wd.work_date, wd.status, wd.notes, wd.created_at, l.name
+		SELECT wd.id, wd.project_id, wd.labour_id, wd.work_date, wd.status, wd.overtime_hours,
+			to_char(wd.check_in, 'HH24:MI'), to_char(wd.check_out, 'HH24:MI'), wd.notes, wd.created_at, l.name
 		FROM work_days wd
 		INNER JOIN labours l ON wd.labour_id = l.id
 		WHERE wd.project_id = $1
 		ORDER BY wd.work_date DESC, l.name ASC
 	`
 
-	rows, err := r.db.Query(ctx, query, projectID)
+	rows, err := conn(ctx, r.db).Query(ctx, query, projectID)
 	if err != nil {
 		return nil, err
 	}
@@ -81,7 +140,8 @@ func (r *WorkDayRepository) GetByProjectID(ctx context.Context, projectID uuid.U
 	for rows.Next() {
 		var wd models.WorkDayWithLabour
 		err := rows.Scan(&wd.ID, &wd.ProjectID, &wd.LabourID,
-			&wd.WorkDate, &wd.Status, &wd.Notes, &wd.CreatedAt, &wd.LabourName)
+			&wd.WorkDate, &wd.Status, &wd.OvertimeHours,
+			&wd.CheckIn, &wd.CheckOut, &wd.Notes, &wd.CreatedAt, &wd.LabourName)
 		if err != nil {
 			return nil, err
 		}
@@ -94,14 +154,15 @@ func (r *WorkDayRepository) GetByProjectID(ctx context.Context, projectID uuid.U
 // GetByProjectAndDate retrieves work days for a project on a specific date
 func (r *WorkDayRepository) GetByProjectAndDate(ctx context.Context, projectID uuid.UUID, date time.Time) ([]models.WorkDayWithLabour, error) {
 	query := `
-		SELECT wd.id, wd.project_id, wd.labour_id, wd.work_date, wd.status, wd.notes, wd.created_at, l.name
+		SELECT wd.id, wd.project_id, wd.labour_id, wd.work_date, wd.status, wd.overtime_hours,
+			to_char(wd.check_in, 'HH24:MI'), to_char(wd.check_out, 'HH24:MI'), wd.notes, wd.created_at, l.name
 		FROM work_days wd
 		INNER JOIN labours l ON wd.labour_id = l.id
 		WHERE wd.project_id = $1 AND wd.work_date = $2
 		ORDER BY l.name ASC
 	`
 
-	rows, err := r.db.Query(ctx, query, projectID, date)
+	rows, err := conn(ctx, r.db).Query(ctx, query, projectID, date)
 	if err != nil {
 		return nil, err
 	}
@@ -111,7 +172,8 @@ func (r *WorkDayRepository) GetByProjectAndDate(ctx context.Context, projectID u
 	for rows.Next() {
 		var wd models.WorkDayWithLabour
 		err := rows.Scan(&wd.ID, &wd.ProjectID, &wd.LabourID,
-			&wd.WorkDate, &wd.Status, &wd.Notes, &wd.CreatedAt, &wd.LabourName)
+			&wd.WorkDate, &wd.Status, &wd.OvertimeHours,
+			&wd.CheckIn, &wd.CheckOut, &wd.Notes, &wd.CreatedAt, &wd.LabourName)
 		if err != nil {
 			return nil, err
 		}
@@ -124,13 +186,14 @@ func (r *WorkDayRepository) GetByProjectAndDate(ctx context.Context, projectID u
 // GetByLabourID retrieves all work days for a labour
 func (r *WorkDayRepository) GetByLabourID(ctx context.Context, labourID uuid.UUID) ([]models.WorkDay, error) {
 	query := `
-		SELECT id, project_id, labour_id, work_date, status, notes, created_at
+		SELECT id, project_id, labour_id, work_date, status, overtime_hours,
+			to_char(check_in, 'HH24:MI'), to_char(check_out, 'HH24:MI'), notes, created_at
 		FROM work_days
 		WHERE labour_id = $1
 		ORDER BY work_date DESC
 	`
 
-	rows, err := r.db.Query(ctx, query, labourID)
+	rows, err := conn(ctx, r.db).Query(ctx, query, labourID)
 	if err != nil {
 		return nil, err
 	}
@@ -140,7 +203,8 @@ func (r *WorkDayRepository) GetByLabourID(ctx context.Context, labourID uuid.UUI
 	for rows.Next() {
 		var wd models.WorkDay
 		err := rows.Scan(&wd.ID, &wd.ProjectID, &wd.LabourID,
-			&wd.WorkDate, &wd.Status, &wd.Notes, &wd.CreatedAt)
+			&wd.WorkDate, &wd.Status, &wd.OvertimeHours,
+			&wd.CheckIn, &wd.CheckOut, &wd.Notes, &wd.CreatedAt)
 		if err != nil {
 			return nil, err
 		}
@@ -154,11 +218,12 @@ func (r *WorkDayRepository) GetByLabourID(ctx context.Context, labourID uuid.UUI
 func (r *WorkDayRepository) Update(ctx context.Context, workDay *models.WorkDay) error {
 	query := `
 		UPDATE work_days
-		SET status = $2, notes = $3
+		SET status = $2, overtime_hours = $3, check_in = $4, check_out = $5, notes = $6
 		WHERE id = $1
 	`
 
-	result, err := r.db.Exec(ctx, query, workDay.ID, workDay.Status, workDay.Notes)
+	result, err := conn(ctx, r.db).Exec(ctx, query, workDay.ID, workDay.Status, workDay.OvertimeHours,
+		workDay.CheckIn, workDay.CheckOut, workDay.Notes)
 	if err != nil {
 		return err
 	}
@@ -174,7 +239,7 @@ func (r *WorkDayRepository) Update(ctx context.Context, workDay *models.WorkDay)
 func (r *WorkDayRepository) Delete(ctx context.Context, id uuid.UUID) error {
 	query := `DELETE FROM work_days WHERE id = $1`
 
-	result, err := r.db.Exec(ctx, query, id)
+	result, err := conn(ctx, r.db).Exec(ctx, query, id)
 	if err != nil {
 		return err
 	}
@@ -185,3 +250,40 @@ func (r *WorkDayRepository) Delete(ctx context.Context, id uuid.UUID) error {
 
 	return nil
 }
+
+// GetByLabourPhone retrieves the work days of every labour record with the
+// given phone, with the amount earned each day
+func (r *WorkDayRepository) GetByLabourPhone(ctx context.Context, phone string) ([]models.LabourWorkDay, error) {
+	query := `
+		SELECT wd.id, wd.project_id, wd.labour_id, wd.work_date, wd.status, wd.overtime_hours,
+			to_char(wd.check_in, 'HH24:MI'), to_char(wd.check_out, 'HH24:MI'), wd.notes, wd.created_at,
+			p.name, e.amount
+		FROM work_days wd
+		INNER JOIN work_day_earnings e ON e.id = wd.id
+		INNER JOIN labours l ON wd.labour_id = l.id
+		INNER JOIN projects p ON wd.project_id = p.id
+		WHERE l.phone = $1 AND l.deleted_at IS NULL AND p.deleted_at IS NULL
+		ORDER BY wd.work_date DESC, p.name ASC
+	`
+
+	rows, err := conn(ctx, r.db).Query(ctx, query, phone)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var workDays []models.LabourWorkDay
+	for rows.Next() {
+		var wd models.LabourWorkDay
+		err := rows.Scan(&wd.ID, &wd.ProjectID, &wd.LabourID,
+			&wd.WorkDate, &wd.Status, &wd.OvertimeHours,
+			&wd.CheckIn, &wd.CheckOut, &wd.Notes, &wd.CreatedAt,
+			&wd.ProjectName, &wd.Amount)
+		if err != nil {
+			return nil, err
+		}
+		workDays = append(workDays, wd)
+	}
+
+	return workDays, rows.Err()
+}
@@ -0,0 +1,138 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/google/uuid"
+	"github.com/vivekanand/labour-thekedar-backend/internal/models"
+	"github.com/vivekanand/labour-thekedar-backend/internal/repository"
+)
+
+// defaultAuditLimit is how many audit entries are listed when no limit is given
+const defaultAuditLimit = 100
+
+type actorKey struct{}
+
+// WithActor returns a context carrying the user making changes, for the audit log
+func WithActor(ctx context.Context, userID uuid.UUID) context.Context {
+	return context.WithValue(ctx, actorKey{}, userID)
+}
+
+// actorFrom returns the user carried by the context, if any
+func actorFrom(ctx context.Context) *uuid.UUID {
+	if userID, ok := ctx.Value(actorKey{}).(uuid.UUID); ok {
+		return &userID
+	}
+	return nil
+}
+
+// AuditService records and lists changes made through the other services
+type AuditService struct {
+	auditRepo  *repository.AuditRepository
+	transactor *repository.Transactor
+}
+
+// NewAuditService creates a new AuditService
+func NewAuditService(auditRepo *repository.AuditRepository, transactor *repository.Transactor) *AuditService {
+	return &AuditService{auditRepo: auditRepo, transactor: transactor}
+}
+
+// InTx runs a change and the Record calls describing it in one transaction,
+// so a change is never saved without its audit entries
+func (s *AuditService) InTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	return s.transactor.InTx(ctx, fn)
+}
+
+// Record appends an audit entry for a change made by the actor in ctx, with
+// snapshots of the record before and after it. Call it within InTx, with
+// the context InTx passes, so that a failure here undoes the change.
+func (s *AuditService) Record(ctx context.Context, entry *models.AuditLog, before, after any) error {
+	entry.ActorID = actorFrom(ctx)
+
+	var err error
+	if before != nil {
+		if entry.Before, err = json.Marshal(before); err != nil {
+			return err
+		}
+	}
+	if after != nil {
+		if entry.After, err = json.Marshal(after); err != nil {
+			return err
+		}
+	}
+
+	return s.auditRepo.Create(ctx, entry)
+}
+
+// GetByProjectID lists a project's audit entries, newest first
+func (s *AuditService) GetByProjectID(ctx context.Context, projectID uuid.UUID, query *models.AuditQuery) ([]models.AuditLog, error) {
+	filter, err := parseAuditQuery(query)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.auditRepo.GetByProjectID(ctx, projectID, filter)
+}
+
+// GetByLabourID lists a labour's audit entries on the given projects, and
+// those not tied to any project, newest first
+func (s *AuditService) GetByLabourID(ctx context.Context, labourID uuid.UUID, projects []models.Project, query *models.AuditQuery) ([]models.AuditLog, error) {
+	filter, err := parseAuditQuery(query)
+	if err != nil {
+		return nil, err
+	}
+
+	projectIDs := make([]uuid.UUID, len(projects))
+	for i, p := range projects {
+		projectIDs[i] = p.ID
+	}
+
+	return s.auditRepo.GetByLabourID(ctx, labourID, projectIDs, filter)
+}
+
+// parseAuditQuery validates the query string filters. Both dates are
+// inclusive.
+func parseAuditQuery(query *models.AuditQuery) (*models.AuditFilter, error) {
+	filter := &models.AuditFilter{Limit: query.Limit}
+	if filter.Limit == 0 {
+		filter.Limit = defaultAuditLimit
+	}
+
+	if query.EntityType != "" {
+		filter.EntityType = &query.EntityType
+	}
+	if query.Action != "" {
+		filter.Action = &query.Action
+	}
+
+	if query.EntityID != "" {
+		id, err := uuid.Parse(query.EntityID)
+		if err != nil {
+			return nil, models.ErrInvalidAuditFilter
+		}
+		filter.EntityID = &id
+	}
+	if query.ActorID != "" {
+		id, err := uuid.Parse(query.ActorID)
+		if err != nil {
+			return nil, models.ErrInvalidAuditFilter
+		}
+		filter.ActorID = &id
+	}
+
+	var err error
+	if filter.From, err = parseOptionalDate(query.From); err != nil {
+		return nil, models.ErrInvalidAuditFilter
+	}
+	to, err := parseOptionalDate(query.To)
+	if err != nil {
+		return nil, models.ErrInvalidAuditFilter
+	}
+	if to != nil {
+		before := to.AddDate(0, 0, 1)
+		filter.Before = &before
+	}
+
+	return filter, nil
+}
@@ -0,0 +1,57 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vivekanand/labour-thekedar-backend/internal/models"
+)
+
+func TestWithActor(t *testing.T) {
+	assert.Nil(t, actorFrom(context.Background()))
+
+	userID := uuid.New()
+	actor := actorFrom(WithActor(context.Background(), userID))
+	require.NotNil(t, actor)
+	assert.Equal(t, userID, *actor)
+}
+
+func TestParseAuditQuery(t *testing.T) {
+	t.Run("empty query lists the latest entries", func(t *testing.T) {
+		filter, err := parseAuditQuery(&models.AuditQuery{})
+		require.NoError(t, err)
+		assert.Equal(t, defaultAuditLimit, filter.Limit)
+		assert.Nil(t, filter.EntityType)
+		assert.Nil(t, filter.From)
+		assert.Nil(t, filter.Before)
+	})
+
+	t.Run("to date is inclusive", func(t *testing.T) {
+		filter, err := parseAuditQuery(&models.AuditQuery{
+			EntityType: models.AuditEntityWorkDay,
+			From:       "2024-03-01",
+			To:         "2024-03-31",
+			Limit:      20,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, models.AuditEntityWorkDay, *filter.EntityType)
+		assert.Equal(t, "2024-03-01", filter.From.Format("2006-01-02"))
+		assert.Equal(t, "2024-04-01", filter.Before.Format("2006-01-02"))
+		assert.Equal(t, 20, filter.Limit)
+	})
+
+	t.Run("invalid filters", func(t *testing.T) {
+		for _, query := range []models.AuditQuery{
+			{From: "01-03-2024"},
+			{To: "yesterday"},
+			{ActorID: "not-a-uuid"},
+			{EntityID: "42"},
+		} {
+			_, err := parseAuditQuery(&query)
+			assert.ErrorIs(t, err, models.ErrInvalidAuditFilter)
+		}
+	})
+}
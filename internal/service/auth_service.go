@@ -2,6 +2,10 @@ package service
 
 import (
 	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
 	"errors"
 	"time"
 
@@ -14,35 +18,82 @@ import (
 
 // AuthService handles authentication operations
 type AuthService struct {
-	userRepo    *repository.UserRepository
-	otpProvider otp.Provider
-	jwtSecret   string
+	userRepo         *repository.UserRepository
+	authEventRepo    *repository.AuthEventRepository
+	refreshTokenRepo *repository.RefreshTokenRepository
+	sessionRepo      *repository.SessionRepository
+	labourRepo       *repository.LabourRepository
+	otpProvider      otp.Provider
+	jwtSecret        string
+	limits           OTPLimits
+}
+
+// OTPLimits configures OTP send and verify rate limits. Counts apply per Window.
+type OTPLimits struct {
+	ResendCooldown   time.Duration
+	Window           time.Duration
+	SendsPerPhone    int
+	SendsPerIP       int
+	VerifiesPerPhone int
+	VerifiesPerIP    int
 }
 
 // NewAuthService creates a new AuthService
-func NewAuthService(userRepo *repository.UserRepository, otpProvider otp.Provider, jwtSecret string) *AuthService {
+func NewAuthService(userRepo *repository.UserRepository, authEventRepo *repository.AuthEventRepository, refreshTokenRepo *repository.RefreshTokenRepository, sessionRepo *repository.SessionRepository, labourRepo *repository.LabourRepository, otpProvider otp.Provider, jwtSecret string, limits OTPLimits) *AuthService {
 	return &AuthService{
-		userRepo:    userRepo,
-		otpProvider: otpProvider,
-		jwtSecret:   jwtSecret,
+		userRepo:         userRepo,
+		authEventRepo:    authEventRepo,
+		refreshTokenRepo: refreshTokenRepo,
+		sessionRepo:      sessionRepo,
+		labourRepo:       labourRepo,
+		otpProvider:      otpProvider,
+		jwtSecret:        jwtSecret,
+		limits:           limits,
 	}
 }
 
+// Token lifetimes. Access tokens are short-lived because they can't be
+// revoked; refresh tokens are stored server-side and rotate on every use.
+const (
+	accessTokenTTL  = 15 * time.Minute
+	refreshTokenTTL = 7 * 24 * time.Hour
+)
+
+// labourTokenTTL is the lifetime of a labour's read-only token. Labour
+// tokens have no refresh token; the labour logs in again with an OTP.
+const labourTokenTTL = 24 * time.Hour
+
+// Token types. Access tokens may be used to call the API; labour tokens may
+// only be used for a labour's read-only view of their own records.
+const (
+	TokenTypeAccess = "access"
+	TokenTypeLabour = "labour"
+)
+
 // Claims represents JWT claims
 type Claims struct {
-	UserID uuid.UUID `json:"user_id"`
-	Phone  string    `json:"phone"`
+	UserID    uuid.UUID `json:"user_id"`
+	Phone     string    `json:"phone"`
+	TokenType string    `json:"typ"`
+	SessionID uuid.UUID `json:"sid"`
 	jwt.RegisteredClaims
 }
 
 // TokenResponse represents the response with JWT tokens
 type TokenResponse struct {
-	AccessToken  string    `json:"access_token"`
-	RefreshToken string    `json:"refresh_token"`
-	ExpiresAt    time.Time `json:"expires_at"`
+	AccessToken  string       `json:"access_token"`
+	RefreshToken string       `json:"refresh_token"`
+	ExpiresAt    time.Time    `json:"expires_at"`
 	User         *models.User `json:"user"`
 }
 
+// LabourTokenResponse represents the response to a labour login
+type LabourTokenResponse struct {
+	AccessToken string          `json:"access_token"`
+	ExpiresAt   time.Time       `json:"expires_at"`
+	Labours     []models.Labour `json:"labours"`
+}
+
 // SendOTPRequest represents the request to send OTP
 type SendOTPRequest struct {
 	Phone string `json:"phone" binding:"required,min=10,max=15"`
@@ -50,8 +101,16 @@ type SendOTPRequest struct {
 
 // VerifyOTPRequest represents the request to verify OTP
 type VerifyOTPRequest struct {
-	Phone string `json:"phone" binding:"required,min=10,max=15"`
-	OTP   string `json:"otp" binding:"required,len=6"`
+	Phone      string `json:"phone" binding:"required,min=10,max=15"`
+	OTP        string `json:"otp" binding:"required,len=6"`
+	DeviceName string `json:"device_name" binding:"max=100"`
+}
+
+// DeviceInfo describes the client a login comes from
+type DeviceInfo struct {
+	Name      string
+	IP        string
+	UserAgent string
 }
 
 // RefreshTokenRequest represents the request to refresh token
@@ -59,21 +118,43 @@ type RefreshTokenRequest struct {
 	RefreshToken string `json:"refresh_token" binding:"required"`
 }
 
-// SendOTP sends an OTP to the given phone number
-func (s *AuthService) SendOTP(ctx context.Context, phone string) error {
+// LogoutRequest represents the request to log out of one device
+type LogoutRequest struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
+}
+
+// SendOTP sends an OTP to the given phone number, subject to the resend
+// cooldown and per-phone and per-IP send limits
+func (s *AuthService) SendOTP(ctx context.Context, phone, clientIP string) error {
+	if err := s.reserveSend(ctx, phone, clientIP); err != nil {
+		return err
+	}
+
 	_, err := s.otpProvider.SendOTP(ctx, phone)
 	return err
 }
 
-// VerifyOTP verifies the OTP and returns JWT tokens
-func (s *AuthService) VerifyOTP(ctx context.Context, phone, otpCode string) (*TokenResponse, error) {
-	// Verify OTP
-	valid, err := s.otpProvider.VerifyOTP(ctx, phone, otpCode)
+// reserveSend counts an OTP send towards the phone's and client IP's limits,
+// or returns the error for the cooldown or limit it would break
+func (s *AuthService) reserveSend(ctx context.Context, phone, clientIP string) error {
+	last, err := s.authEventRepo.LastByPhone(ctx, repository.AuthEventOTPSend, phone)
 	if err != nil {
-		return nil, err
+		return err
 	}
-	if !valid {
-		return nil, models.ErrInvalidOTP
+	if !last.IsZero() && time.Since(last) < s.limits.ResendCooldown {
+		return models.ErrOTPCooldown
+	}
+
+	// Record before sending so failed deliveries still count towards the limit
+	return s.recordWithinLimits(ctx, repository.AuthEventOTPSend, phone, clientIP,
+		s.limits.SendsPerPhone, s.limits.SendsPerIP, models.ErrOTPSendLimit)
+}
+
+// VerifyOTP verifies the OTP, starts a session for the device and returns JWT
+// tokens, subject to per-phone and per-IP verification limits
+func (s *AuthService) VerifyOTP(ctx context.Context, phone, otpCode string, device DeviceInfo) (*TokenResponse, error) {
+	if err := s.verifyCode(ctx, phone, otpCode, device.IP); err != nil {
+		return nil, err
 	}
 
 	// Get or create user
@@ -82,31 +163,261 @@ func (s *AuthService) VerifyOTP(ctx context.Context, phone, otpCode string) (*To
 		return nil, err
 	}
 
-	// Generate tokens
-	return s.generateTokens(user)
+	// Each login starts a new session, which is also the refresh token family
+	session := &models.Session{
+		UserID:     user.ID,
+		DeviceName: device.Name,
+		IP:         device.IP,
+		UserAgent:  device.UserAgent,
+		ExpiresAt:  time.Now().Add(refreshTokenTTL),
+	}
+	if err := s.sessionRepo.Create(ctx, session); err != nil {
+		return nil, err
+	}
+
+	return s.generateTokens(ctx, user, session.ID, nil)
+}
+
+// SendLabourOTP sends an OTP to a phone number registered to a labour. The
+// same limits as SendOTP apply, and are checked first. Unregistered phones
+// get no OTP but no error either, so the endpoint can't be used to find out
+// which phones belong to labours.
+func (s *AuthService) SendLabourOTP(ctx context.Context, phone, clientIP string) error {
+	if err := s.reserveSend(ctx, phone, clientIP); err != nil {
+		return err
+	}
+
+	exists, err := s.labourRepo.ExistsByPhone(ctx, phone)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return nil
+	}
+
+	_, err = s.otpProvider.SendOTP(ctx, phone)
+	return err
+}
+
+// VerifyLabourOTP verifies the OTP sent to a labour's phone and returns a
+// read-only labour token covering every labour record with that phone
+func (s *AuthService) VerifyLabourOTP(ctx context.Context, phone, otpCode, clientIP string) (*LabourTokenResponse, error) {
+	if err := s.verifyCode(ctx, phone, otpCode, clientIP); err != nil {
+		return nil, err
+	}
+
+	labours, err := s.labourRepo.GetByPhone(ctx, phone)
+	if err != nil {
+		return nil, err
+	}
+	if len(labours) == 0 {
+		return nil, models.ErrNotFound
+	}
+
+	now := time.Now()
+	expiresAt := now.Add(labourTokenTTL)
+	claims := &Claims{
+		Phone:     phone,
+		TokenType: TokenTypeLabour,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+			IssuedAt:  jwt.NewNumericDate(now),
+			Subject:   phone,
+		},
+	}
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(s.jwtSecret))
+	if err != nil {
+		return nil, err
+	}
+
+	return &LabourTokenResponse{
+		AccessToken: token,
+		ExpiresAt:   expiresAt,
+		Labours:     labours,
+	}, nil
 }
 
-// RefreshToken refreshes the JWT tokens
+// verifyCode checks an OTP, subject to per-phone and per-IP verification limits
+func (s *AuthService) verifyCode(ctx context.Context, phone, otpCode, clientIP string) error {
+	if err := s.recordWithinLimits(ctx, repository.AuthEventOTPVerify, phone, clientIP,
+		s.limits.VerifiesPerPhone, s.limits.VerifiesPerIP, models.ErrOTPVerifyLimit); err != nil {
+		return err
+	}
+
+	valid, err := s.otpProvider.VerifyOTP(ctx, phone, otpCode)
+	if err != nil {
+		if errors.Is(err, otp.ErrTooManyAttempts) {
+			return models.ErrOTPLocked
+		}
+		return err
+	}
+	if !valid {
+		return models.ErrInvalidOTP
+	}
+
+	return nil
+}
+
+// PruneAuthEvents removes rate-limit events that have left the window
+func (s *AuthService) PruneAuthEvents(ctx context.Context) (int64, error) {
+	return s.authEventRepo.DeleteBefore(ctx, time.Now().Add(-s.limits.Window))
+}
+
+// recordWithinLimits records an event for the phone and client IP, or
+// returns limitErr when either has reached its limit within the window
+func (s *AuthService) recordWithinLimits(ctx context.Context, action, phone, clientIP string, perPhone, perIP int, limitErr error) error {
+	since := time.Now().Add(-s.limits.Window)
+	recorded, err := s.authEventRepo.RecordWithinLimits(ctx, action, phone, clientIP, since, perPhone, perIP)
+	if err != nil {
+		return err
+	}
+	if !recorded {
+		return limitErr
+	}
+
+	return nil
+}
+
+// RefreshToken exchanges a refresh token for a new token pair. The old
+// refresh token is rotated out; presenting it again revokes every token in
+// its family, since it means the token was stolen or replayed.
 func (s *AuthService) RefreshToken(ctx context.Context, refreshToken string) (*TokenResponse, error) {
-	// Parse and validate refresh token
-	claims, err := s.validateToken(refreshToken)
+	stored, err := s.refreshTokenRepo.GetByHash(ctx, hashRefreshToken(refreshToken))
 	if err != nil {
-		return nil, errors.New("invalid refresh token")
+		if errors.Is(err, models.ErrNotFound) {
+			return nil, models.ErrInvalidToken
+		}
+		return nil, err
+	}
+
+	if stored.RevokedAt != nil || time.Now().After(stored.ExpiresAt) {
+		return nil, models.ErrInvalidToken
+	}
+	if stored.RotatedAt != nil {
+		return nil, s.revokeReusedSession(ctx, stored.FamilyID)
+	}
+
+	user, err := s.userRepo.GetByID(ctx, stored.UserID)
+	if err != nil {
+		return nil, err
 	}
 
-	// Get user
-	user, err := s.userRepo.GetByID(ctx, claims.UserID)
+	tokens, err := s.generateTokens(ctx, user, stored.FamilyID, &stored.ID)
 	if err != nil {
+		if errors.Is(err, models.ErrTokenReused) {
+			// Lost a race with another use of the same token
+			return nil, s.revokeReusedSession(ctx, stored.FamilyID)
+		}
 		return nil, err
 	}
 
-	// Generate new tokens
-	return s.generateTokens(user)
+	return tokens, nil
+}
+
+// Logout revokes the session the given refresh token belongs to. Unknown
+// tokens are ignored so logout is idempotent.
+func (s *AuthService) Logout(ctx context.Context, refreshToken string) error {
+	stored, err := s.refreshTokenRepo.GetByHash(ctx, hashRefreshToken(refreshToken))
+	if err != nil {
+		if errors.Is(err, models.ErrNotFound) {
+			return nil
+		}
+		return err
+	}
+
+	return s.sessionRepo.Revoke(ctx, stored.FamilyID)
+}
+
+// LogoutAll revokes every session of a user
+func (s *AuthService) LogoutAll(ctx context.Context, userID uuid.UUID) error {
+	return s.sessionRepo.RevokeAllForUser(ctx, userID)
+}
+
+// ListSessions returns a user's active sessions, flagging the current one
+func (s *AuthService) ListSessions(ctx context.Context, userID, currentSessionID uuid.UUID) ([]models.Session, error) {
+	sessions, err := s.sessionRepo.GetActiveByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range sessions {
+		sessions[i].Current = sessions[i].ID == currentSessionID
+	}
+
+	return sessions, nil
+}
+
+// RevokeSession revokes one of a user's sessions. Sessions of other users
+// are reported as not found.
+func (s *AuthService) RevokeSession(ctx context.Context, userID, sessionID uuid.UUID) error {
+	session, err := s.sessionRepo.GetByID(ctx, sessionID)
+	if err != nil {
+		return err
+	}
+	if session.UserID != userID {
+		return models.ErrNotFound
+	}
+
+	return s.sessionRepo.Revoke(ctx, sessionID)
+}
+
+// ValidateSession checks that the session an access token was issued to
+// has not been revoked or expired
+func (s *AuthService) ValidateSession(ctx context.Context, claims *Claims) error {
+	if claims.SessionID == uuid.Nil {
+		return models.ErrSessionRevoked
+	}
+
+	active, err := s.sessionRepo.Touch(ctx, claims.SessionID)
+	if err != nil {
+		return err
+	}
+	if !active {
+		return models.ErrSessionRevoked
+	}
+
+	return nil
 }
 
-// ValidateToken validates a JWT token and returns the claims
+// PruneSessions removes expired sessions and refresh tokens
+func (s *AuthService) PruneSessions(ctx context.Context) (int64, error) {
+	sessions, err := s.sessionRepo.DeleteExpired(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	tokens, err := s.refreshTokenRepo.DeleteExpired(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	return sessions + tokens, nil
+}
+
+// ValidateToken validates an access token and returns the claims
 func (s *AuthService) ValidateToken(tokenString string) (*Claims, error) {
-	return s.validateToken(tokenString)
+	claims, err := s.validateToken(tokenString)
+	if err != nil {
+		return nil, err
+	}
+	if claims.TokenType != TokenTypeAccess {
+		return nil, models.ErrInvalidToken
+	}
+
+	return claims, nil
+}
+
+// ValidateLabourToken validates a labour token and returns the claims
+func (s *AuthService) ValidateLabourToken(tokenString string) (*Claims, error) {
+	claims, err := s.validateToken(tokenString)
+	if err != nil {
+		return nil, err
+	}
+	if claims.TokenType != TokenTypeLabour || claims.Phone == "" {
+		return nil, models.ErrInvalidToken
+	}
+
+	return claims, nil
 }
 
 func (s *AuthService) validateToken(tokenString string) (*Claims, error) {
@@ -128,15 +439,25 @@ func (s *AuthService) validateToken(tokenString string) (*Claims, error) {
 	return claims, nil
 }
 
-func (s *AuthService) generateTokens(user *models.User) (*TokenResponse, error) {
+func (s *AuthService) revokeReusedSession(ctx context.Context, sessionID uuid.UUID) error {
+	if err := s.sessionRepo.Revoke(ctx, sessionID); err != nil {
+		return err
+	}
+	return models.ErrTokenReused
+}
+
+// generateTokens signs an access token and issues a refresh token for the
+// given session. When rotatedFrom is set, that token is rotated out atomically.
+func (s *AuthService) generateTokens(ctx context.Context, user *models.User, sessionID uuid.UUID, rotatedFrom *uuid.UUID) (*TokenResponse, error) {
 	now := time.Now()
-	accessExpiry := now.Add(24 * time.Hour)       // 24 hours
-	refreshExpiry := now.Add(7 * 24 * time.Hour)  // 7 days
+	accessExpiry := now.Add(accessTokenTTL)
 
 	// Access token
 	accessClaims := &Claims{
-		UserID: user.ID,
-		Phone:  user.Phone,
+		UserID:    user.ID,
+		Phone:     user.Phone,
+		TokenType: TokenTypeAccess,
+		SessionID: sessionID,
 		RegisteredClaims: jwt.RegisteredClaims{
 			ExpiresAt: jwt.NewNumericDate(accessExpiry),
 			IssuedAt:  jwt.NewNumericDate(now),
@@ -149,18 +470,22 @@ func (s *AuthService) generateTokens(user *models.User) (*TokenResponse, error)
 		return nil, err
 	}
 
-	// Refresh token
-	refreshClaims := &Claims{
-		UserID: user.ID,
-		Phone:  user.Phone,
-		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(refreshExpiry),
-			IssuedAt:  jwt.NewNumericDate(now),
-			Subject:   user.ID.String(),
-		},
+	// Refresh token: opaque random value, only its hash is stored
+	refreshTokenString, err := newRefreshToken()
+	if err != nil {
+		return nil, err
+	}
+	stored := &models.RefreshToken{
+		UserID:    user.ID,
+		FamilyID:  sessionID,
+		TokenHash: hashRefreshToken(refreshTokenString),
+		ExpiresAt: now.Add(refreshTokenTTL),
+	}
+	if rotatedFrom != nil {
+		err = s.refreshTokenRepo.Rotate(ctx, *rotatedFrom, stored)
+	} else {
+		err = s.refreshTokenRepo.Create(ctx, stored)
 	}
-	refreshToken := jwt.NewWithClaims(jwt.SigningMethodHS256, refreshClaims)
-	refreshTokenString, err := refreshToken.SignedString([]byte(s.jwtSecret))
 	if err != nil {
 		return nil, err
 	}
@@ -172,3 +497,19 @@ func (s *AuthService) generateTokens(user *models.User) (*TokenResponse, error)
 		User:         user,
 	}, nil
 }
+
+// newRefreshToken returns a random, URL-safe refresh token
+func newRefreshToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// hashRefreshToken returns the hex SHA-256 of a refresh token. Tokens are
+// random, so no salt or key is needed.
+func hashRefreshToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -3,7 +3,10 @@ package service
 import (
 	"context"
 	"testing"
+	"time"
 
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/vivekanand/labour-thekedar-backend/pkg/otp"
@@ -47,3 +50,61 @@ func TestAuthService_GenerateAndValidateToken(t *testing.T) {
 		assert.False(t, valid)
 	})
 }
+
+func TestAuthService_ValidateTokenType(t *testing.T) {
+	secret := "test-secret"
+	s := &AuthService{jwtSecret: secret}
+
+	sign := func(tokenType string) string {
+		claims := &Claims{
+			UserID:    uuid.New(),
+			Phone:     "+1234567890",
+			TokenType: tokenType,
+			RegisteredClaims: jwt.RegisteredClaims{
+				ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+			},
+		}
+		signed, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(secret))
+		require.NoError(t, err)
+		return signed
+	}
+
+	t.Run("accepts access tokens", func(t *testing.T) {
+		claims, err := s.ValidateToken(sign(TokenTypeAccess))
+		require.NoError(t, err)
+		assert.Equal(t, TokenTypeAccess, claims.TokenType)
+	})
+
+	t.Run("rejects tokens without access type", func(t *testing.T) {
+		_, err := s.ValidateToken(sign(""))
+		assert.Error(t, err)
+
+		_, err = s.ValidateToken(sign("refresh"))
+		assert.Error(t, err)
+
+		_, err = s.ValidateToken(sign(TokenTypeLabour))
+		assert.Error(t, err)
+	})
+
+	t.Run("labour tokens are only accepted as labour tokens", func(t *testing.T) {
+		claims, err := s.ValidateLabourToken(sign(TokenTypeLabour))
+		require.NoError(t, err)
+		assert.Equal(t, "+1234567890", claims.Phone)
+
+		_, err = s.ValidateLabourToken(sign(TokenTypeAccess))
+		assert.Error(t, err)
+	})
+}
+
+func TestHashRefreshToken(t *testing.T) {
+	token, err := newRefreshToken()
+	require.NoError(t, err)
+
+	other, err := newRefreshToken()
+	require.NoError(t, err)
+
+	assert.NotEqual(t, token, other)
+	assert.Len(t, hashRefreshToken(token), 64)
+	assert.Equal(t, hashRefreshToken(token), hashRefreshToken(token))
+	assert.NotEqual(t, hashRefreshToken(token), hashRefreshToken(other))
+}
@@ -0,0 +1,313 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/vivekanand/labour-thekedar-backend/internal/models"
+	"github.com/vivekanand/labour-thekedar-backend/internal/repository"
+)
+
+// BillingService handles client billing rates and invoices
+type BillingService struct {
+	billingRepo *repository.BillingRepository
+	projectRepo *repository.ProjectRepository
+	labourRepo  *repository.LabourRepository
+	audit       *AuditService
+}
+
+// NewBillingService creates a new BillingService
+func NewBillingService(billingRepo *repository.BillingRepository, projectRepo *repository.ProjectRepository, labourRepo *repository.LabourRepository, audit *AuditService) *BillingService {
+	return &BillingService{
+		billingRepo: billingRepo,
+		projectRepo: projectRepo,
+		labourRepo:  labourRepo,
+		audit:       audit,
+	}
+}
+
+// GetRates retrieves a project's billing rates
+func (s *BillingService) GetRates(ctx context.Context, projectID uuid.UUID) ([]models.BillingRate, error) {
+	return s.billingRepo.GetRates(ctx, projectID)
+}
+
+// SetRate sets a project's billing rate for a labour, a role or the whole
+// project, replacing any rate with the same scope. A labour must be assigned
+// to the project.
+func (s *BillingService) SetRate(ctx context.Context, projectID uuid.UUID, req *models.SetBillingRateRequest) (*models.BillingRate, error) {
+	rate := &models.BillingRate{
+		ProjectID:     projectID,
+		LabourID:      req.LabourID,
+		Role:          req.Role,
+		DayRate:       req.DayRate,
+		MarkupPercent: req.MarkupPercent,
+	}
+	if err := rate.Validate(); err != nil {
+		return nil, err
+	}
+
+	if rate.LabourID != nil {
+		assigned, err := s.labourRepo.IsAssignedToProject(ctx, projectID, *rate.LabourID)
+		if err != nil {
+			return nil, err
+		}
+		if !assigned {
+			return nil, models.ErrInvalidLabour
+		}
+	}
+
+	err := s.audit.InTx(ctx, func(ctx context.Context) error {
+		created, err := s.billingRepo.UpsertRate(ctx, rate)
+		if err != nil {
+			return err
+		}
+
+		action := models.AuditActionUpdate
+		if created {
+			action = models.AuditActionCreate
+		}
+		return s.audit.Record(ctx, &models.AuditLog{
+			EntityType: models.AuditEntityBillingRate,
+			EntityID:   rate.ID,
+			ProjectID:  &projectID,
+			LabourID:   rate.LabourID,
+			Action:     action,
+		}, nil, rate)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return rate, nil
+}
+
+// DeleteRate removes a project's billing rate
+func (s *BillingService) DeleteRate(ctx context.Context, projectID, rateID uuid.UUID) error {
+	return s.audit.InTx(ctx, func(ctx context.Context) error {
+		rate, err := s.billingRepo.DeleteRate(ctx, projectID, rateID)
+		if err != nil {
+			return err
+		}
+		return s.audit.Record(ctx, &models.AuditLog{
+			EntityType: models.AuditEntityBillingRate,
+			EntityID:   rate.ID,
+			ProjectID:  &projectID,
+			LabourID:   rate.LabourID,
+			Action:     models.AuditActionDelete,
+		}, rate, nil)
+	})
+}
+
+// CreateInvoice drafts an invoice to the project's client for its attendance
+// over a period, each labour priced at their billing rate
+func (s *BillingService) CreateInvoice(ctx context.Context, projectID, userID uuid.UUID, req *models.CreateInvoiceRequest) (*models.InvoiceWithDetails, error) {
+	start, err := time.Parse("2006-01-02", req.PeriodStart)
+	if err != nil {
+		return nil, models.ErrInvalidDate
+	}
+	end, err := time.Parse("2006-01-02", req.PeriodEnd)
+	if err != nil {
+		return nil, models.ErrInvalidDate
+	}
+	if end.Before(start) {
+		return nil, models.ErrInvalidDate
+	}
+
+	project, err := s.projectRepo.GetByID(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	billable, err := s.billingRepo.GetBillable(ctx, projectID, start, end)
+	if err != nil {
+		return nil, err
+	}
+	rates, err := s.billingRepo.GetRates(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	items := models.NewInvoiceItems(billable, rates)
+	if len(items) == 0 {
+		return nil, models.ErrInvoiceEmpty
+	}
+
+	invoice := &models.Invoice{
+		OrganisationID: project.OrganisationID,
+		ProjectID:      projectID,
+		PeriodStart:    start,
+		PeriodEnd:      end,
+		ClientName:     project.ClientName,
+		Total:          models.InvoiceTotal(items),
+		Notes:          req.Notes,
+		CreatedBy:      &userID,
+	}
+	var created *models.InvoiceWithDetails
+	err = s.audit.InTx(ctx, func(ctx context.Context) error {
+		if err := s.billingRepo.CreateInvoice(ctx, invoice, items); err != nil {
+			return err
+		}
+
+		var err error
+		created, err = s.GetInvoice(ctx, invoice.ID)
+		if err != nil {
+			return err
+		}
+		return s.audit.Record(ctx, &models.AuditLog{
+			EntityType: models.AuditEntityInvoice,
+			EntityID:   invoice.ID,
+			ProjectID:  &projectID,
+			Action:     models.AuditActionCreate,
+		}, nil, created)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return created, nil
+}
+
+// GetInvoice retrieves an invoice with its items and receipts
+func (s *BillingService) GetInvoice(ctx context.Context, id uuid.UUID) (*models.InvoiceWithDetails, error) {
+	invoice, err := s.billingRepo.GetInvoice(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	items, err := s.billingRepo.GetItems(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	receipts, err := s.billingRepo.GetReceipts(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if receipts == nil {
+		receipts = []models.InvoiceReceipt{}
+	}
+
+	return &models.InvoiceWithDetails{
+		Invoice:  *invoice,
+		Items:    items,
+		Receipts: receipts,
+	}, nil
+}
+
+// GetInvoiceSummary retrieves an invoice without its items and receipts
+func (s *BillingService) GetInvoiceSummary(ctx context.Context, id uuid.UUID) (*models.Invoice, error) {
+	return s.billingRepo.GetInvoice(ctx, id)
+}
+
+// GetInvoicesByProjectID retrieves a project's invoices
+func (s *BillingService) GetInvoicesByProjectID(ctx context.Context, projectID uuid.UUID) ([]models.Invoice, error) {
+	return s.billingRepo.GetInvoicesByProjectID(ctx, projectID)
+}
+
+// SendInvoice issues a draft invoice, giving it the organisation's next
+// invoice number. Sent invoices can no longer be deleted.
+func (s *BillingService) SendInvoice(ctx context.Context, id uuid.UUID) (*models.InvoiceWithDetails, error) {
+	invoice, err := s.billingRepo.GetInvoice(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	before := *invoice
+	err = s.audit.InTx(ctx, func(ctx context.Context) error {
+		if err := s.billingRepo.SendInvoice(ctx, invoice); err != nil {
+			return err
+		}
+		return s.audit.Record(ctx, &models.AuditLog{
+			EntityType: models.AuditEntityInvoice,
+			EntityID:   invoice.ID,
+			ProjectID:  &invoice.ProjectID,
+			Action:     models.AuditActionSend,
+		}, before, invoice)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.GetInvoice(ctx, id)
+}
+
+// DeleteInvoice discards a draft invoice, freeing its period
+func (s *BillingService) DeleteInvoice(ctx context.Context, id uuid.UUID) error {
+	invoice, err := s.billingRepo.GetInvoice(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	err = s.audit.InTx(ctx, func(ctx context.Context) error {
+		if err := s.billingRepo.DeleteInvoice(ctx, id); err != nil {
+			return err
+		}
+		return s.audit.Record(ctx, &models.AuditLog{
+			EntityType: models.AuditEntityInvoice,
+			EntityID:   invoice.ID,
+			ProjectID:  &invoice.ProjectID,
+			Action:     models.AuditActionDelete,
+		}, invoice, nil)
+	})
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// AddReceipt records money received from the client against a sent invoice.
+// The invoice is marked paid once its total has been received.
+func (s *BillingService) AddReceipt(ctx context.Context, id, userID uuid.UUID, req *models.CreateInvoiceReceiptRequest) (*models.InvoiceWithDetails, error) {
+	receivedOn, err := time.Parse("2006-01-02", req.ReceivedOn)
+	if err != nil {
+		return nil, models.ErrInvalidDate
+	}
+
+	invoice, err := s.billingRepo.GetInvoice(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	receipt := &models.InvoiceReceipt{
+		InvoiceID:  id,
+		Amount:     req.Amount,
+		ReceivedOn: receivedOn,
+		Reference:  req.Reference,
+		CreatedBy:  &userID,
+	}
+	var updated *models.InvoiceWithDetails
+	err = s.audit.InTx(ctx, func(ctx context.Context) error {
+		if err := s.billingRepo.CreateReceipt(ctx, receipt); err != nil {
+			return err
+		}
+		err := s.audit.Record(ctx, &models.AuditLog{
+			EntityType: models.AuditEntityReceipt,
+			EntityID:   receipt.ID,
+			ProjectID:  &invoice.ProjectID,
+			Action:     models.AuditActionCreate,
+		}, nil, receipt)
+		if err != nil {
+			return err
+		}
+
+		updated, err = s.GetInvoice(ctx, id)
+		if err != nil {
+			return err
+		}
+		if updated.Status == invoice.Status {
+			return nil
+		}
+		return s.audit.Record(ctx, &models.AuditLog{
+			EntityType: models.AuditEntityInvoice,
+			EntityID:   invoice.ID,
+			ProjectID:  &invoice.ProjectID,
+			Action:     models.AuditActionUpdate,
+		}, invoice, &updated.Invoice)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return updated, nil
+}
@@ -0,0 +1,89 @@
+package service
+
+import (
+	"context"
+	"log"
+
+	"github.com/google/uuid"
+	"github.com/vivekanand/labour-thekedar-backend/internal/models"
+	"github.com/vivekanand/labour-thekedar-backend/internal/repository"
+)
+
+// BudgetService tracks project labour spend against budget and raises an
+// alert the first time spend crosses each threshold
+type BudgetService struct {
+	budgetRepo  *repository.BudgetRepository
+	projectRepo *repository.ProjectRepository
+	thresholds  []int // Percent of the budget
+}
+
+// NewBudgetService creates a new BudgetService
+func NewBudgetService(budgetRepo *repository.BudgetRepository, projectRepo *repository.ProjectRepository, thresholds []int) *BudgetService {
+	return &BudgetService{
+		budgetRepo:  budgetRepo,
+		projectRepo: projectRepo,
+		thresholds:  thresholds,
+	}
+}
+
+// Get computes a project's budget summary as of today, raising any alerts
+// that are due, and lists its alerts
+func (s *BudgetService) Get(ctx context.Context, projectID uuid.UUID) (*models.ProjectBudget, error) {
+	budget, err := s.evaluate(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	alerts, err := s.budgetRepo.GetAlerts(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+	if alerts != nil {
+		budget.Alerts = alerts
+	}
+
+	return budget, nil
+}
+
+// Check raises any alerts due after a project's labour cost changed. The
+// change itself has already been saved, so a failure here is logged rather
+// than returned.
+func (s *BudgetService) Check(ctx context.Context, projectID uuid.UUID) {
+	if _, err := s.evaluate(ctx, projectID); err != nil {
+		log.Printf("budget: failed to check project %s: %v", projectID, err)
+	}
+}
+
+// evaluate computes a project's budget summary and records an alert for
+// each crossed threshold not yet raised for the current budget
+func (s *BudgetService) evaluate(ctx context.Context, projectID uuid.UUID) (*models.ProjectBudget, error) {
+	project, err := s.projectRepo.GetByID(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	committed, firstWorkDate, err := s.budgetRepo.GetCommitted(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	budget := models.NewProjectBudget(project, committed, firstWorkDate, today())
+	for _, threshold := range budget.CrossedThresholds(s.thresholds) {
+		alert := &models.BudgetAlert{
+			ProjectID: projectID,
+			Threshold: threshold,
+			Budget:    *budget.LabourBudget,
+			Committed: committed,
+		}
+		created, err := s.budgetRepo.CreateAlert(ctx, alert)
+		if err != nil {
+			return nil, err
+		}
+		if created {
+			log.Printf("budget: project %s has committed %s, %d%% of its %s labour budget",
+				projectID, committed, threshold, alert.Budget)
+		}
+	}
+
+	return budget, nil
+}
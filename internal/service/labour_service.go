@@ -2,6 +2,9 @@ package service
 
 import (
 	"context"
+	"errors"
+	"strings"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/vivekanand/labour-thekedar-backend/internal/models"
@@ -10,29 +13,56 @@ import (
 
 // LabourService handles labour business logic
 type LabourService struct {
-	labourRepo *repository.LabourRepository
+	labourRepo  *repository.LabourRepository
+	projectRepo *repository.ProjectRepository
+	orgRepo     *repository.OrganisationRepository
+	paymentRepo *repository.PaymentRepository
+	audit       *AuditService
 }
 
 // NewLabourService creates a new LabourService
-func NewLabourService(labourRepo *repository.LabourRepository) *LabourService {
+func NewLabourService(labourRepo *repository.LabourRepository, projectRepo *repository.ProjectRepository, orgRepo *repository.OrganisationRepository, paymentRepo *repository.PaymentRepository, audit *AuditService) *LabourService {
 	return &LabourService{
-		labourRepo: labourRepo,
+		labourRepo:  labourRepo,
+		projectRepo: projectRepo,
+		orgRepo:     orgRepo,
+		paymentRepo: paymentRepo,
+		audit:       audit,
 	}
 }
 
-// Create creates a new labour
-func (s *LabourService) Create(ctx context.Context, req *models.CreateLabourRequest) (*models.Labour, error) {
+// Create creates a new labour in the requested organisation, or the user's own
+func (s *LabourService) Create(ctx context.Context, userID uuid.UUID, req *models.CreateLabourRequest) (*models.Labour, error) {
+	orgID, err := organisationFor(ctx, s.orgRepo, userID, req.OrganisationID, models.PermLaboursWrite)
+	if err != nil {
+		return nil, err
+	}
+
 	labour := &models.Labour{
-		Name:      req.Name,
-		Phone:     req.Phone,
-		DailyWage: req.DailyWage,
+		UserID:         userID,
+		OrganisationID: orgID,
+		Name:           req.Name,
+		Phone:          req.Phone,
+		DailyWage:      req.DailyWage,
+		OvertimeRate:   req.OvertimeRate,
 	}
 
 	if err := labour.Validate(); err != nil {
 		return nil, err
 	}
 
-	if err := s.labourRepo.Create(ctx, labour); err != nil {
+	err = s.audit.InTx(ctx, func(ctx context.Context) error {
+		if err := s.labourRepo.Create(ctx, labour); err != nil {
+			return err
+		}
+		return s.audit.Record(ctx, &models.AuditLog{
+			EntityType: models.AuditEntityLabour,
+			EntityID:   labour.ID,
+			LabourID:   &labour.ID,
+			Action:     models.AuditActionCreate,
+		}, nil, labour)
+	})
+	if err != nil {
 		return nil, err
 	}
 
@@ -44,13 +74,13 @@ func (s *LabourService) GetByID(ctx context.Context, id uuid.UUID) (*models.Labo
 	return s.labourRepo.GetByID(ctx, id)
 }
 
-// GetAll retrieves all labours
-func (s *LabourService) GetAll(ctx context.Context) ([]models.Labour, error) {
-	return s.labourRepo.GetAll(ctx)
+// GetByUserID retrieves all labours visible to a user
+func (s *LabourService) GetByUserID(ctx context.Context, userID uuid.UUID) ([]models.Labour, error) {
+	return s.labourRepo.GetByUserID(ctx, userID)
 }
 
 // GetByProjectID retrieves all labours for a project
-func (s *LabourService) GetByProjectID(ctx context.Context, projectID uuid.UUID) ([]models.Labour, error) {
+func (s *LabourService) GetByProjectID(ctx context.Context, projectID uuid.UUID) ([]models.AssignedLabour, error) {
 	return s.labourRepo.GetByProjectID(ctx, projectID)
 }
 
@@ -61,43 +91,336 @@ func (s *LabourService) Update(ctx context.Context, id uuid.UUID, req *models.Up
 		return nil, err
 	}
 
+	// A wage change is recorded as a new rate so earlier work days keep their rate
+	var rate *models.WageRate
+	if !req.DailyWage.Equal(labour.DailyWage) {
+		effectiveFrom := today()
+		if req.WageEffectiveFrom != "" {
+			effectiveFrom, err = time.Parse("2006-01-02", req.WageEffectiveFrom)
+			if err != nil {
+				return nil, models.ErrInvalidDate
+			}
+		}
+		rate = &models.WageRate{
+			LabourID:      labour.ID,
+			DailyWage:     req.DailyWage,
+			EffectiveFrom: effectiveFrom,
+		}
+	}
+
+	before := *labour
 	labour.Name = req.Name
 	labour.Phone = req.Phone
 	labour.DailyWage = req.DailyWage
+	labour.OvertimeRate = req.OvertimeRate
 
 	if err := labour.Validate(); err != nil {
 		return nil, err
 	}
 
-	if err := s.labourRepo.Update(ctx, labour); err != nil {
+	// A wage dated in the future only takes effect through its rate
+	if rate != nil && rate.EffectiveFrom.After(today()) {
+		labour.DailyWage = before.DailyWage
+	}
+
+	err = s.audit.InTx(ctx, func(ctx context.Context) error {
+		if err := s.labourRepo.Update(ctx, labour, rate); err != nil {
+			return err
+		}
+		return s.audit.Record(ctx, &models.AuditLog{
+			EntityType: models.AuditEntityLabour,
+			EntityID:   labour.ID,
+			LabourID:   &labour.ID,
+			Action:     models.AuditActionUpdate,
+		}, before, labour)
+	})
+	if err != nil {
 		return nil, err
 	}
 
 	return labour, nil
 }
 
-// Delete deletes a labour
+// Delete moves a labour to the trash. Labours who are still owed money, or
+// who owe an advance back, can't be deleted until they are settled.
 func (s *LabourService) Delete(ctx context.Context, id uuid.UUID) error {
-	return s.labourRepo.Delete(ctx, id)
+	labour, err := s.labourRepo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	balance, err := s.paymentRepo.GetLabourBalance(ctx, id)
+	if err != nil {
+		return err
+	}
+	if !balance.IsZero() {
+		return models.ErrUnsettledBalance
+	}
+
+	before := *labour
+	labour.DeletedBy = actorFrom(ctx)
+	err = s.audit.InTx(ctx, func(ctx context.Context) error {
+		if err := s.labourRepo.Delete(ctx, labour); err != nil {
+			return err
+		}
+		return s.audit.Record(ctx, &models.AuditLog{
+			EntityType: models.AuditEntityLabour,
+			EntityID:   labour.ID,
+			LabourID:   &labour.ID,
+			Action:     models.AuditActionDelete,
+		}, before, labour)
+	})
+	if err != nil {
+		return err
+	}
+
+	return nil
 }
 
-// AssignToProject assigns a labour to a project
-func (s *LabourService) AssignToProject(ctx context.Context, projectID, labourID uuid.UUID) error {
-	// Verify labour exists
-	_, err := s.labourRepo.GetByID(ctx, labourID)
+// GetDeletedByID retrieves a labour in the trash
+func (s *LabourService) GetDeletedByID(ctx context.Context, id uuid.UUID) (*models.Labour, error) {
+	return s.labourRepo.GetDeletedByID(ctx, id)
+}
+
+// GetTrash retrieves the trashed labours visible to a user
+func (s *LabourService) GetTrash(ctx context.Context, userID uuid.UUID) ([]models.Labour, error) {
+	return s.labourRepo.GetDeletedByUserID(ctx, userID)
+}
+
+// Restore takes a labour out of the trash
+func (s *LabourService) Restore(ctx context.Context, id uuid.UUID) (*models.Labour, error) {
+	labour, err := s.labourRepo.GetDeletedByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	before := *labour
+	err = s.audit.InTx(ctx, func(ctx context.Context) error {
+		if err := s.labourRepo.Restore(ctx, labour); err != nil {
+			return err
+		}
+		return s.audit.Record(ctx, &models.AuditLog{
+			EntityType: models.AuditEntityLabour,
+			EntityID:   labour.ID,
+			LabourID:   &labour.ID,
+			Action:     models.AuditActionRestore,
+		}, before, labour)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return labour, nil
+}
+
+// Purge permanently deletes a trashed labour and everything recorded against
+// them. The request must repeat the labour's name.
+func (s *LabourService) Purge(ctx context.Context, id uuid.UUID, req *models.PurgeRequest) error {
+	labour, err := s.labourRepo.GetDeletedByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if strings.TrimSpace(req.Confirm) != labour.Name {
+		return models.ErrPurgeNotConfirmed
+	}
+
+	err = s.audit.InTx(ctx, func(ctx context.Context) error {
+		if err := s.labourRepo.Purge(ctx, id); err != nil {
+			return err
+		}
+		return s.audit.Record(ctx, &models.AuditLog{
+			EntityType: models.AuditEntityLabour,
+			EntityID:   labour.ID,
+			LabourID:   &labour.ID,
+			Action:     models.AuditActionPurge,
+		}, labour, nil)
+	})
 	if err != nil {
 		return err
 	}
 
-	return s.labourRepo.AssignToProject(ctx, projectID, labourID)
+	return nil
+}
+
+// AssignToProject assigns a labour to a project with optional project-specific terms
+func (s *LabourService) AssignToProject(ctx context.Context, projectID uuid.UUID, req *models.AssignLabourRequest) (*models.ProjectLabour, error) {
+	// Verify labour exists and belongs to the project's organisation
+	labour, err := s.labourRepo.GetByID(ctx, req.LabourID)
+	if err != nil {
+		return nil, err
+	}
+	project, err := s.projectRepo.GetByID(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+	if labour.OrganisationID != project.OrganisationID {
+		return nil, models.ErrInvalidLabour
+	}
+
+	assignment := &models.ProjectLabour{
+		ProjectID: projectID,
+		LabourID:  req.LabourID,
+		DailyWage: req.DailyWage,
+		Role:      req.Role,
+	}
+
+	if err := assignment.Validate(); err != nil {
+		return nil, err
+	}
+
+	// Record the project wage in the rate history so later changes don't
+	// rewrite earnings for days already worked
+	var rate *models.WageRate
+	if req.DailyWage != nil {
+		effectiveFrom := today()
+		if req.WageEffectiveFrom != "" {
+			effectiveFrom, err = time.Parse("2006-01-02", req.WageEffectiveFrom)
+			if err != nil {
+				return nil, models.ErrInvalidDate
+			}
+		}
+		rate = &models.WageRate{
+			LabourID:      req.LabourID,
+			ProjectID:     &projectID,
+			DailyWage:     *req.DailyWage,
+			EffectiveFrom: effectiveFrom,
+		}
+
+		// A project wage dated in the future only takes effect through its
+		// rate; a re-assignment keeps the wage currently on the assignment
+		if effectiveFrom.After(today()) {
+			assignment.DailyWage = nil
+		}
+	}
+
+	err = s.audit.InTx(ctx, func(ctx context.Context) error {
+		// A re-assignment overwrites the current terms, so keep them
+		var before any
+		existing, err := s.labourRepo.GetAssignment(ctx, projectID, req.LabourID)
+		if err == nil {
+			before = existing
+		} else if !errors.Is(err, models.ErrNotFound) {
+			return err
+		}
+
+		if err := s.labourRepo.AssignToProject(ctx, assignment, rate); err != nil {
+			return err
+		}
+		return s.audit.Record(ctx, &models.AuditLog{
+			EntityType: models.AuditEntityAssignment,
+			EntityID:   assignment.LabourID,
+			ProjectID:  &assignment.ProjectID,
+			LabourID:   &assignment.LabourID,
+			Action:     models.AuditActionAssign,
+		}, before, assignment)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return assignment, nil
 }
 
 // RemoveFromProject removes a labour from a project
 func (s *LabourService) RemoveFromProject(ctx context.Context, projectID, labourID uuid.UUID) error {
-	return s.labourRepo.RemoveFromProject(ctx, projectID, labourID)
+	err := s.audit.InTx(ctx, func(ctx context.Context) error {
+		assignment, err := s.labourRepo.GetAssignment(ctx, projectID, labourID)
+		if err != nil {
+			return err
+		}
+
+		if err := s.labourRepo.RemoveFromProject(ctx, projectID, labourID); err != nil {
+			return err
+		}
+		return s.audit.Record(ctx, &models.AuditLog{
+			EntityType: models.AuditEntityAssignment,
+			EntityID:   labourID,
+			ProjectID:  &projectID,
+			LabourID:   &labourID,
+			Action:     models.AuditActionUnassign,
+		}, assignment, nil)
+	})
+	if err != nil {
+		return err
+	}
+
+	return nil
 }
 
 // IsAssignedToProject checks if a labour is assigned to a project
 func (s *LabourService) IsAssignedToProject(ctx context.Context, projectID, labourID uuid.UUID) (bool, error) {
 	return s.labourRepo.IsAssignedToProject(ctx, projectID, labourID)
 }
+
+// Authorize checks if a user's role in the labour's organisation grants the permission
+func (s *LabourService) Authorize(ctx context.Context, labourID, userID uuid.UUID, perm models.Permission) (bool, error) {
+	role, err := s.orgRepo.GetRoleForLabour(ctx, labourID, userID)
+	if err != nil {
+		return false, err
+	}
+
+	return role.Can(perm), nil
+}
+
+// CreateWageRate records a wage rate for a labour, optionally scoped to a project
+func (s *LabourService) CreateWageRate(ctx context.Context, labourID uuid.UUID, req *models.CreateWageRateRequest) (*models.WageRate, error) {
+	effectiveFrom, err := time.Parse("2006-01-02", req.EffectiveFrom)
+	if err != nil {
+		return nil, models.ErrInvalidDate
+	}
+
+	rate := &models.WageRate{
+		LabourID:      labourID,
+		ProjectID:     req.ProjectID,
+		DailyWage:     req.DailyWage,
+		EffectiveFrom: effectiveFrom,
+	}
+
+	if err := rate.Validate(); err != nil {
+		return nil, err
+	}
+
+	// A project rate must be on a project of the labour's organisation
+	if rate.ProjectID != nil {
+		labour, err := s.labourRepo.GetByID(ctx, labourID)
+		if err != nil {
+			return nil, err
+		}
+		project, err := s.projectRepo.GetByID(ctx, *rate.ProjectID)
+		if err != nil {
+			return nil, err
+		}
+		if labour.OrganisationID != project.OrganisationID {
+			return nil, models.ErrInvalidLabour
+		}
+	}
+
+	err = s.audit.InTx(ctx, func(ctx context.Context) error {
+		if err := s.labourRepo.CreateWageRate(ctx, rate); err != nil {
+			return err
+		}
+		return s.audit.Record(ctx, &models.AuditLog{
+			EntityType: models.AuditEntityWageRate,
+			EntityID:   rate.ID,
+			ProjectID:  rate.ProjectID,
+			LabourID:   &rate.LabourID,
+			Action:     models.AuditActionCreate,
+		}, nil, rate)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return rate, nil
+}
+
+// GetWageRates retrieves a labour's wage history
+func (s *LabourService) GetWageRates(ctx context.Context, labourID uuid.UUID) ([]models.WageRate, error) {
+	return s.labourRepo.GetWageRates(ctx, labourID)
+}
+
+// today returns the current date at midnight UTC, matching dates parsed from YYYY-MM-DD
+func today() time.Time {
+	y, m, d := time.Now().Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,158 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/vivekanand/labour-thekedar-backend/internal/models"
+	"github.com/vivekanand/labour-thekedar-backend/internal/repository"
+)
+
+// invitationTTL is how long an invitation stays open
+const invitationTTL = 7 * 24 * time.Hour
+
+// OrganisationService handles organisation, membership and invitation business logic
+type OrganisationService struct {
+	orgRepo *repository.OrganisationRepository
+}
+
+// NewOrganisationService creates a new OrganisationService
+func NewOrganisationService(orgRepo *repository.OrganisationRepository) *OrganisationService {
+	return &OrganisationService{
+		orgRepo: orgRepo,
+	}
+}
+
+// Create creates a new organisation owned by the given user
+func (s *OrganisationService) Create(ctx context.Context, userID uuid.UUID, req *models.CreateOrganisationRequest) (*models.Organisation, error) {
+	org := &models.Organisation{
+		Name: req.Name,
+	}
+
+	if err := org.Validate(); err != nil {
+		return nil, err
+	}
+
+	if err := s.orgRepo.Create(ctx, org, userID); err != nil {
+		return nil, err
+	}
+
+	return org, nil
+}
+
+// GetByUserID retrieves the organisations a user belongs to with their role in each
+func (s *OrganisationService) GetByUserID(ctx context.Context, userID uuid.UUID) ([]models.OrganisationWithRole, error) {
+	return s.orgRepo.GetByUserID(ctx, userID)
+}
+
+// Authorize checks if a user's role in an organisation grants the permission
+func (s *OrganisationService) Authorize(ctx context.Context, orgID, userID uuid.UUID, perm models.Permission) (bool, error) {
+	role, err := s.orgRepo.GetRole(ctx, orgID, userID)
+	if err != nil {
+		return false, err
+	}
+
+	return role.Can(perm), nil
+}
+
+// GetMembers retrieves the members of an organisation
+func (s *OrganisationService) GetMembers(ctx context.Context, orgID uuid.UUID) ([]models.OrganisationMember, error) {
+	return s.orgRepo.GetMembers(ctx, orgID)
+}
+
+// UpdateMemberRole changes a member's role
+func (s *OrganisationService) UpdateMemberRole(ctx context.Context, orgID, userID uuid.UUID, req *models.UpdateMemberRequest) error {
+	if !req.Role.IsValid() {
+		return models.ErrInvalidRole
+	}
+
+	return s.orgRepo.UpdateMemberRole(ctx, orgID, userID, req.Role)
+}
+
+// RemoveMember removes a member from an organisation
+func (s *OrganisationService) RemoveMember(ctx context.Context, orgID, userID uuid.UUID) error {
+	return s.orgRepo.RemoveMember(ctx, orgID, userID)
+}
+
+// Invite invites a phone number to join an organisation with the given role
+func (s *OrganisationService) Invite(ctx context.Context, orgID, invitedBy uuid.UUID, req *models.CreateInvitationRequest) (*models.Invitation, error) {
+	invitation := &models.Invitation{
+		OrganisationID: orgID,
+		Phone:          req.Phone,
+		Role:           req.Role,
+		InvitedBy:      &invitedBy,
+		ExpiresAt:      time.Now().Add(invitationTTL),
+	}
+
+	if err := invitation.Validate(); err != nil {
+		return nil, err
+	}
+
+	if err := s.orgRepo.CreateInvitation(ctx, invitation); err != nil {
+		return nil, err
+	}
+
+	return invitation, nil
+}
+
+// GetPendingInvitations retrieves an organisation's open invitations
+func (s *OrganisationService) GetPendingInvitations(ctx context.Context, orgID uuid.UUID) ([]models.Invitation, error) {
+	return s.orgRepo.GetPendingInvitations(ctx, orgID)
+}
+
+// GetInvitationsForPhone retrieves open invitations addressed to a phone number
+func (s *OrganisationService) GetInvitationsForPhone(ctx context.Context, phone string) ([]models.Invitation, error) {
+	return s.orgRepo.GetPendingInvitationsByPhone(ctx, phone)
+}
+
+// CancelInvitation withdraws an open invitation
+func (s *OrganisationService) CancelInvitation(ctx context.Context, orgID, invitationID uuid.UUID) error {
+	return s.orgRepo.DeleteInvitation(ctx, orgID, invitationID)
+}
+
+// AcceptInvitation adds the user to the organisation they were invited to.
+// The invitation must be addressed to the user's own phone number.
+func (s *OrganisationService) AcceptInvitation(ctx context.Context, invitationID, userID uuid.UUID, phone string) (uuid.UUID, error) {
+	return s.orgRepo.AcceptInvitation(ctx, invitationID, userID, phone)
+}
+
+// organisationFor returns the organisation a new project or labour should
+// belong to. A requested organisation must grant perm to the user; otherwise
+// the user's own organisation is used, created on first use.
+func organisationFor(ctx context.Context, orgRepo *repository.OrganisationRepository, userID uuid.UUID, requested *uuid.UUID, perm models.Permission) (uuid.UUID, error) {
+	if requested != nil {
+		role, err := orgRepo.GetRole(ctx, *requested, userID)
+		if err != nil {
+			return uuid.Nil, err
+		}
+		if !role.Can(perm) {
+			return uuid.Nil, models.ErrForbidden
+		}
+		return *requested, nil
+	}
+
+	org, err := orgRepo.GetDefaultForUser(ctx, userID)
+	if err == nil {
+		return org.ID, nil
+	}
+	if !errors.Is(err, models.ErrNotFound) {
+		return uuid.Nil, err
+	}
+
+	org = &models.Organisation{Name: "My Organisation"}
+	created, err := orgRepo.CreatePersonal(ctx, org, userID)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	if !created {
+		// A concurrent request created it first
+		org, err = orgRepo.GetDefaultForUser(ctx, userID)
+		if err != nil {
+			return uuid.Nil, err
+		}
+	}
+
+	return org.ID, nil
+}
@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
 	"github.com/vivekanand/labour-thekedar-backend/internal/models"
 	"github.com/vivekanand/labour-thekedar-backend/internal/repository"
 )
@@ -13,13 +14,17 @@ import (
 type PaymentService struct {
 	paymentRepo *repository.PaymentRepository
 	labourRepo  *repository.LabourRepository
+	projectRepo *repository.ProjectRepository
+	audit       *AuditService
 }
 
 // NewPaymentService creates a new PaymentService
-func NewPaymentService(paymentRepo *repository.PaymentRepository, labourRepo *repository.LabourRepository) *PaymentService {
+func NewPaymentService(paymentRepo *repository.PaymentRepository, labourRepo *repository.LabourRepository, projectRepo *repository.ProjectRepository, audit *AuditService) *PaymentService {
 	return &PaymentService{
 		paymentRepo: paymentRepo,
 		labourRepo:  labourRepo,
+		projectRepo: projectRepo,
+		audit:       audit,
 	}
 }
 
@@ -31,6 +36,10 @@ func (s *PaymentService) Create(ctx context.Context, projectID uuid.UUID, req *m
 		return nil, models.ErrInvalidDate
 	}
 
+	if err := ensurePeriodOpen(ctx, s.projectRepo, projectID, paymentDate); err != nil {
+		return nil, err
+	}
+
 	// Verify labour is assigned to project
 	isAssigned, err := s.labourRepo.IsAssignedToProject(ctx, projectID, req.LabourID)
 	if err != nil {
@@ -41,19 +50,33 @@ func (s *PaymentService) Create(ctx context.Context, projectID uuid.UUID, req *m
 	}
 
 	payment := &models.Payment{
-		ProjectID:   projectID,
-		LabourID:    req.LabourID,
-		Amount:      req.Amount,
-		PaymentDate: paymentDate,
-		PaymentType: req.PaymentType,
-		Notes:       req.Notes,
+		ProjectID:     projectID,
+		LabourID:      req.LabourID,
+		Amount:        req.Amount,
+		PaymentDate:   paymentDate,
+		PaymentType:   req.PaymentType,
+		Notes:         req.Notes,
+		RecoveryType:  req.RecoveryType,
+		RecoveryValue: req.RecoveryValue,
 	}
 
 	if err := payment.Validate(); err != nil {
 		return nil, err
 	}
 
-	if err := s.paymentRepo.Create(ctx, payment); err != nil {
+	err = s.audit.InTx(ctx, func(ctx context.Context) error {
+		if err := s.paymentRepo.Create(ctx, payment); err != nil {
+			return err
+		}
+		return s.audit.Record(ctx, &models.AuditLog{
+			EntityType: models.AuditEntityPayment,
+			EntityID:   payment.ID,
+			ProjectID:  &payment.ProjectID,
+			LabourID:   &payment.LabourID,
+			Action:     models.AuditActionCreate,
+		}, nil, payment)
+	})
+	if err != nil {
 		return nil, err
 	}
 
@@ -80,7 +103,222 @@ func (s *PaymentService) GetBalance(ctx context.Context, projectID, labourID uui
 	return s.paymentRepo.GetBalance(ctx, projectID, labourID)
 }
 
-// Delete deletes a payment record
-func (s *PaymentService) Delete(ctx context.Context, id uuid.UUID) error {
-	return s.paymentRepo.Delete(ctx, id)
+// GetProjectBalances builds the balance sheet of every labour assigned to a project
+func (s *PaymentService) GetProjectBalances(ctx context.Context, project *models.Project) (*models.ProjectBalanceSheet, error) {
+	labours, err := s.paymentRepo.GetProjectBalances(ctx, project.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return models.NewProjectBalanceSheet(project, labours), nil
+}
+
+// GetLedger builds a labour's running-balance statement across the given
+// projects. from and to are optional dates in YYYY-MM-DD format; entries
+// before from are carried into the opening balance.
+func (s *PaymentService) GetLedger(ctx context.Context, labour *models.Labour, projects []models.Project, fromStr, toStr string) (*models.Ledger, error) {
+	from, err := parseOptionalDate(fromStr)
+	if err != nil {
+		return nil, err
+	}
+	to, err := parseOptionalDate(toStr)
+	if err != nil {
+		return nil, err
+	}
+	if from != nil && to != nil && to.Before(*from) {
+		return nil, models.ErrInvalidDate
+	}
+
+	projectIDs := make([]uuid.UUID, len(projects))
+	for i, p := range projects {
+		projectIDs[i] = p.ID
+	}
+
+	opening := map[uuid.UUID]decimal.Decimal{}
+	if from != nil {
+		opening, err = s.paymentRepo.GetBalancesBefore(ctx, labour.ID, projectIDs, *from)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	entries, err := s.paymentRepo.GetLedgerEntries(ctx, labour.ID, projectIDs, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	ledger := models.NewLedger(labour, projects, opening, entries)
+	ledger.From = from
+	ledger.To = to
+
+	return ledger, nil
+}
+
+// GetAdvances summarises a labour's advances on the given projects with
+// what is still outstanding
+func (s *PaymentService) GetAdvances(ctx context.Context, labour *models.Labour, projects []models.Project) (*models.AdvanceSummary, error) {
+	projectIDs := make([]uuid.UUID, len(projects))
+	for i, p := range projects {
+		projectIDs[i] = p.ID
+	}
+
+	advances, err := s.paymentRepo.GetAdvances(ctx, labour.ID, projectIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	return models.NewAdvanceSummary(labour, advances), nil
+}
+
+// SetRecovery sets or clears the recovery plan of an advance. The plan
+// applies to payroll runs created afterwards.
+func (s *PaymentService) SetRecovery(ctx context.Context, payment *models.Payment, req *models.SetRecoveryPlanRequest) (*models.Payment, error) {
+	if payment.IsVoided() {
+		return nil, models.ErrPaymentVoided
+	}
+
+	before := *payment
+	payment.RecoveryType = req.RecoveryType
+	payment.RecoveryValue = req.RecoveryValue
+
+	if err := payment.Validate(); err != nil {
+		return nil, err
+	}
+
+	err := s.audit.InTx(ctx, func(ctx context.Context) error {
+		if err := s.paymentRepo.UpdateRecovery(ctx, payment); err != nil {
+			return err
+		}
+		return s.audit.Record(ctx, &models.AuditLog{
+			EntityType: models.AuditEntityPayment,
+			EntityID:   payment.ID,
+			ProjectID:  &payment.ProjectID,
+			LabourID:   &payment.LabourID,
+			Action:     models.AuditActionUpdate,
+		}, before, payment)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return payment, nil
+}
+
+// Update corrects a payment. Both the old and the new date must be in an
+// open period, and an advance can't drop below what payroll has recovered.
+func (s *PaymentService) Update(ctx context.Context, payment *models.Payment, req *models.UpdatePaymentRequest) (*models.Payment, error) {
+	if payment.IsVoided() {
+		return nil, models.ErrPaymentVoided
+	}
+
+	paymentDate, err := time.Parse("2006-01-02", req.PaymentDate)
+	if err != nil {
+		return nil, models.ErrInvalidDate
+	}
+
+	if err := ensurePeriodOpen(ctx, s.projectRepo, payment.ProjectID, payment.PaymentDate); err != nil {
+		return nil, err
+	}
+	if err := ensurePeriodOpen(ctx, s.projectRepo, payment.ProjectID, paymentDate); err != nil {
+		return nil, err
+	}
+
+	if payment.PaymentType == models.PaymentTypeAdvance {
+		recovered, err := s.paymentRepo.GetRecovered(ctx, payment.ID)
+		if err != nil {
+			return nil, err
+		}
+		if recovered.IsPositive() && (req.PaymentType != models.PaymentTypeAdvance || req.Amount.LessThan(recovered)) {
+			return nil, models.ErrAdvanceRecovered
+		}
+	}
+
+	before := *payment
+	payment.Amount = req.Amount
+	payment.PaymentDate = paymentDate
+	payment.PaymentType = req.PaymentType
+	payment.Notes = req.Notes
+	if payment.PaymentType != models.PaymentTypeAdvance {
+		payment.RecoveryType = nil
+		payment.RecoveryValue = nil
+	}
+
+	if err := payment.Validate(); err != nil {
+		return nil, err
+	}
+
+	err = s.audit.InTx(ctx, func(ctx context.Context) error {
+		if err := s.paymentRepo.Update(ctx, payment); err != nil {
+			return err
+		}
+		return s.audit.Record(ctx, &models.AuditLog{
+			EntityType: models.AuditEntityPayment,
+			EntityID:   payment.ID,
+			ProjectID:  &payment.ProjectID,
+			LabourID:   &payment.LabourID,
+			Action:     models.AuditActionUpdate,
+		}, before, payment)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return payment, nil
+}
+
+// Void voids a payment so it no longer counts towards balances. Advances
+// that payroll has started recovering can't be voided.
+func (s *PaymentService) Void(ctx context.Context, payment *models.Payment, userID uuid.UUID, req *models.VoidPaymentRequest) (*models.Payment, error) {
+	if payment.IsVoided() {
+		return nil, models.ErrPaymentVoided
+	}
+
+	if err := ensurePeriodOpen(ctx, s.projectRepo, payment.ProjectID, payment.PaymentDate); err != nil {
+		return nil, err
+	}
+
+	if payment.PaymentType == models.PaymentTypeAdvance {
+		recovered, err := s.paymentRepo.GetRecovered(ctx, payment.ID)
+		if err != nil {
+			return nil, err
+		}
+		if recovered.IsPositive() {
+			return nil, models.ErrAdvanceRecovered
+		}
+	}
+
+	before := *payment
+	payment.VoidedBy = &userID
+	payment.VoidReason = req.Reason
+	err := s.audit.InTx(ctx, func(ctx context.Context) error {
+		if err := s.paymentRepo.Void(ctx, payment); err != nil {
+			return err
+		}
+		return s.audit.Record(ctx, &models.AuditLog{
+			EntityType: models.AuditEntityPayment,
+			EntityID:   payment.ID,
+			ProjectID:  &payment.ProjectID,
+			LabourID:   &payment.LabourID,
+			Action:     models.AuditActionVoid,
+		}, before, payment)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return payment, nil
+}
+
+// parseOptionalDate parses a YYYY-MM-DD date, returning nil for an empty string
+func parseOptionalDate(s string) (*time.Time, error) {
+	if s == "" {
+		return nil, nil
+	}
+
+	date, err := time.Parse("2006-01-02", s)
+	if err != nil {
+		return nil, models.ErrInvalidDate
+	}
+
+	return &date, nil
 }
@@ -0,0 +1,289 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/vivekanand/labour-thekedar-backend/internal/models"
+	"github.com/vivekanand/labour-thekedar-backend/internal/repository"
+)
+
+// PayrollService handles payroll run business logic
+type PayrollService struct {
+	payrollRepo *repository.PayrollRepository
+	projectRepo *repository.ProjectRepository
+	orgRepo     *repository.OrganisationRepository
+	audit       *AuditService
+}
+
+// NewPayrollService creates a new PayrollService
+func NewPayrollService(payrollRepo *repository.PayrollRepository, projectRepo *repository.ProjectRepository, orgRepo *repository.OrganisationRepository, audit *AuditService) *PayrollService {
+	return &PayrollService{
+		payrollRepo: payrollRepo,
+		projectRepo: projectRepo,
+		orgRepo:     orgRepo,
+		audit:       audit,
+	}
+}
+
+// Create drafts a payroll run for a project, or for every project in an
+// organisation. The caller must already be authorized on the project; for
+// organisation-wide runs the organisation is resolved and checked here.
+func (s *PayrollService) Create(ctx context.Context, userID uuid.UUID, req *models.CreatePayrollRunRequest) (*models.PayrollRunWithItems, error) {
+	start, err := time.Parse("2006-01-02", req.PeriodStart)
+	if err != nil {
+		return nil, models.ErrInvalidDate
+	}
+	end, err := time.Parse("2006-01-02", req.PeriodEnd)
+	if err != nil {
+		return nil, models.ErrInvalidDate
+	}
+	if end.Before(start) {
+		return nil, models.ErrInvalidDate
+	}
+
+	run := &models.PayrollRun{
+		ProjectID:   req.ProjectID,
+		PeriodStart: start,
+		PeriodEnd:   end,
+		CreatedBy:   &userID,
+	}
+
+	if req.ProjectID != nil {
+		project, err := s.projectRepo.GetByID(ctx, *req.ProjectID)
+		if err != nil {
+			return nil, err
+		}
+		if !project.Status.IsOpen() {
+			return nil, models.ErrProjectInactive
+		}
+		run.OrganisationID = project.OrganisationID
+	} else {
+		run.OrganisationID, err = organisationFor(ctx, s.orgRepo, userID, req.OrganisationID, models.PermPaymentsWrite)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var created *models.PayrollRunWithItems
+	err = s.audit.InTx(ctx, func(ctx context.Context) error {
+		if err := s.payrollRepo.Create(ctx, run); err != nil {
+			return err
+		}
+
+		var err error
+		created, err = s.GetByID(ctx, run.ID)
+		if err != nil {
+			return err
+		}
+		return s.audit.Record(ctx, &models.AuditLog{
+			EntityType: models.AuditEntityPayrollRun,
+			EntityID:   run.ID,
+			ProjectID:  run.ProjectID,
+			Action:     models.AuditActionCreate,
+		}, nil, created)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return created, nil
+}
+
+// GetByID retrieves a payroll run with its items
+func (s *PayrollService) GetByID(ctx context.Context, id uuid.UUID) (*models.PayrollRunWithItems, error) {
+	run, err := s.payrollRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	items, err := s.payrollRepo.GetItems(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.PayrollRunWithItems{
+		PayrollRun: *run,
+		Items:      items,
+	}, nil
+}
+
+// GetRun retrieves a payroll run without its items
+func (s *PayrollService) GetRun(ctx context.Context, id uuid.UUID) (*models.PayrollRun, error) {
+	return s.payrollRepo.GetByID(ctx, id)
+}
+
+// GetByOrganisationID retrieves every payroll run in an organisation
+func (s *PayrollService) GetByOrganisationID(ctx context.Context, orgID uuid.UUID) ([]models.PayrollRun, error) {
+	return s.payrollRepo.GetByOrganisationID(ctx, orgID)
+}
+
+// GetByProjectID retrieves the payroll runs for a single project
+func (s *PayrollService) GetByProjectID(ctx context.Context, projectID uuid.UUID) ([]models.PayrollRun, error) {
+	return s.payrollRepo.GetByProjectID(ctx, projectID)
+}
+
+// UpdateItem edits the deduction, adjustment and notes of a draft item
+func (s *PayrollService) UpdateItem(ctx context.Context, runID, itemID uuid.UUID, req *models.UpdatePayrollItemRequest) (*models.PayrollItem, error) {
+	item, err := s.payrollRepo.GetItem(ctx, runID, itemID)
+	if err != nil {
+		return nil, err
+	}
+
+	before := *item
+	item.AdvanceDeduction = req.AdvanceDeduction
+	item.Adjustment = req.Adjustment
+	item.Notes = req.Notes
+
+	outstanding, err := s.payrollRepo.GetAdvanceOutstanding(ctx, item)
+	if err != nil {
+		return nil, err
+	}
+	if err := item.Recalculate(outstanding); err != nil {
+		return nil, err
+	}
+
+	err = s.audit.InTx(ctx, func(ctx context.Context) error {
+		if err := s.payrollRepo.UpdateItem(ctx, item); err != nil {
+			return err
+		}
+		return s.audit.Record(ctx, &models.AuditLog{
+			EntityType: models.AuditEntityPayrollItem,
+			EntityID:   item.ID,
+			ProjectID:  &item.ProjectID,
+			LabourID:   &item.LabourID,
+			Action:     models.AuditActionUpdate,
+		}, before, item)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return item, nil
+}
+
+// DeleteItem removes a labour from a draft payroll run
+func (s *PayrollService) DeleteItem(ctx context.Context, runID, itemID uuid.UUID) error {
+	item, err := s.payrollRepo.GetItem(ctx, runID, itemID)
+	if err != nil {
+		return err
+	}
+
+	err = s.audit.InTx(ctx, func(ctx context.Context) error {
+		if err := s.payrollRepo.DeleteItem(ctx, runID, itemID); err != nil {
+			return err
+		}
+		return s.audit.Record(ctx, &models.AuditLog{
+			EntityType: models.AuditEntityPayrollItem,
+			EntityID:   item.ID,
+			ProjectID:  &item.ProjectID,
+			LabourID:   &item.LabourID,
+			Action:     models.AuditActionDelete,
+		}, item, nil)
+	})
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Delete discards a draft payroll run
+func (s *PayrollService) Delete(ctx context.Context, id uuid.UUID) error {
+	run, err := s.payrollRepo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	err = s.audit.InTx(ctx, func(ctx context.Context) error {
+		if err := s.payrollRepo.Delete(ctx, id); err != nil {
+			return err
+		}
+		return s.audit.Record(ctx, &models.AuditLog{
+			EntityType: models.AuditEntityPayrollRun,
+			EntityID:   run.ID,
+			ProjectID:  run.ProjectID,
+			Action:     models.AuditActionDelete,
+		}, run, nil)
+	})
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Approve approves a draft payroll run, paying every item and locking the
+// period. The payment date defaults to the end of the period.
+func (s *PayrollService) Approve(ctx context.Context, id, userID uuid.UUID, req *models.ApprovePayrollRunRequest) (*models.PayrollRunWithItems, error) {
+	run, err := s.payrollRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	paymentDate := run.PeriodEnd
+	if req.PaymentDate != "" {
+		paymentDate, err = time.Parse("2006-01-02", req.PaymentDate)
+		if err != nil {
+			return nil, models.ErrInvalidDate
+		}
+	}
+
+	var approved *models.PayrollRunWithItems
+	err = s.audit.InTx(ctx, func(ctx context.Context) error {
+		payments, recoveries, err := s.payrollRepo.Approve(ctx, id, userID, paymentDate)
+		if err != nil {
+			return err
+		}
+
+		approved, err = s.GetByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		err = s.audit.Record(ctx, &models.AuditLog{
+			EntityType: models.AuditEntityPayrollRun,
+			EntityID:   run.ID,
+			ProjectID:  run.ProjectID,
+			Action:     models.AuditActionApprove,
+		}, run, &approved.PayrollRun)
+		if err != nil {
+			return err
+		}
+
+		// Each payment and recovery is recorded against its labour too, so
+		// it shows in the labour's history
+		for i := range payments {
+			payment := &payments[i]
+			err := s.audit.Record(ctx, &models.AuditLog{
+				EntityType: models.AuditEntityPayment,
+				EntityID:   payment.ID,
+				ProjectID:  &payment.ProjectID,
+				LabourID:   &payment.LabourID,
+				Action:     models.AuditActionCreate,
+			}, nil, payment)
+			if err != nil {
+				return err
+			}
+		}
+		for i := range recoveries {
+			recovery := &recoveries[i]
+			err := s.audit.Record(ctx, &models.AuditLog{
+				EntityType: models.AuditEntityRecovery,
+				EntityID:   recovery.ID,
+				ProjectID:  &recovery.ProjectID,
+				LabourID:   &recovery.LabourID,
+				Action:     models.AuditActionCreate,
+			}, nil, recovery)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return approved, nil
+}
@@ -2,6 +2,9 @@ package service
 
 import (
 	"context"
+	"errors"
+	"strings"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/vivekanand/labour-thekedar-backend/internal/models"
@@ -12,29 +15,66 @@ import (
 type ProjectService struct {
 	projectRepo *repository.ProjectRepository
 	labourRepo  *repository.LabourRepository
+	orgRepo     *repository.OrganisationRepository
+	userRepo    *repository.UserRepository
+	audit       *AuditService
 }
 
 // NewProjectService creates a new ProjectService
-func NewProjectService(projectRepo *repository.ProjectRepository, labourRepo *repository.LabourRepository) *ProjectService {
+func NewProjectService(projectRepo *repository.ProjectRepository, labourRepo *repository.LabourRepository, orgRepo *repository.OrganisationRepository, userRepo *repository.UserRepository, audit *AuditService) *ProjectService {
 	return &ProjectService{
 		projectRepo: projectRepo,
 		labourRepo:  labourRepo,
+		orgRepo:     orgRepo,
+		userRepo:    userRepo,
+		audit:       audit,
 	}
 }
 
-// Create creates a new project
+// Create creates a new project in the requested organisation, or the user's own
 func (s *ProjectService) Create(ctx context.Context, userID uuid.UUID, req *models.CreateProjectRequest) (*models.Project, error) {
+	orgID, err := organisationFor(ctx, s.orgRepo, userID, req.OrganisationID, models.PermProjectsWrite)
+	if err != nil {
+		return nil, err
+	}
+
 	project := &models.Project{
-		UserID:      userID,
-		Name:        req.Name,
-		Description: req.Description,
+		UserID:         userID,
+		OrganisationID: orgID,
+		Name:           req.Name,
+		Description:    req.Description,
+		Status:         req.Status,
+		SiteAddress:    req.SiteAddress,
+		ClientName:     req.ClientName,
+		LabourBudget:   req.LabourBudget,
+		OvertimeRate:   req.OvertimeRate,
+	}
+	if project.Status == "" {
+		project.Status = models.ProjectStatusActive
+	}
+	if project.StartDate, err = parseOptionalDate(req.StartDate); err != nil {
+		return nil, err
+	}
+	if project.EndDate, err = parseOptionalDate(req.EndDate); err != nil {
+		return nil, err
 	}
 
 	if err := project.Validate(); err != nil {
 		return nil, err
 	}
 
-	if err := s.projectRepo.Create(ctx, project); err != nil {
+	err = s.audit.InTx(ctx, func(ctx context.Context) error {
+		if err := s.projectRepo.Create(ctx, project); err != nil {
+			return err
+		}
+		return s.audit.Record(ctx, &models.AuditLog{
+			EntityType: models.AuditEntityProject,
+			EntityID:   project.ID,
+			ProjectID:  &project.ID,
+			Action:     models.AuditActionCreate,
+		}, nil, project)
+	})
+	if err != nil {
 		return nil, err
 	}
 
@@ -64,9 +104,14 @@ func (s *ProjectService) GetByIDWithLabours(ctx context.Context, id uuid.UUID) (
 	}, nil
 }
 
-// GetByUserID retrieves all projects for a user
-func (s *ProjectService) GetByUserID(ctx context.Context, userID uuid.UUID) ([]models.Project, error) {
-	return s.projectRepo.GetByUserID(ctx, userID)
+// GetByUserID retrieves the projects visible to a user, filtered by status
+func (s *ProjectService) GetByUserID(ctx context.Context, userID uuid.UUID, query *models.ProjectQuery) ([]models.Project, error) {
+	statuses, err := query.Statuses()
+	if err != nil {
+		return nil, err
+	}
+
+	return s.projectRepo.GetByUserID(ctx, userID, statuses)
 }
 
 // Update updates a project
@@ -76,26 +121,314 @@ func (s *ProjectService) Update(ctx context.Context, id uuid.UUID, req *models.U
 		return nil, err
 	}
 
-	project.Name = req.Name
-	project.Description = req.Description
+	before := *project
+	if err := req.Apply(project); err != nil {
+		return nil, err
+	}
 
 	if err := project.Validate(); err != nil {
 		return nil, err
 	}
 
-	if err := s.projectRepo.Update(ctx, project); err != nil {
+	err = s.audit.InTx(ctx, func(ctx context.Context) error {
+		if err := s.projectRepo.Update(ctx, project); err != nil {
+			return err
+		}
+		return s.audit.Record(ctx, &models.AuditLog{
+			EntityType: models.AuditEntityProject,
+			EntityID:   project.ID,
+			ProjectID:  &project.ID,
+			Action:     models.AuditActionUpdate,
+		}, before, project)
+	})
+	if err != nil {
 		return nil, err
 	}
 
 	return project, nil
 }
 
-// Delete deletes a project
+// Delete moves a project to the trash
 func (s *ProjectService) Delete(ctx context.Context, id uuid.UUID) error {
-	return s.projectRepo.Delete(ctx, id)
+	project, err := s.projectRepo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	before := *project
+	project.DeletedBy = actorFrom(ctx)
+	err = s.audit.InTx(ctx, func(ctx context.Context) error {
+		if err := s.projectRepo.Delete(ctx, project); err != nil {
+			return err
+		}
+		return s.audit.Record(ctx, &models.AuditLog{
+			EntityType: models.AuditEntityProject,
+			EntityID:   project.ID,
+			ProjectID:  &project.ID,
+			Action:     models.AuditActionDelete,
+		}, before, project)
+	})
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// GetDeletedByID retrieves a project in the trash
+func (s *ProjectService) GetDeletedByID(ctx context.Context, id uuid.UUID) (*models.Project, error) {
+	return s.projectRepo.GetDeletedByID(ctx, id)
+}
+
+// GetTrash retrieves the trashed projects visible to a user
+func (s *ProjectService) GetTrash(ctx context.Context, userID uuid.UUID) ([]models.Project, error) {
+	return s.projectRepo.GetDeletedByUserID(ctx, userID)
+}
+
+// Restore takes a project out of the trash
+func (s *ProjectService) Restore(ctx context.Context, id uuid.UUID) (*models.Project, error) {
+	project, err := s.projectRepo.GetDeletedByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	before := *project
+	err = s.audit.InTx(ctx, func(ctx context.Context) error {
+		if err := s.projectRepo.Restore(ctx, project); err != nil {
+			return err
+		}
+		return s.audit.Record(ctx, &models.AuditLog{
+			EntityType: models.AuditEntityProject,
+			EntityID:   project.ID,
+			ProjectID:  &project.ID,
+			Action:     models.AuditActionRestore,
+		}, before, project)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return project, nil
+}
+
+// Purge permanently deletes a trashed project and everything recorded
+// against it. The request must repeat the project's name.
+func (s *ProjectService) Purge(ctx context.Context, id uuid.UUID, req *models.PurgeRequest) error {
+	project, err := s.projectRepo.GetDeletedByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if strings.TrimSpace(req.Confirm) != project.Name {
+		return models.ErrPurgeNotConfirmed
+	}
+
+	err = s.audit.InTx(ctx, func(ctx context.Context) error {
+		if err := s.projectRepo.Purge(ctx, id); err != nil {
+			return err
+		}
+		return s.audit.Record(ctx, &models.AuditLog{
+			EntityType: models.AuditEntityProject,
+			EntityID:   project.ID,
+			ProjectID:  &project.ID,
+			Action:     models.AuditActionPurge,
+		}, project, nil)
+	})
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Authorize checks if a user's role in the project's organisation, or their
+// capabilities as a member of the project, grant the permission
+func (s *ProjectService) Authorize(ctx context.Context, projectID, userID uuid.UUID, perm models.Permission) (bool, error) {
+	role, err := s.orgRepo.GetRoleForProject(ctx, projectID, userID)
+	if err != nil {
+		return false, err
+	}
+	if role.Can(perm) {
+		return true, nil
+	}
+
+	member, err := s.projectRepo.GetMember(ctx, projectID, userID)
+	if err != nil {
+		if errors.Is(err, models.ErrNotFound) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	return member.Can(perm), nil
 }
 
-// IsOwner checks if a user owns a project
-func (s *ProjectService) IsOwner(ctx context.Context, projectID, userID uuid.UUID) (bool, error) {
-	return s.projectRepo.IsOwner(ctx, projectID, userID)
+// GetAccessibleForLabour retrieves the projects a labour has worked on that
+// grant the user the permission
+func (s *ProjectService) GetAccessibleForLabour(ctx context.Context, labourID, userID uuid.UUID, perm models.Permission) ([]models.Project, error) {
+	projects, err := s.projectRepo.GetByLabourID(ctx, labourID)
+	if err != nil {
+		return nil, err
+	}
+
+	accessible := make([]models.Project, 0, len(projects))
+	for _, p := range projects {
+		allowed, err := s.Authorize(ctx, p.ID, userID, perm)
+		if err != nil {
+			return nil, err
+		}
+		if allowed {
+			accessible = append(accessible, p)
+		}
+	}
+
+	return accessible, nil
+}
+
+// AddMember grants capabilities on a project to the user with the given
+// phone, creating the user if they have never logged in
+func (s *ProjectService) AddMember(ctx context.Context, projectID uuid.UUID, req *models.AddProjectMemberRequest) (*models.ProjectMember, error) {
+	member := &models.ProjectMember{
+		ProjectID:    projectID,
+		Phone:        req.Phone,
+		Capabilities: req.Capabilities,
+	}
+
+	if err := member.Validate(); err != nil {
+		return nil, err
+	}
+
+	user, _, err := s.userRepo.GetOrCreate(ctx, req.Phone)
+	if err != nil {
+		return nil, err
+	}
+	member.UserID = user.ID
+	member.Name = user.Name
+
+	err = s.audit.InTx(ctx, func(ctx context.Context) error {
+		if err := s.projectRepo.UpsertMember(ctx, member); err != nil {
+			return err
+		}
+		return s.audit.Record(ctx, &models.AuditLog{
+			EntityType: models.AuditEntityProjectMember,
+			EntityID:   member.UserID,
+			ProjectID:  &projectID,
+			Action:     models.AuditActionAssign,
+		}, nil, member)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return member, nil
+}
+
+// GetMembers retrieves the members of a project
+func (s *ProjectService) GetMembers(ctx context.Context, projectID uuid.UUID) ([]models.ProjectMember, error) {
+	return s.projectRepo.GetMembers(ctx, projectID)
+}
+
+// RemoveMember removes a user from a project
+func (s *ProjectService) RemoveMember(ctx context.Context, projectID, userID uuid.UUID) error {
+	err := s.audit.InTx(ctx, func(ctx context.Context) error {
+		if err := s.projectRepo.RemoveMember(ctx, projectID, userID); err != nil {
+			return err
+		}
+		return s.audit.Record(ctx, &models.AuditLog{
+			EntityType: models.AuditEntityProjectMember,
+			EntityID:   userID,
+			ProjectID:  &projectID,
+			Action:     models.AuditActionUnassign,
+		}, nil, nil)
+	})
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// ClosePeriod locks attendance and payments on the project up to and
+// including the given date
+func (s *ProjectService) ClosePeriod(ctx context.Context, projectID, userID uuid.UUID, req *models.ClosePeriodRequest) (*models.PeriodEvent, error) {
+	closedThrough, err := time.Parse("2006-01-02", req.ClosedThrough)
+	if err != nil {
+		return nil, models.ErrInvalidDate
+	}
+
+	event := &models.PeriodEvent{
+		ProjectID:     projectID,
+		Action:        models.PeriodActionClose,
+		ClosedThrough: &closedThrough,
+		UserID:        &userID,
+	}
+	err = s.audit.InTx(ctx, func(ctx context.Context) error {
+		if err := s.projectRepo.UpdateClosedThrough(ctx, event); err != nil {
+			return err
+		}
+		return s.audit.Record(ctx, &models.AuditLog{
+			EntityType: models.AuditEntityPeriod,
+			EntityID:   event.ID,
+			ProjectID:  &projectID,
+			Action:     models.AuditActionClose,
+		}, nil, event)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return event, nil
+}
+
+// ReopenPeriod moves the project's closed-through date back, or clears it
+// when no date is given. The reason is kept in the period history.
+func (s *ProjectService) ReopenPeriod(ctx context.Context, projectID, userID uuid.UUID, req *models.ReopenPeriodRequest) (*models.PeriodEvent, error) {
+	closedThrough, err := parseOptionalDate(req.ClosedThrough)
+	if err != nil {
+		return nil, err
+	}
+
+	event := &models.PeriodEvent{
+		ProjectID:     projectID,
+		Action:        models.PeriodActionReopen,
+		ClosedThrough: closedThrough,
+		Reason:        req.Reason,
+		UserID:        &userID,
+	}
+	err = s.audit.InTx(ctx, func(ctx context.Context) error {
+		if err := s.projectRepo.UpdateClosedThrough(ctx, event); err != nil {
+			return err
+		}
+		return s.audit.Record(ctx, &models.AuditLog{
+			EntityType: models.AuditEntityPeriod,
+			EntityID:   event.ID,
+			ProjectID:  &projectID,
+			Action:     models.AuditActionReopen,
+		}, nil, event)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return event, nil
+}
+
+// GetPeriodEvents retrieves the project's close and reopen history
+func (s *ProjectService) GetPeriodEvents(ctx context.Context, projectID uuid.UUID) ([]models.PeriodEvent, error) {
+	return s.projectRepo.GetPeriodEvents(ctx, projectID)
+}
+
+// ensurePeriodOpen returns ErrProjectInactive when the project is completed
+// or archived, and ErrPeriodLocked when it is closed on the given date
+func ensurePeriodOpen(ctx context.Context, projectRepo *repository.ProjectRepository, projectID uuid.UUID, date time.Time) error {
+	project, err := projectRepo.GetByID(ctx, projectID)
+	if err != nil {
+		return err
+	}
+	if !project.Status.IsOpen() {
+		return models.ErrProjectInactive
+	}
+	if project.IsClosed(date) {
+		return models.ErrPeriodLocked
+	}
+	return nil
 }
@@ -0,0 +1,63 @@
+package service
+
+import (
+	"context"
+
+	"github.com/shopspring/decimal"
+	"github.com/vivekanand/labour-thekedar-backend/internal/models"
+	"github.com/vivekanand/labour-thekedar-backend/internal/repository"
+)
+
+// SelfService handles a labour's read-only view of their own records. A
+// labour is identified by phone and may have a record in several
+// organisations; every record with the phone is included.
+type SelfService struct {
+	labourRepo  *repository.LabourRepository
+	workDayRepo *repository.WorkDayRepository
+	paymentRepo *repository.PaymentRepository
+}
+
+// NewSelfService creates a new SelfService
+func NewSelfService(labourRepo *repository.LabourRepository, workDayRepo *repository.WorkDayRepository, paymentRepo *repository.PaymentRepository) *SelfService {
+	return &SelfService{
+		labourRepo:  labourRepo,
+		workDayRepo: workDayRepo,
+		paymentRepo: paymentRepo,
+	}
+}
+
+// GetProfiles retrieves the labour records registered with the phone
+func (s *SelfService) GetProfiles(ctx context.Context, phone string) ([]models.Labour, error) {
+	return s.labourRepo.GetByPhone(ctx, phone)
+}
+
+// GetWorkDays retrieves the labour's work days across all projects
+func (s *SelfService) GetWorkDays(ctx context.Context, phone string) ([]models.LabourWorkDay, error) {
+	return s.workDayRepo.GetByLabourPhone(ctx, phone)
+}
+
+// GetPayments retrieves the labour's payments across all projects
+func (s *SelfService) GetPayments(ctx context.Context, phone string) ([]models.PaymentWithProject, error) {
+	return s.paymentRepo.GetByLabourPhone(ctx, phone)
+}
+
+// GetBalance calculates the labour's balance on each project and overall
+func (s *SelfService) GetBalance(ctx context.Context, phone string) (*models.CombinedBalanceResponse, error) {
+	balances, err := s.paymentRepo.GetBalancesByLabourPhone(ctx, phone)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &models.CombinedBalanceResponse{
+		Projects:    balances,
+		TotalEarned: decimal.Zero,
+		TotalPaid:   decimal.Zero,
+	}
+	for _, b := range balances {
+		response.TotalEarned = response.TotalEarned.Add(b.TotalEarned)
+		response.TotalPaid = response.TotalPaid.Add(b.TotalPaid)
+	}
+	response.Balance = response.TotalEarned.Sub(response.TotalPaid)
+
+	return response, nil
+}
@@ -13,13 +13,19 @@ import (
 type WorkDayService struct {
 	workDayRepo *repository.WorkDayRepository
 	labourRepo  *repository.LabourRepository
+	projectRepo *repository.ProjectRepository
+	budget      *BudgetService
+	audit       *AuditService
 }
 
 // NewWorkDayService creates a new WorkDayService
-func NewWorkDayService(workDayRepo *repository.WorkDayRepository, labourRepo *repository.LabourRepository) *WorkDayService {
+func NewWorkDayService(workDayRepo *repository.WorkDayRepository, labourRepo *repository.LabourRepository, projectRepo *repository.ProjectRepository, budget *BudgetService, audit *AuditService) *WorkDayService {
 	return &WorkDayService{
 		workDayRepo: workDayRepo,
 		labourRepo:  labourRepo,
+		projectRepo: projectRepo,
+		budget:      budget,
+		audit:       audit,
 	}
 }
 
@@ -31,6 +37,10 @@ func (s *WorkDayService) Create(ctx context.Context, projectID uuid.UUID, req *m
 		return nil, models.ErrInvalidDate
 	}
 
+	if err := ensurePeriodOpen(ctx, s.projectRepo, projectID, workDate); err != nil {
+		return nil, err
+	}
+
 	// Verify labour is assigned to project
 	isAssigned, err := s.labourRepo.IsAssignedToProject(ctx, projectID, req.LabourID)
 	if err != nil {
@@ -41,24 +51,171 @@ func (s *WorkDayService) Create(ctx context.Context, projectID uuid.UUID, req *m
 	}
 
 	workDay := &models.WorkDay{
-		ProjectID: projectID,
-		LabourID:  req.LabourID,
-		WorkDate:  workDate,
-		Status:    req.Status,
-		Notes:     req.Notes,
+		ProjectID:     projectID,
+		LabourID:      req.LabourID,
+		WorkDate:      workDate,
+		Status:        req.Status,
+		OvertimeHours: req.OvertimeHours,
+		CheckIn:       req.CheckIn,
+		CheckOut:      req.CheckOut,
+		Notes:         req.Notes,
 	}
 
 	if err := workDay.Validate(); err != nil {
 		return nil, err
 	}
 
-	if err := s.workDayRepo.Create(ctx, workDay); err != nil {
+	err = s.audit.InTx(ctx, func(ctx context.Context) error {
+		if err := s.workDayRepo.Create(ctx, workDay); err != nil {
+			return err
+		}
+		return s.audit.Record(ctx, &models.AuditLog{
+			EntityType: models.AuditEntityWorkDay,
+			EntityID:   workDay.ID,
+			ProjectID:  &workDay.ProjectID,
+			LabourID:   &workDay.LabourID,
+			Action:     models.AuditActionCreate,
+		}, nil, workDay)
+	})
+	if err != nil {
 		return nil, err
 	}
+	s.budget.Check(ctx, projectID)
 
 	return workDay, nil
 }
 
+// BulkUpsert marks attendance for many labours on one date. Valid entries are
+// saved together in one transaction; re-marking a day updates the existing
+// rows. Invalid entries are reported per row and do not block the others.
+func (s *WorkDayService) BulkUpsert(ctx context.Context, projectID uuid.UUID, req *models.BulkAttendanceRequest) (*models.BulkAttendanceResponse, error) {
+	workDate, err := time.Parse("2006-01-02", req.WorkDate)
+	if err != nil {
+		return nil, models.ErrInvalidDate
+	}
+
+	if err := ensurePeriodOpen(ctx, s.projectRepo, projectID, workDate); err != nil {
+		return nil, err
+	}
+
+	// Load assignments once instead of checking each labour separately
+	assigned, err := s.labourRepo.GetByProjectID(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+	assignedIDs := make(map[uuid.UUID]bool, len(assigned))
+	for _, l := range assigned {
+		assignedIDs[l.ID] = true
+	}
+
+	response := &models.BulkAttendanceResponse{
+		WorkDate: workDate,
+		Results:  make([]models.BulkAttendanceResult, len(req.Entries)),
+	}
+
+	seen := make(map[uuid.UUID]bool, len(req.Entries))
+	var workDays []*models.WorkDay
+	var rowIndexes []int
+	for i, entry := range req.Entries {
+		result := &response.Results[i]
+		result.LabourID = entry.LabourID
+
+		if seen[entry.LabourID] {
+			result.Result = models.BulkResultError
+			result.Error = "duplicate labour in request"
+			continue
+		}
+		seen[entry.LabourID] = true
+
+		if !assignedIDs[entry.LabourID] {
+			result.Result = models.BulkResultError
+			result.Error = "labour not assigned to this project"
+			continue
+		}
+
+		workDay := &models.WorkDay{
+			ProjectID:     projectID,
+			LabourID:      entry.LabourID,
+			WorkDate:      workDate,
+			Status:        entry.Status,
+			OvertimeHours: entry.OvertimeHours,
+			CheckIn:       entry.CheckIn,
+			CheckOut:      entry.CheckOut,
+			Notes:         entry.Notes,
+		}
+		if err := workDay.Validate(); err != nil {
+			result.Result = models.BulkResultError
+			result.Error = err.Error()
+			continue
+		}
+
+		workDays = append(workDays, workDay)
+		rowIndexes = append(rowIndexes, i)
+	}
+
+	if len(workDays) > 0 {
+		err := s.audit.InTx(ctx, func(ctx context.Context) error {
+			// The rows being overwritten are kept as the audit before snapshots
+			existing, err := s.workDayRepo.GetByProjectAndDate(ctx, projectID, workDate)
+			if err != nil {
+				return err
+			}
+			previous := make(map[uuid.UUID]*models.WorkDay, len(existing))
+			for k := range existing {
+				previous[existing[k].LabourID] = &existing[k].WorkDay
+			}
+
+			inserted, err := s.workDayRepo.UpsertMany(ctx, workDays)
+			if err != nil {
+				return err
+			}
+			for j, workDay := range workDays {
+				result := &response.Results[rowIndexes[j]]
+				result.WorkDay = workDay
+				action := models.AuditActionUpdate
+				var before any
+				if inserted[j] {
+					result.Result = models.BulkResultCreated
+					action = models.AuditActionCreate
+				} else {
+					result.Result = models.BulkResultUpdated
+					if prev, ok := previous[workDay.LabourID]; ok {
+						before = prev
+					}
+				}
+				err := s.audit.Record(ctx, &models.AuditLog{
+					EntityType: models.AuditEntityWorkDay,
+					EntityID:   workDay.ID,
+					ProjectID:  &workDay.ProjectID,
+					LabourID:   &workDay.LabourID,
+					Action:     action,
+				}, before, workDay)
+				if err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		s.budget.Check(ctx, projectID)
+	}
+
+	for _, result := range response.Results {
+		switch result.Result {
+		case models.BulkResultCreated:
+			response.Created++
+		case models.BulkResultUpdated:
+			response.Updated++
+		default:
+			response.Failed++
+		}
+	}
+
+	return response, nil
+}
+
 // GetByID retrieves a work day by ID
 func (s *WorkDayService) GetByID(ctx context.Context, id uuid.UUID) (*models.WorkDay, error) {
 	return s.workDayRepo.GetByID(ctx, id)
@@ -90,21 +247,67 @@ func (s *WorkDayService) Update(ctx context.Context, id uuid.UUID, req *models.U
 		return nil, err
 	}
 
+	if err := ensurePeriodOpen(ctx, s.projectRepo, workDay.ProjectID, workDay.WorkDate); err != nil {
+		return nil, err
+	}
+
+	before := *workDay
 	workDay.Status = req.Status
+	workDay.OvertimeHours = req.OvertimeHours
+	workDay.CheckIn = req.CheckIn
+	workDay.CheckOut = req.CheckOut
 	workDay.Notes = req.Notes
 
 	if err := workDay.Validate(); err != nil {
 		return nil, err
 	}
 
-	if err := s.workDayRepo.Update(ctx, workDay); err != nil {
+	err = s.audit.InTx(ctx, func(ctx context.Context) error {
+		if err := s.workDayRepo.Update(ctx, workDay); err != nil {
+			return err
+		}
+		return s.audit.Record(ctx, &models.AuditLog{
+			EntityType: models.AuditEntityWorkDay,
+			EntityID:   workDay.ID,
+			ProjectID:  &workDay.ProjectID,
+			LabourID:   &workDay.LabourID,
+			Action:     models.AuditActionUpdate,
+		}, before, workDay)
+	})
+	if err != nil {
 		return nil, err
 	}
+	s.budget.Check(ctx, workDay.ProjectID)
 
 	return workDay, nil
 }
 
 // Delete deletes a work day record
 func (s *WorkDayService) Delete(ctx context.Context, id uuid.UUID) error {
-	return s.workDayRepo.Delete(ctx, id)
+	workDay, err := s.workDayRepo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if err := ensurePeriodOpen(ctx, s.projectRepo, workDay.ProjectID, workDay.WorkDate); err != nil {
+		return err
+	}
+
+	err = s.audit.InTx(ctx, func(ctx context.Context) error {
+		if err := s.workDayRepo.Delete(ctx, id); err != nil {
+			return err
+		}
+		return s.audit.Record(ctx, &models.AuditLog{
+			EntityType: models.AuditEntityWorkDay,
+			EntityID:   workDay.ID,
+			ProjectID:  &workDay.ProjectID,
+			LabourID:   &workDay.LabourID,
+			Action:     models.AuditActionDelete,
+		}, workDay, nil)
+	})
+	if err != nil {
+		return err
+	}
+
+	return nil
 }
@@ -0,0 +1,82 @@
+package otp
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// storedCode represents an OTP held in memory
+type storedCode struct {
+	CodeHash  string
+	ExpiresAt time.Time
+	Attempts  int
+}
+
+// MemoryStore implements the Store interface in process memory. It is meant
+// for tests and single-instance development; codes are lost on restart.
+type MemoryStore struct {
+	mu    sync.Mutex
+	codes map[string]*storedCode
+}
+
+// NewMemoryStore creates a new in-memory OTP store
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{codes: make(map[string]*storedCode)}
+}
+
+// Save replaces any earlier code for the phone
+func (s *MemoryStore) Save(ctx context.Context, phone string, codeHash string, expiresAt time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.codes[phone] = &storedCode{
+		CodeHash:  codeHash,
+		ExpiresAt: expiresAt,
+	}
+	return nil
+}
+
+// Consume checks the code and removes it on success, so it can only be used once
+func (s *MemoryStore) Consume(ctx context.Context, phone string, codeHash string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stored, exists := s.codes[phone]
+	if !exists {
+		return false, nil
+	}
+
+	if time.Now().After(stored.ExpiresAt) {
+		delete(s.codes, phone)
+		return false, nil
+	}
+
+	stored.Attempts++
+	if stored.CodeHash != codeHash {
+		if stored.Attempts >= MaxVerifyAttempts {
+			delete(s.codes, phone)
+			return false, ErrTooManyAttempts
+		}
+		return false, nil
+	}
+
+	delete(s.codes, phone)
+	return true, nil
+}
+
+// DeleteExpired removes all expired codes
+func (s *MemoryStore) DeleteExpired(ctx context.Context) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var removed int64
+	now := time.Now()
+	for phone, stored := range s.codes {
+		if now.After(stored.ExpiresAt) {
+			delete(s.codes, phone)
+			removed++
+		}
+	}
+	return removed, nil
+}
@@ -6,39 +6,33 @@ import (
 	"fmt"
 	"log"
 	"math/big"
-	"sync"
 	"time"
 )
 
-// MockOTPStore represents a stored OTP
-type MockOTPStore struct {
-	Code      string
-	ExpiresAt time.Time
-}
+// mockSecret hashes mock codes; mock codes are not secret anyway
+const mockSecret = "mock-otp"
 
 // MockProvider implements the Provider interface for development
 type MockProvider struct {
-	mu     sync.RWMutex
-	otps   map[string]*MockOTPStore
+	store       Store
 	useFixedOTP bool
 }
 
-// NewMockProvider creates a new mock OTP provider
+// NewMockProvider creates a new mock OTP provider backed by an in-memory store
 func NewMockProvider(useFixedOTP bool) *MockProvider {
-	provider := &MockProvider{
-		otps:   make(map[string]*MockOTPStore),
+	return NewMockProviderWithStore(NewMemoryStore(), useFixedOTP)
+}
+
+// NewMockProviderWithStore creates a new mock OTP provider backed by store
+func NewMockProviderWithStore(store Store, useFixedOTP bool) *MockProvider {
+	return &MockProvider{
+		store:       store,
 		useFixedOTP: useFixedOTP,
 	}
-	// Start cleanup goroutine
-	go provider.cleanupExpired()
-	return provider
 }
 
-// SendOTP sends a mock OTP (stores it in memory)
+// SendOTP sends a mock OTP (logs it instead of delivering it)
 func (m *MockProvider) SendOTP(ctx context.Context, phone string) (string, error) {
-	m.mu.Lock()
-	defer m.mu.Unlock()
-
 	var code string
 	if m.useFixedOTP {
 		// Fixed OTP for testing
@@ -48,9 +42,8 @@ func (m *MockProvider) SendOTP(ctx context.Context, phone string) (string, error
 		code = generateOTP()
 	}
 
-	m.otps[phone] = &MockOTPStore{
-		Code:      code,
-		ExpiresAt: time.Now().Add(5 * time.Minute),
+	if err := m.store.Save(ctx, phone, HashCode(mockSecret, phone, code), time.Now().Add(codeTTL)); err != nil {
+		return "", err
 	}
 
 	log.Printf("[MOCK OTP] Sent OTP %s to phone %s", code, phone)
@@ -59,52 +52,30 @@ func (m *MockProvider) SendOTP(ctx context.Context, phone string) (string, error
 
 // VerifyOTP verifies the mock OTP
 func (m *MockProvider) VerifyOTP(ctx context.Context, phone string, code string) (bool, error) {
-	m.mu.Lock()
-	defer m.mu.Unlock()
-
-	stored, exists := m.otps[phone]
-	if !exists {
-		return false, nil
-	}
-
-	if time.Now().After(stored.ExpiresAt) {
-		delete(m.otps, phone)
-		return false, nil
-	}
-
-	if stored.Code != code {
-		return false, nil
+	valid, err := m.store.Consume(ctx, phone, HashCode(mockSecret, phone, code))
+	if err != nil || !valid {
+		return false, err
 	}
 
-	// OTP verified, remove it
-	delete(m.otps, phone)
 	log.Printf("[MOCK OTP] Verified OTP for phone %s", phone)
 	return true, nil
 }
 
-// cleanupExpired periodically removes expired OTPs
-func (m *MockProvider) cleanupExpired() {
-	ticker := time.NewTicker(1 * time.Minute)
-	defer ticker.Stop()
-
-	for range ticker.C {
-		m.mu.Lock()
-		now := time.Now()
-		for phone, stored := range m.otps {
-			if now.After(stored.ExpiresAt) {
-				delete(m.otps, phone)
-			}
-		}
-		m.mu.Unlock()
+// generateOTP generates a random 6-digit OTP
+func generateOTP() string {
+	code, err := randomCode()
+	if err != nil {
+		return "123456" // Fallback
 	}
+	return code
 }
 
-// generateOTP generates a random 6-digit OTP
-func generateOTP() string {
+// randomCode generates a random 6-digit code from a cryptographic source
+func randomCode() (string, error) {
 	max := big.NewInt(1000000)
 	n, err := rand.Int(rand.Reader, max)
 	if err != nil {
-		return "123456" // Fallback
+		return "", err
 	}
-	return fmt.Sprintf("%06d", n.Int64())
+	return fmt.Sprintf("%06d", n.Int64()), nil
 }
@@ -0,0 +1,93 @@
+package otp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// MSG91Sender delivers OTP codes as SMS through the MSG91 Flow API. The
+// template must contain an ##otp## variable.
+type MSG91Sender struct {
+	// BaseURL is the MSG91 API root; overridable for tests
+	BaseURL    string
+	authKey    string
+	templateID string
+	client     *http.Client
+}
+
+// NewMSG91Sender creates a new MSG91 SMS sender
+func NewMSG91Sender(authKey, templateID string) *MSG91Sender {
+	return &MSG91Sender{
+		BaseURL:    "https://control.msg91.com",
+		authKey:    authKey,
+		templateID: templateID,
+		client:     &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// msg91Recipient represents one recipient of a flow message
+type msg91Recipient struct {
+	Mobiles string `json:"mobiles"`
+	OTP     string `json:"otp"`
+}
+
+// msg91Request represents the flow API request body
+type msg91Request struct {
+	TemplateID string           `json:"template_id"`
+	ShortURL   string           `json:"short_url"`
+	Recipients []msg91Recipient `json:"recipients"`
+}
+
+// msg91Response represents the flow API response body
+type msg91Response struct {
+	Type    string `json:"type"`
+	Message string `json:"message"`
+}
+
+// Send sends the code to the phone as an SMS
+func (s *MSG91Sender) Send(ctx context.Context, phone string, code string) error {
+	body, err := json.Marshal(msg91Request{
+		TemplateID: s.templateID,
+		ShortURL:   "0",
+		Recipients: []msg91Recipient{{
+			// MSG91 expects the number with country code but without "+"
+			Mobiles: strings.TrimPrefix(phone, "+"),
+			OTP:     code,
+		}},
+	})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.BaseURL+"/api/v5/flow/", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("authkey", s.authKey)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("msg91 request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	// MSG91 can report failures with a 200 status, so check the body as well
+	var result msg91Response
+	decodeErr := json.NewDecoder(resp.Body).Decode(&result)
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 || result.Type == "error" {
+		if decodeErr == nil && result.Message != "" {
+			return fmt.Errorf("msg91 returned %d: %s", resp.StatusCode, result.Message)
+		}
+		return fmt.Errorf("msg91 returned %d", resp.StatusCode)
+	}
+
+	return nil
+}
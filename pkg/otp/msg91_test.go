@@ -0,0 +1,65 @@
+package otp
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMSG91Sender_Send(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("posts the flow request", func(t *testing.T) {
+		var gotPath, gotKey string
+		var gotBody msg91Request
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			gotPath = r.URL.Path
+			gotKey = r.Header.Get("authkey")
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&gotBody))
+			w.Write([]byte(`{"type": "success", "message": "3763646c3058373530393938"}`))
+		}))
+		defer srv.Close()
+
+		sender := NewMSG91Sender("auth-key", "tmpl-1")
+		sender.BaseURL = srv.URL
+
+		require.NoError(t, sender.Send(ctx, "+919876543210", "482913"))
+		assert.Equal(t, "/api/v5/flow/", gotPath)
+		assert.Equal(t, "auth-key", gotKey)
+		assert.Equal(t, "tmpl-1", gotBody.TemplateID)
+		require.Len(t, gotBody.Recipients, 1)
+		assert.Equal(t, "919876543210", gotBody.Recipients[0].Mobiles)
+		assert.Equal(t, "482913", gotBody.Recipients[0].OTP)
+	})
+
+	t.Run("treats an error body with 200 status as failure", func(t *testing.T) {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(`{"type": "error", "message": "Invalid template"}`))
+		}))
+		defer srv.Close()
+
+		sender := NewMSG91Sender("auth-key", "tmpl-1")
+		sender.BaseURL = srv.URL
+
+		err := sender.Send(ctx, "+919876543210", "482913")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "Invalid template")
+	})
+
+	t.Run("fails on non-2xx status", func(t *testing.T) {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusUnauthorized)
+		}))
+		defer srv.Close()
+
+		sender := NewMSG91Sender("wrong-key", "tmpl-1")
+		sender.BaseURL = srv.URL
+
+		assert.Error(t, sender.Send(ctx, "+919876543210", "482913"))
+	})
+}
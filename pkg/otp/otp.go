@@ -1,6 +1,9 @@
 package otp
 
-import "context"
+import (
+	"context"
+	"fmt"
+)
 
 // Provider defines the interface for OTP providers
 type Provider interface {
@@ -9,3 +12,14 @@ type Provider interface {
 	// VerifyOTP verifies the OTP for the given phone number
 	VerifyOTP(ctx context.Context, phone string, code string) (bool, error)
 }
+
+// Sender delivers an OTP code to a phone number over some channel (SMS, webhook)
+type Sender interface {
+	// Send delivers the code to the given phone number
+	Send(ctx context.Context, phone string, code string) error
+}
+
+// message builds the SMS text for a code
+func message(code string) string {
+	return fmt.Sprintf("%s is your Labour Thekedar login code. It expires in %d minutes.", code, int(codeTTL.Minutes()))
+}
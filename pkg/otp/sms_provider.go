@@ -0,0 +1,48 @@
+package otp
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// SMSProvider implements the Provider interface by generating random codes,
+// keeping their hashes in a Store and delivering them through a Sender
+type SMSProvider struct {
+	sender Sender
+	store  Store
+	secret string
+}
+
+// NewSMSProvider creates a new OTP provider that delivers codes through
+// sender and keeps them in store, hashed with secret
+func NewSMSProvider(sender Sender, store Store, secret string) *SMSProvider {
+	return &SMSProvider{
+		sender: sender,
+		store:  store,
+		secret: secret,
+	}
+}
+
+// SendOTP generates a code, stores it and delivers it to the phone
+func (p *SMSProvider) SendOTP(ctx context.Context, phone string) (string, error) {
+	code, err := randomCode()
+	if err != nil {
+		return "", fmt.Errorf("failed to generate OTP: %w", err)
+	}
+
+	if err := p.sender.Send(ctx, phone, code); err != nil {
+		return "", fmt.Errorf("failed to deliver OTP: %w", err)
+	}
+
+	// Only store the code once it has actually been delivered
+	if err := p.store.Save(ctx, phone, HashCode(p.secret, phone, code), time.Now().Add(codeTTL)); err != nil {
+		return "", fmt.Errorf("failed to store OTP: %w", err)
+	}
+	return code, nil
+}
+
+// VerifyOTP verifies the code for the phone; a code can only be used once
+func (p *SMSProvider) VerifyOTP(ctx context.Context, phone string, code string) (bool, error) {
+	return p.store.Consume(ctx, phone, HashCode(p.secret, phone, code))
+}
@@ -0,0 +1,65 @@
+package otp
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"log"
+	"time"
+)
+
+// codeTTL is how long a sent OTP stays valid
+const codeTTL = 5 * time.Minute
+
+// MaxVerifyAttempts is how many wrong codes are accepted before the current
+// code is invalidated and a new one must be requested
+const MaxVerifyAttempts = 5
+
+// ErrTooManyAttempts is returned when a code has been invalidated after
+// MaxVerifyAttempts wrong guesses
+var ErrTooManyAttempts = errors.New("too many wrong OTP attempts")
+
+// Store persists OTP codes. Codes are only ever stored as hashes (see HashCode).
+type Store interface {
+	// Save stores a new code for the phone, invalidating any earlier unused code
+	Save(ctx context.Context, phone string, codeHash string, expiresAt time.Time) error
+	// Consume checks codeHash against the phone's active code and counts the
+	// attempt. A matching code is marked used so it cannot be used again. The
+	// wrong guess that reaches MaxVerifyAttempts invalidates the code and
+	// returns ErrTooManyAttempts.
+	Consume(ctx context.Context, phone string, codeHash string) (bool, error)
+	// DeleteExpired removes expired and used codes, returning how many were removed
+	DeleteExpired(ctx context.Context) (int64, error)
+}
+
+// HashCode returns the hex HMAC-SHA256 of a phone's code, so a leaked store
+// does not reveal usable codes
+func HashCode(secret, phone, code string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(phone + ":" + code))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// RunCleanup deletes expired codes from the store every interval until ctx is done
+func RunCleanup(ctx context.Context, store Store, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			removed, err := store.DeleteExpired(ctx)
+			if err != nil {
+				log.Printf("[OTP] Failed to clean up expired codes: %v", err)
+				continue
+			}
+			if removed > 0 {
+				log.Printf("[OTP] Removed %d expired codes", removed)
+			}
+		}
+	}
+}
@@ -0,0 +1,75 @@
+package otp
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHashCode(t *testing.T) {
+	t.Run("is deterministic", func(t *testing.T) {
+		assert.Equal(t, HashCode("s", "+911111111111", "123456"), HashCode("s", "+911111111111", "123456"))
+	})
+
+	t.Run("differs per phone and secret", func(t *testing.T) {
+		base := HashCode("s", "+911111111111", "123456")
+		assert.NotEqual(t, base, HashCode("s", "+922222222222", "123456"))
+		assert.NotEqual(t, base, HashCode("other", "+911111111111", "123456"))
+		assert.NotContains(t, base, "123456")
+	})
+}
+
+func TestMemoryStore(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("new code replaces the previous one", func(t *testing.T) {
+		store := NewMemoryStore()
+		phone := "+911111111111"
+		require.NoError(t, store.Save(ctx, phone, "old", time.Now().Add(time.Minute)))
+		require.NoError(t, store.Save(ctx, phone, "new", time.Now().Add(time.Minute)))
+
+		valid, err := store.Consume(ctx, phone, "old")
+		require.NoError(t, err)
+		assert.False(t, valid)
+
+		valid, err = store.Consume(ctx, phone, "new")
+		require.NoError(t, err)
+		assert.True(t, valid)
+	})
+
+	t.Run("rejects and removes expired codes", func(t *testing.T) {
+		store := NewMemoryStore()
+		phone := "+922222222222"
+		require.NoError(t, store.Save(ctx, phone, "hash", time.Now().Add(-time.Second)))
+
+		removed, err := store.DeleteExpired(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), removed)
+
+		valid, err := store.Consume(ctx, phone, "hash")
+		require.NoError(t, err)
+		assert.False(t, valid)
+	})
+
+	t.Run("invalidates the code after too many wrong guesses", func(t *testing.T) {
+		store := NewMemoryStore()
+		phone := "+933333333333"
+		require.NoError(t, store.Save(ctx, phone, "right", time.Now().Add(time.Minute)))
+
+		for i := 1; i < MaxVerifyAttempts; i++ {
+			valid, err := store.Consume(ctx, phone, "wrong")
+			require.NoError(t, err)
+			assert.False(t, valid)
+		}
+
+		_, err := store.Consume(ctx, phone, "wrong")
+		assert.ErrorIs(t, err, ErrTooManyAttempts)
+
+		valid, err := store.Consume(ctx, phone, "right")
+		require.NoError(t, err)
+		assert.False(t, valid, "locked code must not verify")
+	})
+}
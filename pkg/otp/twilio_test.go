@@ -0,0 +1,75 @@
+package otp
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTwilioSender_Send(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("posts the message with basic auth", func(t *testing.T) {
+		var gotPath, gotUser, gotPass, gotTo, gotFrom, gotBody string
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			gotPath = r.URL.Path
+			gotUser, gotPass, _ = r.BasicAuth()
+			require.NoError(t, r.ParseForm())
+			gotTo = r.PostForm.Get("To")
+			gotFrom = r.PostForm.Get("From")
+			gotBody = r.PostForm.Get("Body")
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{"sid": "SM123", "status": "queued"}`))
+		}))
+		defer srv.Close()
+
+		sender := NewTwilioSender("AC123", "secret-token", "+15005550006")
+		sender.BaseURL = srv.URL
+
+		err := sender.Send(ctx, "+919876543210", "482913")
+		require.NoError(t, err)
+		assert.Equal(t, "/2010-04-01/Accounts/AC123/Messages.json", gotPath)
+		assert.Equal(t, "AC123", gotUser)
+		assert.Equal(t, "secret-token", gotPass)
+		assert.Equal(t, "+919876543210", gotTo)
+		assert.Equal(t, "+15005550006", gotFrom)
+		assert.Contains(t, gotBody, "482913")
+	})
+
+	t.Run("uses a messaging service SID", func(t *testing.T) {
+		var gotService, gotFrom string
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			require.NoError(t, r.ParseForm())
+			gotService = r.PostForm.Get("MessagingServiceSid")
+			gotFrom = r.PostForm.Get("From")
+			w.WriteHeader(http.StatusCreated)
+		}))
+		defer srv.Close()
+
+		sender := NewTwilioSender("AC123", "secret-token", "MG999")
+		sender.BaseURL = srv.URL
+
+		require.NoError(t, sender.Send(ctx, "+919876543210", "482913"))
+		assert.Equal(t, "MG999", gotService)
+		assert.Empty(t, gotFrom)
+	})
+
+	t.Run("returns the API error message", func(t *testing.T) {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"code": 21211, "message": "The 'To' number is not a valid phone number."}`))
+		}))
+		defer srv.Close()
+
+		sender := NewTwilioSender("AC123", "secret-token", "+15005550006")
+		sender.BaseURL = srv.URL
+
+		err := sender.Send(ctx, "bogus", "482913")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "not a valid phone number")
+	})
+}
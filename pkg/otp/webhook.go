@@ -0,0 +1,81 @@
+package otp
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// SignatureHeader carries the hex HMAC-SHA256 of the webhook body
+const SignatureHeader = "X-OTP-Signature"
+
+// WebhookSender delivers OTP codes by POSTing them to an HTTP endpoint, for
+// SMS gateways without a dedicated adapter
+type WebhookSender struct {
+	url    string
+	secret string
+	client *http.Client
+}
+
+// NewWebhookSender creates a new webhook sender. When secret is set, each
+// request is signed in the X-OTP-Signature header.
+func NewWebhookSender(url, secret string) *WebhookSender {
+	return &WebhookSender{
+		url:    url,
+		secret: secret,
+		client: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// WebhookPayload represents the JSON body sent to the webhook
+type WebhookPayload struct {
+	Phone   string `json:"phone"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// Send posts the code to the webhook; any 2xx response counts as delivered
+func (s *WebhookSender) Send(ctx context.Context, phone string, code string) error {
+	body, err := json.Marshal(WebhookPayload{
+		Phone:   phone,
+		Code:    code,
+		Message: message(code),
+	})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if s.secret != "" {
+		req.Header.Set(SignatureHeader, Sign(s.secret, body))
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("webhook request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook returned %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+// Sign returns the hex HMAC-SHA256 of body using secret
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,99 @@
+package otp
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebhookSender_Send(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("posts a signed payload", func(t *testing.T) {
+		var gotBody []byte
+		var gotSignature string
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			gotBody, _ = io.ReadAll(r.Body)
+			gotSignature = r.Header.Get(SignatureHeader)
+			w.WriteHeader(http.StatusAccepted)
+		}))
+		defer srv.Close()
+
+		sender := NewWebhookSender(srv.URL, "shh")
+		require.NoError(t, sender.Send(ctx, "+919876543210", "482913"))
+
+		var payload WebhookPayload
+		require.NoError(t, json.Unmarshal(gotBody, &payload))
+		assert.Equal(t, "+919876543210", payload.Phone)
+		assert.Equal(t, "482913", payload.Code)
+		assert.Equal(t, Sign("shh", gotBody), gotSignature)
+	})
+
+	t.Run("omits the signature without a secret", func(t *testing.T) {
+		var gotSignature string
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			gotSignature = r.Header.Get(SignatureHeader)
+		}))
+		defer srv.Close()
+
+		require.NoError(t, NewWebhookSender(srv.URL, "").Send(ctx, "+919876543210", "482913"))
+		assert.Empty(t, gotSignature)
+	})
+
+	t.Run("fails on non-2xx status", func(t *testing.T) {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusBadGateway)
+		}))
+		defer srv.Close()
+
+		assert.Error(t, NewWebhookSender(srv.URL, "").Send(ctx, "+919876543210", "482913"))
+	})
+}
+
+func TestSMSProvider(t *testing.T) {
+	ctx := context.Background()
+
+	var delivered string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload WebhookPayload
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&payload))
+		delivered = payload.Code
+	}))
+	defer srv.Close()
+
+	provider := NewSMSProvider(NewWebhookSender(srv.URL, ""), NewMemoryStore(), "secret")
+
+	t.Run("verifies the delivered code once", func(t *testing.T) {
+		phone := "+919876543210"
+		code, err := provider.SendOTP(ctx, phone)
+		require.NoError(t, err)
+		assert.Len(t, code, 6)
+		assert.Equal(t, code, delivered)
+
+		valid, err := provider.VerifyOTP(ctx, phone, delivered)
+		require.NoError(t, err)
+		assert.True(t, valid)
+
+		valid, err = provider.VerifyOTP(ctx, phone, delivered)
+		require.NoError(t, err)
+		assert.False(t, valid)
+	})
+
+	t.Run("does not store a code that failed to deliver", func(t *testing.T) {
+		failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+		}))
+		defer failing.Close()
+
+		p := NewSMSProvider(NewWebhookSender(failing.URL, ""), NewMemoryStore(), "secret")
+		code, err := p.SendOTP(ctx, "+919876543211")
+		require.Error(t, err)
+		assert.Empty(t, code)
+	})
+}